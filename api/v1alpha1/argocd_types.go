@@ -25,6 +25,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 func init() {
@@ -105,6 +107,25 @@ type ArgoCDApplicationControllerSpec struct {
 
 	// Env lets you specify environment for application controller pods
 	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// PodTemplateOverride is a strategic merge patch that is applied to the pod template of the Application Controller StatefulSet.
+	//+kubebuilder:pruning:PreserveUnknownFields
+	//+kubebuilder:validation:Type=object
+	PodTemplateOverride *runtime.RawExtension `json:"podTemplateOverride,omitempty"`
+
+	// Scheduling defines the scheduling options for the pods of the Application Controller StatefulSet.
+	Scheduling *ArgoCDSchedulingSpec `json:"scheduling,omitempty"`
+
+	// SecurityContext defines the security contexts of the pods and containers of the Application Controller StatefulSet.
+	SecurityContext *ArgoCDSecurityContextSpec `json:"securityContext,omitempty"`
+
+	// ImagePullPolicy is the pull policy for the container images of the Application Controller StatefulSet. Defaults
+	// to the image pull policy of the ArgoCD.
+	//+kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// PodDisruptionBudget defines the PodDisruptionBudget of the Application Controller.
+	PodDisruptionBudget *ArgoCDPodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
 }
 
 // ArgoCDApplicationControllerShardSpec defines the options available for enabling sharding for the Application Controller component.
@@ -131,6 +152,25 @@ type ArgoCDApplicationSet struct {
 
 	// LogLevel describes the log level that should be used by the ApplicationSet controller. Defaults to ArgoCDDefaultLogLevel if not set.  Valid options are debug,info, error, and warn.
 	LogLevel string `json:"logLevel,omitempty"`
+
+	// PodTemplateOverride is a strategic merge patch that is applied to the pod template of the ApplicationSet controller Deployment.
+	//+kubebuilder:pruning:PreserveUnknownFields
+	//+kubebuilder:validation:Type=object
+	PodTemplateOverride *runtime.RawExtension `json:"podTemplateOverride,omitempty"`
+
+	// Scheduling defines the scheduling options for the pods of the ApplicationSet controller Deployment.
+	Scheduling *ArgoCDSchedulingSpec `json:"scheduling,omitempty"`
+
+	// SecurityContext defines the security contexts of the pods and containers of the ApplicationSet controller Deployment.
+	SecurityContext *ArgoCDSecurityContextSpec `json:"securityContext,omitempty"`
+
+	// ImagePullPolicy is the pull policy for the container images of the ApplicationSet controller Deployment. Defaults
+	// to the image pull policy of the ArgoCD.
+	//+kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// PodDisruptionBudget defines the PodDisruptionBudget of the ApplicationSet controller.
+	PodDisruptionBudget *ArgoCDPodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
 }
 
 // ArgoCDCASpec defines the CA options for ArgCD.
@@ -172,6 +212,25 @@ type ArgoCDDexSpec struct {
 	// Version is the Dex container image tag.
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Version",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:fieldGroup:Dex","urn:alm:descriptor:com.tectonic.ui:text"}
 	Version string `json:"version,omitempty"`
+
+	// PodTemplateOverride is a strategic merge patch that is applied to the pod template of the Dex Deployment.
+	//+kubebuilder:pruning:PreserveUnknownFields
+	//+kubebuilder:validation:Type=object
+	PodTemplateOverride *runtime.RawExtension `json:"podTemplateOverride,omitempty"`
+
+	// Scheduling defines the scheduling options for the pods of the Dex Deployment.
+	Scheduling *ArgoCDSchedulingSpec `json:"scheduling,omitempty"`
+
+	// SecurityContext defines the security contexts of the pods and containers of the Dex Deployment.
+	SecurityContext *ArgoCDSecurityContextSpec `json:"securityContext,omitempty"`
+
+	// ImagePullPolicy is the pull policy for the container images of the Dex Deployment. Defaults to the image pull
+	// policy of the ArgoCD.
+	//+kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// PodDisruptionBudget defines the PodDisruptionBudget of the Dex.
+	PodDisruptionBudget *ArgoCDPodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
 }
 
 // ArgoCDDexOAuthSpec defines the desired state for the Dex OAuth configuration.
@@ -211,6 +270,32 @@ type ArgoCDGrafanaSpec struct {
 	// Version is the Grafana container image tag.
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Version",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:fieldGroup:Grafana","urn:alm:descriptor:com.tectonic.ui:text"}
 	Version string `json:"version,omitempty"`
+
+	// PodTemplateOverride is a strategic merge patch that is applied to the pod template of the Grafana Deployment.
+	//+kubebuilder:pruning:PreserveUnknownFields
+	//+kubebuilder:validation:Type=object
+	PodTemplateOverride *runtime.RawExtension `json:"podTemplateOverride,omitempty"`
+
+	// Scheduling defines the scheduling options for the pods of the Grafana Deployment.
+	Scheduling *ArgoCDSchedulingSpec `json:"scheduling,omitempty"`
+
+	// SecurityContext defines the security contexts of the pods and containers of the Grafana Deployment.
+	SecurityContext *ArgoCDSecurityContextSpec `json:"securityContext,omitempty"`
+
+	// ImagePullPolicy is the pull policy for the container images of the Grafana Deployment. Defaults to the image pull
+	// policy of the ArgoCD.
+	//+kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+}
+
+// ArgoCDHealthChecksSpec defines the health checks from the catalog bundled with the operator that are enabled.
+type ArgoCDHealthChecksSpec struct {
+	// Include is the list of the names of the bundled health checks to enable, e.g. cert-manager or crossplane.
+	Include []string `json:"include,omitempty"`
+
+	// CrossplaneClaims is the list of the groups and kinds of the Crossplane claims and composite resources of your own
+	// definitions that the health check of the bundled crossplane catalog entry is applied to.
+	CrossplaneClaims []metav1.GroupKind `json:"crossplaneClaims,omitempty"`
 }
 
 // ArgoCDHASpec defines the desired state for High Availability support for Argo CD.
@@ -227,6 +312,25 @@ type ArgoCDHASpec struct {
 
 	// Resources defines the Compute Resources required by the container for HA.
 	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// PodTemplateOverride is a strategic merge patch that is applied to the pod template of the Redis HA Proxy Deployment.
+	//+kubebuilder:pruning:PreserveUnknownFields
+	//+kubebuilder:validation:Type=object
+	PodTemplateOverride *runtime.RawExtension `json:"podTemplateOverride,omitempty"`
+
+	// Scheduling defines the scheduling options for the pods of the Redis HA Proxy Deployment.
+	Scheduling *ArgoCDSchedulingSpec `json:"scheduling,omitempty"`
+
+	// SecurityContext defines the security contexts of the pods and containers of the Redis HA Proxy Deployment.
+	SecurityContext *ArgoCDSecurityContextSpec `json:"securityContext,omitempty"`
+
+	// ImagePullPolicy is the pull policy for the container images of the Redis HA Proxy Deployment. Defaults to the
+	// image pull policy of the ArgoCD.
+	//+kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// PodDisruptionBudget defines the PodDisruptionBudget of the Redis HA Proxy.
+	PodDisruptionBudget *ArgoCDPodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
 }
 
 // ArgoCDImportSpec defines the desired state for the ArgoCD import/restore process.
@@ -270,6 +374,40 @@ type ArgoCDList struct {
 	Items           []ArgoCD `json:"items"`
 }
 
+// ArgoCDPatchType is the type of a patch.
+type ArgoCDPatchType string
+
+const (
+	// ArgoCDPatchTypeJSON is a RFC 6902 JSON patch.
+	ArgoCDPatchTypeJSON ArgoCDPatchType = "json"
+
+	// ArgoCDPatchTypeStrategic is a strategic merge patch, as used by kubectl patch --type=strategic.
+	ArgoCDPatchTypeStrategic ArgoCDPatchType = "strategic"
+)
+
+// ArgoCDPatch defines a patch that is applied to resources generated by the operator.
+type ArgoCDPatch struct {
+	// Target selects the generated resources that the patch is applied to.
+	Target ArgoCDPatchTarget `json:"target"`
+
+	// Type is the type of the patch, json for a RFC 6902 JSON patch or strategic for a strategic merge patch. Defaults to strategic.
+	//+kubebuilder:validation:Enum=json;strategic
+	Type ArgoCDPatchType `json:"type,omitempty"`
+
+	// Patch is the patch in YAML or JSON.
+	Patch string `json:"patch"`
+}
+
+// ArgoCDPatchTarget selects the generated resources that a patch is applied to.
+type ArgoCDPatchTarget struct {
+	// Kind is the kind of the resources, e.g. Service.
+	Kind string `json:"kind"`
+
+	// Name is the name of the resource, either the full name, e.g. argocd-cm, or the suffix that follows the name of the
+	// ArgoCD, e.g. server for the example-argocd-server Service. All resources of the kind are selected if omitted.
+	Name string `json:"name,omitempty"`
+}
+
 // ArgoCDPrometheusSpec defines the desired state for the Prometheus component.
 type ArgoCDPrometheusSpec struct {
 	// Enabled will toggle Prometheus support globally for ArgoCD.
@@ -327,6 +465,25 @@ type ArgoCDRedisSpec struct {
 	// Version is the Redis container image tag.
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Version",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:fieldGroup:Redis","urn:alm:descriptor:com.tectonic.ui:text"}
 	Version string `json:"version,omitempty"`
+
+	// PodTemplateOverride is a strategic merge patch that is applied to the pod template of the Redis Deployment, or the Redis HA StatefulSet when HA is enabled.
+	//+kubebuilder:pruning:PreserveUnknownFields
+	//+kubebuilder:validation:Type=object
+	PodTemplateOverride *runtime.RawExtension `json:"podTemplateOverride,omitempty"`
+
+	// Scheduling defines the scheduling options for the pods of the Redis Deployment, or the Redis HA StatefulSet when HA is enabled.
+	Scheduling *ArgoCDSchedulingSpec `json:"scheduling,omitempty"`
+
+	// SecurityContext defines the security contexts of the pods and containers of the Redis Deployment, or the Redis HA StatefulSet when HA is enabled.
+	SecurityContext *ArgoCDSecurityContextSpec `json:"securityContext,omitempty"`
+
+	// ImagePullPolicy is the pull policy for the container images of the Redis Deployment, or the Redis HA StatefulSet
+	// when HA is enabled. Defaults to the image pull policy of the ArgoCD.
+	//+kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// PodDisruptionBudget defines the PodDisruptionBudget of the Redis HA StatefulSet.
+	PodDisruptionBudget *ArgoCDPodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
 }
 
 // ArgoCDRepoSpec defines the desired state for the Argo CD repo server component.
@@ -382,6 +539,25 @@ type ArgoCDRepoSpec struct {
 
 	// SidecarContainers defines the list of sidecar containers for the repo server deployment
 	SidecarContainers []corev1.Container `json:"sidecarContainers,omitempty"`
+
+	// PodTemplateOverride is a strategic merge patch that is applied to the pod template of the Repo Server Deployment.
+	//+kubebuilder:pruning:PreserveUnknownFields
+	//+kubebuilder:validation:Type=object
+	PodTemplateOverride *runtime.RawExtension `json:"podTemplateOverride,omitempty"`
+
+	// Scheduling defines the scheduling options for the pods of the Repo Server Deployment.
+	Scheduling *ArgoCDSchedulingSpec `json:"scheduling,omitempty"`
+
+	// SecurityContext defines the security contexts of the pods and containers of the Repo Server Deployment.
+	SecurityContext *ArgoCDSecurityContextSpec `json:"securityContext,omitempty"`
+
+	// ImagePullPolicy is the pull policy for the container images of the Repo Server Deployment. Defaults to the image
+	// pull policy of the ArgoCD.
+	//+kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// PodDisruptionBudget defines the PodDisruptionBudget of the Repo Server.
+	PodDisruptionBudget *ArgoCDPodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
 }
 
 // ArgoCDRouteSpec defines the desired state for an OpenShift Route.
@@ -472,6 +648,25 @@ type ArgoCDServerSpec struct {
 	// ExtraCommandArgs will not be added, if one of these commands is already part of the server command
 	// with same or different value.
 	ExtraCommandArgs []string `json:"extraCommandArgs,omitempty"`
+
+	// PodTemplateOverride is a strategic merge patch that is applied to the pod template of the Argo CD Server Deployment.
+	//+kubebuilder:pruning:PreserveUnknownFields
+	//+kubebuilder:validation:Type=object
+	PodTemplateOverride *runtime.RawExtension `json:"podTemplateOverride,omitempty"`
+
+	// Scheduling defines the scheduling options for the pods of the Argo CD Server Deployment.
+	Scheduling *ArgoCDSchedulingSpec `json:"scheduling,omitempty"`
+
+	// SecurityContext defines the security contexts of the pods and containers of the Argo CD Server Deployment.
+	SecurityContext *ArgoCDSecurityContextSpec `json:"securityContext,omitempty"`
+
+	// ImagePullPolicy is the pull policy for the container images of the Argo CD Server Deployment. Defaults to the
+	// image pull policy of the ArgoCD.
+	//+kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// PodDisruptionBudget defines the PodDisruptionBudget of the Argo CD Server.
+	PodDisruptionBudget *ArgoCDPodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
 }
 
 // ArgoCDServerServiceSpec defines the Service options for Argo CD Server component.
@@ -494,6 +689,13 @@ const (
 type ArgoCDSSOSpec struct {
 	// Image is the SSO container image.
 	Image string `json:"image,omitempty"`
+	// ImagePullPolicy is the pull policy for the SSO container image. Defaults to the image pull policy of the ArgoCD.
+	//+kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+	// PodTemplateOverride is a strategic merge patch that is applied to the pod template of the Keycloak Deployment, or the Keycloak DeploymentConfig on OpenShift.
+	//+kubebuilder:pruning:PreserveUnknownFields
+	//+kubebuilder:validation:Type=object
+	PodTemplateOverride *runtime.RawExtension `json:"podTemplateOverride,omitempty"`
 	// Provider installs and configures the given SSO Provider with Argo CD.
 	Provider SSOProviderType `json:"provider,omitempty"`
 	// Resources defines the Compute Resources required by the container for SSO.
@@ -512,6 +714,153 @@ type KustomizeVersionSpec struct {
 	Path string `json:"path,omitempty"`
 }
 
+// ResourceOverride customizes the behavior of Argo CD for resources of a group and kind.
+type ResourceOverride struct {
+	// Group is the API group of the resources, empty for the core group.
+	Group string `json:"group,omitempty"`
+
+	// Kind is the kind of the resources.
+	Kind string `json:"kind"`
+
+	// HealthLua is a Lua script that assesses the health of the resources.
+	HealthLua string `json:"healthLua,omitempty"`
+
+	// UseOpenLibs gives the health assessment script access to the standard Lua libraries.
+	UseOpenLibs bool `json:"useOpenLibs,omitempty"`
+
+	// Actions defines the custom actions of the resources.
+	Actions *ResourceActions `json:"actions,omitempty"`
+
+	// IgnoreDifferences defines the fields of the resources that are ignored when comparing them to the desired state.
+	IgnoreDifferences *ResourceIgnoreDifferences `json:"ignoreDifferences,omitempty"`
+
+	// KnownTypeFields defines the types of fields of the resources, for fields of custom resources that embed
+	// Kubernetes types, so that they are normalized when diffing.
+	KnownTypeFields []KnownTypeField `json:"knownTypeFields,omitempty"`
+}
+
+// ResourceActions defines the custom actions of resources.
+type ResourceActions struct {
+	// DiscoveryLua is a Lua script that returns the actions that are available for a resource.
+	DiscoveryLua string `json:"discoveryLua,omitempty"`
+
+	// Definitions defines the actions.
+	Definitions []ResourceAction `json:"definitions,omitempty"`
+}
+
+// ResourceAction defines a custom action of resources.
+type ResourceAction struct {
+	// Name is the name of the action.
+	Name string `json:"name"`
+
+	// ActionLua is a Lua script that returns the resource modified by the action.
+	ActionLua string `json:"actionLua"`
+}
+
+// ResourceFilter selects resources by API group, kind and cluster. The values are globs, e.g. "*.k8s.io", and an
+// empty list matches all values.
+type ResourceFilter struct {
+	// APIGroups are the API groups of the resources, "" is the core group.
+	APIGroups []string `json:"apiGroups,omitempty"`
+
+	// Kinds are the kinds of the resources.
+	Kinds []string `json:"kinds,omitempty"`
+
+	// Clusters are the URLs of the clusters of the resources.
+	Clusters []string `json:"clusters,omitempty"`
+}
+
+// ResourceIgnoreDifferences defines the fields of resources that are ignored when comparing them to the desired state.
+type ResourceIgnoreDifferences struct {
+	// JSONPointers are the JSON pointers to the ignored fields.
+	JSONPointers []string `json:"jsonPointers,omitempty"`
+
+	// JQPathExpressions are the JQ path expressions that select the ignored fields.
+	JQPathExpressions []string `json:"jqPathExpressions,omitempty"`
+
+	// ManagedFieldsManagers are the field managers whose fields are ignored.
+	ManagedFieldsManagers []string `json:"managedFieldsManagers,omitempty"`
+}
+
+// KnownTypeField defines the type of a field of a resource.
+type KnownTypeField struct {
+	// Field is the path of the field, e.g. spec.template.spec.
+	Field string `json:"field"`
+
+	// Type is the type of the field, e.g. core/v1/PodSpec.
+	Type string `json:"type"`
+}
+
+// ArgoCDPodDisruptionBudgetSpec defines the PodDisruptionBudget of an Argo CD component.
+type ArgoCDPodDisruptionBudgetSpec struct {
+	// Enabled will toggle the creation of the PodDisruptionBudget. Defaults to true when HA is enabled or the component
+	// has more than one replica.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// MaxUnavailable is the maximum number or percentage of pods that can be unavailable during a voluntary disruption.
+	// Defaults to 1 when MinAvailable is not set.
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// MinAvailable is the minimum number or percentage of pods that must be available during a voluntary disruption.
+	// Cannot be set together with MaxUnavailable.
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+}
+
+// ArgoCDSchedulingSpec defines the scheduling options for the pods of an Argo CD component.
+type ArgoCDSchedulingSpec struct {
+	// Affinity defines the affinity and anti-affinity rules of the pods, it replaces the default affinity of the component.
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// PriorityClassName is the name of the PriorityClass of the pods.
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// RuntimeClassName is the name of the RuntimeClass used to run the pods.
+	RuntimeClassName *string `json:"runtimeClassName,omitempty"`
+
+	// TopologySpreadConstraints describe how the pods are spread across topology domains.
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+}
+
+// ArgoCDSecurityContextSpec defines the security contexts of the pods and containers of an Argo CD component. The
+// fields that are set are merged into the default security contexts, which satisfy the restricted Pod Security
+// Standard.
+type ArgoCDSecurityContextSpec struct {
+	// Container is the security context of the containers of the pods.
+	Container *corev1.SecurityContext `json:"container,omitempty"`
+
+	// Pod is the security context of the pods.
+	Pod *corev1.PodSecurityContext `json:"pod,omitempty"`
+}
+
+// ArgoCDNetworkPolicySpec defines the NetworkPolicies that restrict the traffic to the Argo CD components.
+type ArgoCDNetworkPolicySpec struct {
+	// Enabled will toggle the creation of the NetworkPolicies.
+	Enabled bool `json:"enabled"`
+
+	// MetricsFrom are the sources that are allowed to scrape the metrics of the components, e.g. Prometheus. All
+	// sources are allowed if empty.
+	MetricsFrom []networkingv1.NetworkPolicyPeer `json:"metricsFrom,omitempty"`
+
+	// ServerFrom are the sources that are allowed to connect to the Argo CD server and Grafana, e.g. the ingress
+	// controller. All sources are allowed if empty.
+	ServerFrom []networkingv1.NetworkPolicyPeer `json:"serverFrom,omitempty"`
+}
+
+// ArgoCDUpgradeSpec defines the options for managed upgrades of Argo CD.
+type ArgoCDUpgradeSpec struct {
+	// Managed enables managed upgrades. When the version changes, the operator runs pre-flight checks and rolls the
+	// new version out to the repo server, the application controller and the server in order, waiting for each of
+	// them to become ready. All of them are rolled back to the previous version if one does not.
+	Managed bool `json:"managed,omitempty"`
+
+	// ProgressDeadline is the time that a component has to become ready with the new version, before the upgrade is
+	// rolled back. Defaults to 10m.
+	ProgressDeadline *metav1.Duration `json:"progressDeadline,omitempty"`
+
+	// SkipPreflightChecks starts upgrades even if the pre-flight checks fail, e.g. to skip a minor version.
+	SkipPreflightChecks bool `json:"skipPreflightChecks,omitempty"`
+}
+
 //ArgoCDNodePlacementSpec is used to specify NodeSelector and Tolerations for Argo CD workloads
 type ArgoCDNodePlacementSpec struct {
 	// NodeSelector is a field of PodSpec, it is a map of key value pairs used for node selection
@@ -531,6 +880,18 @@ type ArgoCDSpec struct {
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Application Instance Label Key'",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
 	ApplicationInstanceLabelKey string `json:"applicationInstanceLabelKey,omitempty"`
 
+	// CommonAnnotations are added to every resource generated by the operator.
+	CommonAnnotations map[string]string `json:"commonAnnotations,omitempty"`
+
+	// CommonLabels are added to every resource generated by the operator.
+	CommonLabels map[string]string `json:"commonLabels,omitempty"`
+
+	// CommonPodAnnotations are added to the pod template of every component.
+	CommonPodAnnotations map[string]string `json:"commonPodAnnotations,omitempty"`
+
+	// CommonPodLabels are added to the pod template of every component.
+	CommonPodLabels map[string]string `json:"commonPodLabels,omitempty"`
+
 	// ConfigManagementPlugins is used to specify additional config management plugins.
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Config Management Plugins'",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
 	ConfigManagementPlugins string `json:"configManagementPlugins,omitempty"`
@@ -544,6 +905,14 @@ type ArgoCDSpec struct {
 	// DisableAdmin will disable the admin user.
 	DisableAdmin bool `json:"disableAdmin,omitempty"`
 
+	// ExcludeDefaultResources adds the default exclusions of the operator, which exclude events, leases and metrics,
+	// to the resources that Argo CD ignores completely. Defaults to true, set it to false to disable them.
+	ExcludeDefaultResources *bool `json:"excludeDefaultResources,omitempty"`
+
+	// ExcludedResources lists the resources that Argo CD ignores completely. They are merged with the
+	// ResourceExclusions.
+	ExcludedResources []ResourceFilter `json:"excludedResources,omitempty"`
+
 	// GATrackingID is the google analytics tracking ID to use.
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Google Analytics Tracking ID'",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
 	GATrackingID string `json:"gaTrackingID,omitempty"`
@@ -558,6 +927,10 @@ type ArgoCDSpec struct {
 	// HA options for High Availability support for the Redis component.
 	HA ArgoCDHASpec `json:"ha,omitempty"`
 
+	// HealthChecks enables health checks for popular custom resources from the catalog bundled with the operator. The
+	// resource customizations and overrides take precedence over the bundled health checks of the same group and kind.
+	HealthChecks *ArgoCDHealthChecksSpec `json:"healthChecks,omitempty"`
+
 	// HelpChatURL is the URL for getting chat help, this will typically be your Slack channel for support.
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Help Chat URL'",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
 	HelpChatURL string `json:"helpChatURL,omitempty"`
@@ -570,6 +943,24 @@ type ArgoCDSpec struct {
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Image",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:fieldGroup:ArgoCD","urn:alm:descriptor:com.tectonic.ui:text"}
 	Image string `json:"image,omitempty"`
 
+	// ImagePullPolicy is the pull policy for the container images of all components. Defaults to IfNotPresent for images
+	// that are referenced by digest, and Always otherwise.
+	//+kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// ImagePullSecrets are the Secrets used to pull the images of the components, they are added to every generated pod
+	// and ServiceAccount.
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// ImageRegistry is the registry that mirrors the public registries, e.g. quay.io, ghcr.io and docker.io. The images
+	// of the components that are hosted on these registries are pulled from it instead, keeping their repository and
+	// tag or digest. Defaults to the ARGOCD_IMAGE_REGISTRY environment variable of the operator.
+	ImageRegistry string `json:"imageRegistry,omitempty"`
+
+	// IncludedResources lists the resources that Argo CD reconciles, all other resources are ignored. They are merged
+	// with the ResourceInclusions.
+	IncludedResources []ResourceFilter `json:"includedResources,omitempty"`
+
 	// Import is the import/restore options for ArgoCD.
 	Import *ArgoCDImportSpec `json:"import,omitempty"`
 
@@ -591,9 +982,15 @@ type ArgoCDSpec struct {
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="OIDC Config'",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
 	OIDCConfig string `json:"oidcConfig,omitempty"`
 
+	// NetworkPolicy defines the NetworkPolicies that restrict the traffic to the Argo CD components.
+	NetworkPolicy *ArgoCDNetworkPolicySpec `json:"networkPolicy,omitempty"`
+
 	// NodePlacement defines NodeSelectors and Taints for Argo CD workloads
 	NodePlacement *ArgoCDNodePlacementSpec `json:"nodePlacement,omitempty"`
 
+	// Patches are applied to the resources generated by the operator, after the options of the ArgoCD have been applied.
+	Patches []ArgoCDPatch `json:"patches,omitempty"`
+
 	// Prometheus defines the Prometheus server options for ArgoCD.
 	Prometheus ArgoCDPrometheusSpec `json:"prometheus,omitempty"`
 
@@ -610,6 +1007,7 @@ type ArgoCDSpec struct {
 	RepositoryCredentials string `json:"repositoryCredentials,omitempty"`
 
 	// ResourceCustomizations customizes resource behavior. Keys are in the form: group/Kind.
+	// Deprecated: use ResourceOverrides, which take precedence for the same group and kind.
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Resource Customizations'",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
 	ResourceCustomizations string `json:"resourceCustomizations,omitempty"`
 
@@ -621,6 +1019,10 @@ type ArgoCDSpec struct {
 	// reconciliation process.
 	ResourceInclusions string `json:"resourceInclusions,omitempty"`
 
+	// ResourceOverrides customizes the health assessment, actions, diffing and known type fields of resources of a group
+	// and kind. They are rendered into the argocd-cm keys of the Argo CD version in use.
+	ResourceOverrides []ResourceOverride `json:"resourceOverrides,omitempty"`
+
 	// ResourceTrackingMethod defines how Argo CD should track resources that it manages
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Resource Tracking Method'",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text","urn:alm:descriptor:com.tectonic.ui:advanced"}
 	ResourceTrackingMethod string `json:"resourceTrackingMethod,omitempty"`
@@ -638,6 +1040,9 @@ type ArgoCDSpec struct {
 	// TLS defines the TLS options for ArgoCD.
 	TLS ArgoCDTLSSpec `json:"tls,omitempty"`
 
+	// Upgrade defines the options for managed upgrades of Argo CD.
+	Upgrade *ArgoCDUpgradeSpec `json:"upgrade,omitempty"`
+
 	// UsersAnonymousEnabled toggles anonymous user access.
 	// The anonymous users get default role permissions specified argocd-rbac-cm.
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Anonymous Users Enabled'",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch","urn:alm:descriptor:com.tectonic.ui:advanced"}
@@ -720,8 +1125,202 @@ type ArgoCDStatus struct {
 
 	// Host is the hostname of the Ingress.
 	Host string `json:"host,omitempty"`
+
+	// Conditions contains the latest available observations of the state of the ArgoCD.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Conditions",xDescriptors={"urn:alm:descriptor:io.kubernetes.conditions"}
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// ObservedGeneration is the most recent generation of the ArgoCD observed by the operator.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Version is the Argo CD version that all of the core components were last rolled out with.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Image is the Argo CD container image that all of the core components were last rolled out with, before the image
+	// registry is applied.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Images are the container images that the enabled components run, keyed by component, e.g. server or redis. The
+	// images are resolved from the spec, the environment of the operator and the progress of a managed upgrade, with the
+	// image registry applied, so that the images and versions in use are visible without reading the Deployments.
+	// +optional
+	Images map[string]string `json:"images,omitempty"`
+
+	// Upgrade describes the managed upgrade of Argo CD that is in progress, or that finished last.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Upgrade"
+	Upgrade *ArgoCDUpgradeStatus `json:"upgrade,omitempty"`
+
+	// ConfigMigrations lists the migrations that were applied to the generated Argo CD configuration, to rewrite it into
+	// the format of the Argo CD version in use.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Config Migrations"
+	ConfigMigrations []ArgoCDConfigMigration `json:"configMigrations,omitempty"`
+
+	// SchemaVersion is the version of the resources generated by the operator that the ArgoCD was last migrated to.
+	// The operator runs the migrations of newer versions in order when it is upgraded, e.g. to delete resources that
+	// were renamed.
+	// +optional
+	SchemaVersion int32 `json:"schemaVersion,omitempty"`
+
+	// DriftedResources lists the resources owned by the operator that were modified out-of-band, i.e. a field set by
+	// the operator was changed by another field manager. The operator resets the modified fields, a resource is
+	// listed until no drift has been detected for it for an hour.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Drifted Resources"
+	DriftedResources []ArgoCDDriftedResource `json:"driftedResources,omitempty"`
+}
+
+// ArgoCDUpgradeStatus describes a managed upgrade of Argo CD.
+type ArgoCDUpgradeStatus struct {
+	// Phase is the phase of the upgrade, one of Progressing, Succeeded, RolledBack or Blocked.
+	Phase string `json:"phase"`
+
+	// FromVersion is the version that the components are upgraded from, and rolled back to if the upgrade fails.
+	FromVersion string `json:"fromVersion,omitempty"`
+
+	// FromImage is the container image of the version that the components are upgraded from.
+	FromImage string `json:"fromImage,omitempty"`
+
+	// ToVersion is the version that the components are upgraded to.
+	ToVersion string `json:"toVersion"`
+
+	// ToImage is the container image of the version that the components are upgraded to.
+	ToImage string `json:"toImage,omitempty"`
+
+	// Component is the component that the new version is being rolled out to, while the upgrade is progressing.
+	Component string `json:"component,omitempty"`
+
+	// Message is a human readable description of the state of the upgrade, e.g. the failed pre-flight checks.
+	Message string `json:"message,omitempty"`
+
+	// LastTransitionTime is the last time that the phase or the component of the upgrade changed.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+}
+
+// ArgoCDConfigMigration describes a migration of the generated Argo CD configuration to the format of a newer Argo CD
+// version.
+type ArgoCDConfigMigration struct {
+	// Name is the name of the migration.
+	Name string `json:"name"`
+
+	// ConfigMap is the name of the ConfigMap that the migration rewrote.
+	ConfigMap string `json:"configMap"`
+
+	// Version is the Argo CD version that introduced the format that the configuration was migrated to.
+	Version string `json:"version"`
+
+	// Message is a human readable description of the migration.
+	Message string `json:"message,omitempty"`
+}
+
+// ArgoCDDriftedResource describes a resource owned by the operator that was modified out-of-band.
+type ArgoCDDriftedResource struct {
+	// Kind is the kind of the resource.
+	Kind string `json:"kind"`
+
+	// Name is the name of the resource.
+	Name string `json:"name"`
+
+	// Fields are the paths of the fields that were modified, e.g. .data.admin.enabled
+	Fields []string `json:"fields,omitempty"`
+
+	// Managers are the field managers that modified the fields, e.g. kubectl-edit
+	Managers []string `json:"managers,omitempty"`
+
+	// LastDetectedTime is the last time that drift was detected for the resource.
+	LastDetectedTime metav1.Time `json:"lastDetectedTime"`
 }
 
+const (
+	// ArgoCDConditionAvailable indicates that all of the core Argo CD components are running and ready.
+	ArgoCDConditionAvailable = "Available"
+
+	// ArgoCDConditionProgressing indicates that the operator is waiting for one or more components to become ready.
+	ArgoCDConditionProgressing = "Progressing"
+
+	// ArgoCDConditionDegraded indicates that the ArgoCD cannot reach its desired state without intervention.
+	ArgoCDConditionDegraded = "Degraded"
+
+	// ArgoCDConditionReconcileError indicates that the last reconciliation of the ArgoCD failed.
+	ArgoCDConditionReconcileError = "ReconcileError"
+
+	// ArgoCDConditionSSOConfigured indicates that exactly one SSO provider is configured for the ArgoCD.
+	ArgoCDConditionSSOConfigured = "SSOConfigured"
+
+	// ArgoCDConditionApplicationControllerReady indicates that the application controller pods are ready.
+	ArgoCDConditionApplicationControllerReady = "ApplicationControllerReady"
+
+	// ArgoCDConditionDexReady indicates that the Dex server pods are ready.
+	ArgoCDConditionDexReady = "DexReady"
+
+	// ArgoCDConditionRedisReady indicates that the Redis pods are ready.
+	ArgoCDConditionRedisReady = "RedisReady"
+
+	// ArgoCDConditionRepoServerReady indicates that the repo server pods are ready.
+	ArgoCDConditionRepoServerReady = "RepoServerReady"
+
+	// ArgoCDConditionServerReady indicates that the Argo CD server pods are ready.
+	ArgoCDConditionServerReady = "ServerReady"
+)
+
+const (
+	// ArgoCDReasonComponentsReady is the reason used when all of the core components are ready.
+	ArgoCDReasonComponentsReady = "ComponentsReady"
+
+	// ArgoCDReasonComponentsNotReady is the reason used when one or more core components are not ready.
+	ArgoCDReasonComponentsNotReady = "ComponentsNotReady"
+
+	// ArgoCDReasonReconcileFailed is the reason used when the last reconciliation failed.
+	ArgoCDReasonReconcileFailed = "ReconcileFailed"
+
+	// ArgoCDReasonReconcileSucceeded is the reason used when the last reconciliation succeeded.
+	ArgoCDReasonReconcileSucceeded = "ReconcileSucceeded"
+
+	// ArgoCDReasonUpgradeInProgress is the reason used while a managed upgrade is rolling out a new version.
+	ArgoCDReasonUpgradeInProgress = "UpgradeInProgress"
+
+	// ArgoCDReasonUpgradeBlocked is the reason used when the pre-flight checks of a managed upgrade failed.
+	ArgoCDReasonUpgradeBlocked = "UpgradeBlocked"
+
+	// ArgoCDReasonUpgradeRolledBack is the reason used when a managed upgrade was rolled back.
+	ArgoCDReasonUpgradeRolledBack = "UpgradeRolledBack"
+
+	// ArgoCDReasonAsExpected is the reason used when a condition is in its expected state.
+	ArgoCDReasonAsExpected = "AsExpected"
+
+	// ArgoCDReasonSingleSSOProvider is the reason used when exactly one SSO provider is configured.
+	ArgoCDReasonSingleSSOProvider = "SingleSSOProvider"
+
+	// ArgoCDReasonMultipleSSOProviders is the reason used when both Keycloak and Dex are configured.
+	ArgoCDReasonMultipleSSOProviders = "MultipleSSOProviders"
+
+	// ArgoCDReasonNoSSOProvider is the reason used when no SSO provider is configured.
+	ArgoCDReasonNoSSOProvider = "NoSSOProvider"
+)
+
+const (
+	// ArgoCDUpgradePhaseProgressing is the phase of a managed upgrade that is rolling out the new version.
+	ArgoCDUpgradePhaseProgressing = "Progressing"
+
+	// ArgoCDUpgradePhaseSucceeded is the phase of a managed upgrade that rolled the new version out to all components.
+	ArgoCDUpgradePhaseSucceeded = "Succeeded"
+
+	// ArgoCDUpgradePhaseRolledBack is the phase of a managed upgrade that was rolled back to the previous version.
+	ArgoCDUpgradePhaseRolledBack = "RolledBack"
+
+	// ArgoCDUpgradePhaseBlocked is the phase of a managed upgrade whose pre-flight checks failed.
+	ArgoCDUpgradePhaseBlocked = "Blocked"
+)
+
 // Banner defines an additional banner message to be displayed in Argo CD UI
 // https://argo-cd.readthedocs.io/en/stable/operator-manual/custom-styles/#banners
 type Banner struct {
@@ -0,0 +1,477 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/parse"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// argocdlog is for logging in this package.
+var argocdlog = logf.Log.WithName("argocd-resource")
+
+// argoServerManagedFlags are the flags that the operator always sets on the Argo CD server command. They cannot be
+// passed again using Server.ExtraCommandArgs.
+var argoServerManagedFlags = []string{
+	"--staticassets",
+	"--dex-server",
+	"--repo-server",
+	"--redis",
+	"--loglevel",
+	"--logformat",
+}
+
+// SetupWebhookWithManager registers the webhooks for ArgoCD with the given manager.
+func (r *ArgoCD) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-argoproj-io-v1alpha1-argocd,mutating=false,failurePolicy=fail,sideEffects=None,groups=argoproj.io,resources=argocds,verbs=create;update,versions=v1alpha1,name=vargocd.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &ArgoCD{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *ArgoCD) ValidateCreate() error {
+	argocdlog.Info("validate create", "name", r.Name)
+	return r.validate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+//
+// An ArgoCD that is being deleted, or whose spec is unchanged, e.g. when a finalizer is removed, is not validated, and
+// the problems that the old spec already had are not reported, so that an ArgoCD that was created before a check was
+// added can still be updated.
+func (r *ArgoCD) ValidateUpdate(old runtime.Object) error {
+	argocdlog.Info("validate update", "name", r.Name)
+	oldCR, ok := old.(*ArgoCD)
+	if !ok {
+		return r.validate()
+	}
+	if r.DeletionTimestamp != nil || reflect.DeepEqual(r.Spec, oldCR.Spec) {
+		return nil
+	}
+
+	fldPath := field.NewPath("spec")
+	existing := map[string]bool{}
+	for _, err := range oldCR.Spec.validate(fldPath) {
+		existing[err.Error()] = true
+	}
+
+	allErrs := field.ErrorList{}
+	for _, err := range r.Spec.validate(fldPath) {
+		if !existing[err.Error()] {
+			allErrs = append(allErrs, err)
+		}
+	}
+	return r.newInvalidError(allErrs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *ArgoCD) ValidateDelete() error {
+	return nil
+}
+
+// validate will return an Invalid error listing every problem found in the spec, or nil if the spec is valid.
+func (r *ArgoCD) validate() error {
+	return r.newInvalidError(r.Spec.validate(field.NewPath("spec")))
+}
+
+// ValidateResourceCustomizations will return an Invalid error listing every Lua script of the resource customizations
+// and overrides that does not compile, or nil if all of them compile. The reconciler uses it to refuse the scripts
+// when the webhooks are not enabled.
+func (r *ArgoCD) ValidateResourceCustomizations() error {
+	return r.newInvalidError(r.Spec.validateResourceCustomizations(field.NewPath("spec")))
+}
+
+// newInvalidError will return an Invalid error for the given errors, or nil if there are none.
+func (r *ArgoCD) newInvalidError(allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: "ArgoCD"}, r.Name, allErrs)
+}
+
+// validate will return the list of validation errors for the given ArgoCDSpec.
+func (s *ArgoCDSpec) validate(fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if s.SSO != nil && !reflect.DeepEqual(s.Dex, ArgoCDDexSpec{}) {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("sso"),
+			"sso and dex cannot be configured at the same time, configure only one SSO provider"))
+	}
+
+	if ParseResourceTrackingMethod(s.ResourceTrackingMethod) == ResourceTrackingMethodInvalid {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("resourceTrackingMethod"), s.ResourceTrackingMethod, []string{
+			stringResourceTrackingMethodLabel,
+			stringResourceTrackingMethodAnnotation,
+			stringResourceTrackingMethodAnnotationAndLabel,
+		}))
+	}
+
+	allErrs = append(allErrs, s.validateExtraCommandArgs(fldPath.Child("server", "extraCommandArgs"))...)
+	allErrs = append(allErrs, s.validateResourceCustomizationsFormat(fldPath.Child("resourceCustomizations"))...)
+	allErrs = append(allErrs, s.validateResourceCustomizations(fldPath)...)
+	allErrs = append(allErrs, s.validateResourceOverrides(fldPath.Child("resourceOverrides"))...)
+	allErrs = append(allErrs, s.validateHealthChecks(fldPath.Child("healthChecks"))...)
+	allErrs = append(allErrs, validateResourceFilters(fldPath.Child("excludedResources"), s.ExcludedResources)...)
+	allErrs = append(allErrs, validateResourceFilters(fldPath.Child("includedResources"), s.IncludedResources)...)
+	excludeDefaults := s.ExcludeDefaultResources == nil || *s.ExcludeDefaultResources
+	if len(s.ExcludedResources) > 0 || excludeDefaults {
+		allErrs = append(allErrs, validateResourceFiltersFormat(fldPath.Child("resourceExclusions"), s.ResourceExclusions,
+			"excludedResources and the default exclusions")...)
+	}
+	if len(s.IncludedResources) > 0 {
+		allErrs = append(allErrs, validateResourceFiltersFormat(fldPath.Child("resourceInclusions"), s.ResourceInclusions,
+			"includedResources")...)
+	}
+	allErrs = append(allErrs, s.validatePodDisruptionBudgets(fldPath)...)
+	allErrs = append(allErrs, s.validatePodTemplateOverrides(fldPath)...)
+
+	return allErrs
+}
+
+// validatePodDisruptionBudgets will return an error for every PodDisruptionBudget of a component that sets both
+// minAvailable and maxUnavailable, which are mutually exclusive.
+func (s *ArgoCDSpec) validatePodDisruptionBudgets(fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	var applicationSet *ArgoCDPodDisruptionBudgetSpec
+	if s.ApplicationSet != nil {
+		applicationSet = s.ApplicationSet.PodDisruptionBudget
+	}
+
+	pdbs := []struct {
+		component string
+		spec      *ArgoCDPodDisruptionBudgetSpec
+	}{
+		{"applicationSet", applicationSet},
+		{"controller", s.Controller.PodDisruptionBudget},
+		{"dex", s.Dex.PodDisruptionBudget},
+		{"ha", s.HA.PodDisruptionBudget},
+		{"redis", s.Redis.PodDisruptionBudget},
+		{"repo", s.Repo.PodDisruptionBudget},
+		{"server", s.Server.PodDisruptionBudget},
+	}
+	for _, pdb := range pdbs {
+		if pdb.spec != nil && pdb.spec.MinAvailable != nil && pdb.spec.MaxUnavailable != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child(pdb.component, "podDisruptionBudget", "maxUnavailable"),
+				"minAvailable and maxUnavailable are mutually exclusive"))
+		}
+	}
+
+	return allErrs
+}
+
+// validatePodTemplateOverrides will return an error for every pod template override of a component that is not a
+// valid strategic merge patch of a pod template, which would fail every reconciliation of the component.
+func (s *ArgoCDSpec) validatePodTemplateOverrides(fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	var applicationSet, sso *runtime.RawExtension
+	if s.ApplicationSet != nil {
+		applicationSet = s.ApplicationSet.PodTemplateOverride
+	}
+	if s.SSO != nil {
+		sso = s.SSO.PodTemplateOverride
+	}
+
+	overrides := []struct {
+		component string
+		override  *runtime.RawExtension
+	}{
+		{"applicationSet", applicationSet},
+		{"controller", s.Controller.PodTemplateOverride},
+		{"dex", s.Dex.PodTemplateOverride},
+		{"grafana", s.Grafana.PodTemplateOverride},
+		{"ha", s.HA.PodTemplateOverride},
+		{"redis", s.Redis.PodTemplateOverride},
+		{"repo", s.Repo.PodTemplateOverride},
+		{"server", s.Server.PodTemplateOverride},
+		{"sso", sso},
+	}
+	for _, o := range overrides {
+		if o.override == nil || len(o.override.Raw) == 0 {
+			continue
+		}
+		if err := validatePodTemplateOverride(o.override.Raw); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child(o.component, "podTemplateOverride"), field.OmitValueType{},
+				fmt.Sprintf("invalid pod template override: %v", err)))
+		}
+	}
+
+	return allErrs
+}
+
+// validatePodTemplateOverride will return an error if the given strategic merge patch cannot be applied to an empty pod
+// template, or if the result is not a pod template.
+func validatePodTemplateOverride(override []byte) error {
+	patched, err := strategicpatch.StrategicMergePatch([]byte("{}"), override, corev1.PodTemplateSpec{})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(patched, &corev1.PodTemplateSpec{})
+}
+
+// validateExtraCommandArgs will return an error for every extra argument that is already part of the Argo CD server
+// command generated by the operator.
+func (s *ArgoCDSpec) validateExtraCommandArgs(fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	managed := make([]string, 0, len(argoServerManagedFlags)+2)
+	managed = append(managed, argoServerManagedFlags...)
+	if s.Server.Insecure {
+		managed = append(managed, "--insecure")
+	}
+	if s.Repo.VerifyTLS {
+		managed = append(managed, "--repo-server-strict-tls")
+	}
+
+	for i, arg := range s.Server.ExtraCommandArgs {
+		if len(arg) <= 2 || !strings.HasPrefix(arg, "--") {
+			continue
+		}
+
+		flag := strings.SplitN(arg, "=", 2)[0]
+		if containsString(managed, flag) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), arg,
+				fmt.Sprintf("%s is already part of the Argo CD server command and cannot be overridden", flag)))
+		}
+	}
+
+	return allErrs
+}
+
+// validateResourceFilters will return an error for every filter that matches all resources, and for every kind and
+// cluster of a filter that is empty. An empty API group is the core group.
+func validateResourceFilters(fldPath *field.Path, filters []ResourceFilter) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, filter := range filters {
+		if len(filter.APIGroups) == 0 && len(filter.Kinds) == 0 && len(filter.Clusters) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Index(i),
+				"at least one of apiGroups, kinds or clusters must be set, an empty filter matches all resources"))
+		}
+		for j, kind := range filter.Kinds {
+			if kind == "" {
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("kinds").Index(j), kind, "must not be empty"))
+			}
+		}
+		for j, cluster := range filter.Clusters {
+			if cluster == "" {
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("clusters").Index(j), cluster, "must not be empty"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// validateResourceFiltersFormat will return an error if the given resource filters in YAML are not a list of filters,
+// as they are merged with the typed resource filters and the default exclusions, which the given description names.
+func validateResourceFiltersFormat(fldPath *field.Path, filtersYAML string, merged string) field.ErrorList {
+	if filtersYAML == "" {
+		return nil
+	}
+
+	filters := []yaml.MapSlice{}
+	if err := yaml.Unmarshal([]byte(filtersYAML), &filters); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, field.OmitValueType{}, fmt.Sprintf(
+			"must be a list of filters to be merged with %s: %s", merged, strings.Join(strings.Fields(err.Error()), " ")))}
+	}
+	return nil
+}
+
+// validateResourceOverrides will return an error for every resource override of a group and kind that is already
+// overridden by an earlier resource override, as only one of them would be rendered.
+func (s *ArgoCDSpec) validateResourceOverrides(fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	seen := map[string]bool{}
+	for i, override := range s.ResourceOverrides {
+		groupKind := override.Kind
+		if override.Group != "" {
+			groupKind = override.Group + "/" + override.Kind
+		}
+		if seen[groupKind] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), groupKind))
+		}
+		seen[groupKind] = true
+	}
+
+	return allErrs
+}
+
+// validateHealthChecks will return an error for every included health check that is not part of the bundled catalog,
+// and for every Crossplane claim without a group or kind. The names are not validated if the catalog cannot be read.
+func (s *ArgoCDSpec) validateHealthChecks(fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if s.HealthChecks == nil {
+		return allErrs
+	}
+
+	if names, err := GetHealthCheckNames(); err == nil && len(names) > 0 {
+		for i, name := range s.HealthChecks.Include {
+			if !containsString(names, name) {
+				allErrs = append(allErrs, field.NotSupported(fldPath.Child("include").Index(i), name, names))
+			}
+		}
+	}
+
+	for i, claim := range s.HealthChecks.CrossplaneClaims {
+		if claim.Group == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("crossplaneClaims").Index(i).Child("group"), ""))
+		}
+		if claim.Kind == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("crossplaneClaims").Index(i).Child("kind"), ""))
+		}
+	}
+
+	return allErrs
+}
+
+// validateResourceCustomizationsFormat will return an error if the resource customizations are not a map of
+// customizations keyed by group/kind while resource overrides or health checks are set, as these are merged into the
+// customizations by group/kind. Otherwise the customizations are passed to Argo CD as is and not validated.
+func (s *ArgoCDSpec) validateResourceCustomizationsFormat(fldPath *field.Path) field.ErrorList {
+	healthChecks := s.HealthChecks != nil && (len(s.HealthChecks.Include) > 0 || len(s.HealthChecks.CrossplaneClaims) > 0)
+	if s.ResourceCustomizations == "" || (len(s.ResourceOverrides) == 0 && !healthChecks) {
+		return nil
+	}
+
+	customizations := map[string]map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(s.ResourceCustomizations), &customizations); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, field.OmitValueType{}, fmt.Sprintf(
+			"must be a map of customizations keyed by group/kind when resourceOverrides or healthChecks are set: %s",
+			strings.Join(strings.Fields(err.Error()), " ")))}
+	}
+	return nil
+}
+
+// resourceCustomization holds the Lua scripts of a resource customization in the format of the resource.customizations
+// key of argocd-cm.
+type resourceCustomization struct {
+	HealthLua string `yaml:"health.lua"`
+	Actions   string `yaml:"actions"`
+}
+
+// resourceCustomizationActions holds the Lua scripts of the actions of a resource customization.
+type resourceCustomizationActions struct {
+	DiscoveryLua string `yaml:"discovery.lua"`
+	Definitions  []struct {
+		Name      string `yaml:"name"`
+		ActionLua string `yaml:"action.lua"`
+	} `yaml:"definitions"`
+}
+
+// validateResourceCustomizations will return an error for every health check and action Lua script of the resource
+// customizations and overrides that does not compile. Customizations that cannot be parsed are not validated, they
+// are passed to Argo CD as is.
+func (s *ArgoCDSpec) validateResourceCustomizations(fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	customizations := map[string]resourceCustomization{}
+	if err := yaml.Unmarshal([]byte(s.ResourceCustomizations), &customizations); err == nil {
+		// Map iteration is random, keep the errors stable across validations.
+		groupKinds := make([]string, 0, len(customizations))
+		for groupKind := range customizations {
+			groupKinds = append(groupKinds, groupKind)
+		}
+		sort.Strings(groupKinds)
+
+		for _, groupKind := range groupKinds {
+			customization := customizations[groupKind]
+			path := fldPath.Child("resourceCustomizations").Key(groupKind)
+			allErrs = append(allErrs, validateLua(path.Child("health.lua"), groupKind, customization.HealthLua)...)
+
+			actions := resourceCustomizationActions{}
+			if err := yaml.Unmarshal([]byte(customization.Actions), &actions); err != nil {
+				continue
+			}
+			allErrs = append(allErrs, validateLua(path.Child("actions", "discovery.lua"), groupKind, actions.DiscoveryLua)...)
+			for i, definition := range actions.Definitions {
+				allErrs = append(allErrs, validateLua(path.Child("actions", "definitions").Index(i).Child("action.lua"),
+					groupKind, definition.ActionLua)...)
+			}
+		}
+	}
+
+	for i, override := range s.ResourceOverrides {
+		path := fldPath.Child("resourceOverrides").Index(i)
+		groupKind := override.Kind
+		if override.Group != "" {
+			groupKind = override.Group + "/" + override.Kind
+		}
+		allErrs = append(allErrs, validateLua(path.Child("healthLua"), groupKind, override.HealthLua)...)
+		if override.Actions == nil {
+			continue
+		}
+		allErrs = append(allErrs, validateLua(path.Child("actions", "discoveryLua"), groupKind, override.Actions.DiscoveryLua)...)
+		for j, definition := range override.Actions.Definitions {
+			allErrs = append(allErrs, validateLua(path.Child("actions", "definitions").Index(j).Child("actionLua"),
+				groupKind, definition.ActionLua)...)
+		}
+	}
+
+	return allErrs
+}
+
+// validateLua will return an error if the given Lua script of the given resource does not compile, using the Lua
+// runtime of Argo CD.
+func validateLua(fldPath *field.Path, groupKind string, script string) field.ErrorList {
+	if script == "" {
+		return nil
+	}
+
+	chunk, err := parse.Parse(strings.NewReader(script), groupKind)
+	if err == nil {
+		_, err = lua.Compile(chunk, groupKind)
+	}
+	if err != nil {
+		// The errors name the script and position, e.g. "apps/Deployment line:2(column:6) near 'return': syntax error".
+		return field.ErrorList{field.Invalid(fldPath, field.OmitValueType{},
+			fmt.Sprintf("invalid Lua script: %s", strings.Join(strings.Fields(err.Error()), " ")))}
+	}
+	return nil
+}
+
+// containsString returns true if the given slice contains the given string.
+func containsString(s []string, str string) bool {
+	for _, v := range s {
+		if v == str {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,300 @@
+package v1alpha1
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func Test_ArgoCD_ValidateCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    ArgoCDSpec
+		wantErr bool
+	}{
+		{
+			name: "empty spec",
+			spec: ArgoCDSpec{},
+		},
+		{
+			name: "sso only",
+			spec: ArgoCDSpec{SSO: &ArgoCDSSOSpec{Provider: SSOProviderTypeKeycloak}},
+		},
+		{
+			name: "dex only",
+			spec: ArgoCDSpec{Dex: ArgoCDDexSpec{OpenShiftOAuth: true}},
+		},
+		{
+			name: "sso and dex",
+			spec: ArgoCDSpec{
+				SSO: &ArgoCDSSOSpec{Provider: SSOProviderTypeKeycloak},
+				Dex: ArgoCDDexSpec{OpenShiftOAuth: true},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid resource tracking method",
+			spec: ArgoCDSpec{ResourceTrackingMethod: stringResourceTrackingMethodAnnotationAndLabel},
+		},
+		{
+			name:    "invalid resource tracking method",
+			spec:    ArgoCDSpec{ResourceTrackingMethod: "labels"},
+			wantErr: true,
+		},
+		{
+			name: "extra command args",
+			spec: ArgoCDSpec{Server: ArgoCDServerSpec{ExtraCommandArgs: []string{"--rootpath", "/argocd"}}},
+		},
+		{
+			name:    "duplicate extra command args",
+			spec:    ArgoCDSpec{Server: ArgoCDServerSpec{ExtraCommandArgs: []string{"--loglevel", "debug"}}},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate extra command args with value",
+			spec:    ArgoCDSpec{Server: ArgoCDServerSpec{ExtraCommandArgs: []string{"--redis=redis:6379"}}},
+			wantErr: true,
+		},
+		{
+			name: "insecure extra command arg",
+			spec: ArgoCDSpec{Server: ArgoCDServerSpec{ExtraCommandArgs: []string{"--insecure"}}},
+		},
+		{
+			name: "duplicate insecure extra command arg",
+			spec: ArgoCDSpec{Server: ArgoCDServerSpec{
+				Insecure:         true,
+				ExtraCommandArgs: []string{"--insecure"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "valid resource customizations",
+			spec: ArgoCDSpec{ResourceCustomizations: "apps/Deployment:\n  health.lua: |\n    hs = {}\n    return hs\n"},
+		},
+		{
+			name:    "invalid resource customizations health check",
+			spec:    ArgoCDSpec{ResourceCustomizations: "apps/Deployment:\n  health.lua: |\n    hs = {\n    return hs\n"},
+			wantErr: true,
+		},
+		{
+			name: "invalid resource customizations action",
+			spec: ArgoCDSpec{ResourceCustomizations: "Service:\n  actions: |\n    definitions:\n    - name: restart\n" +
+				"      action.lua: |\n        if obj then\n"},
+			wantErr: true,
+		},
+		{
+			name: "unparsable resource customizations",
+			spec: ArgoCDSpec{ResourceCustomizations: "testing: testing"},
+		},
+		{
+			name: "valid resource overrides",
+			spec: ArgoCDSpec{ResourceOverrides: []ResourceOverride{{
+				Kind:      "Service",
+				HealthLua: "hs = {}\nreturn hs\n",
+				Actions: &ResourceActions{
+					DiscoveryLua: "return {}",
+					Definitions:  []ResourceAction{{Name: "restart", ActionLua: "return obj"}},
+				},
+			}}},
+		},
+		{
+			name: "duplicate resource overrides",
+			spec: ArgoCDSpec{ResourceOverrides: []ResourceOverride{
+				{Group: "apps", Kind: "Deployment", HealthLua: "return {}"},
+				{Kind: "Deployment", HealthLua: "return {}"},
+				{Group: "apps", Kind: "Deployment", IgnoreDifferences: &ResourceIgnoreDifferences{JSONPointers: []string{"/spec/replicas"}}},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "resource filters",
+			spec: ArgoCDSpec{
+				ExcludedResources: []ResourceFilter{{APIGroups: []string{""}, Kinds: []string{"Secret"}}},
+				IncludedResources: []ResourceFilter{{Clusters: []string{"https://kubernetes.default.svc"}}},
+			},
+		},
+		{
+			name:    "empty resource filter",
+			spec:    ArgoCDSpec{ExcludedResources: []ResourceFilter{{APIGroups: []string{"apps"}}, {}}},
+			wantErr: true,
+		},
+		{
+			name:    "empty resource filter kind",
+			spec:    ArgoCDSpec{IncludedResources: []ResourceFilter{{APIGroups: []string{"apps"}, Kinds: []string{""}}}},
+			wantErr: true,
+		},
+		{
+			name: "pod disruption budget",
+			spec: ArgoCDSpec{Server: ArgoCDServerSpec{PodDisruptionBudget: &ArgoCDPodDisruptionBudgetSpec{
+				MinAvailable: &intstr.IntOrString{Type: intstr.String, StrVal: "50%"},
+			}}},
+		},
+		{
+			name: "pod disruption budget with min available and max unavailable",
+			spec: ArgoCDSpec{Repo: ArgoCDRepoSpec{PodDisruptionBudget: &ArgoCDPodDisruptionBudgetSpec{
+				MinAvailable:   &intstr.IntOrString{IntVal: 1},
+				MaxUnavailable: &intstr.IntOrString{IntVal: 1},
+			}}},
+			wantErr: true,
+		},
+		{
+			name: "resource exclusions",
+			spec: ArgoCDSpec{
+				ResourceExclusions: "- apiGroups:\n  - cilium.io\n",
+				ExcludedResources:  []ResourceFilter{{APIGroups: []string{"velero.io"}}},
+			},
+		},
+		{
+			name:    "unparsable resource exclusions with default exclusions",
+			spec:    ArgoCDSpec{ResourceExclusions: "testing: testing"},
+			wantErr: true,
+		},
+		{
+			name: "unparsable resource exclusions without default exclusions",
+			spec: ArgoCDSpec{ResourceExclusions: "testing: testing", ExcludeDefaultResources: new(bool)},
+		},
+		{
+			name: "unparsable resource inclusions with included resources",
+			spec: ArgoCDSpec{
+				ResourceInclusions: "testing: testing",
+				IncludedResources:  []ResourceFilter{{APIGroups: []string{"apps"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "resource customizations with resource overrides",
+			spec: ArgoCDSpec{
+				ResourceCustomizations: "apps/Deployment:\n  ignoreDifferences: |\n    jsonPointers:\n    - /spec/replicas\n",
+				ResourceOverrides:      []ResourceOverride{{Kind: "Service", HealthLua: "return {}"}},
+			},
+		},
+		{
+			name: "unparsable resource customizations with resource overrides",
+			spec: ArgoCDSpec{
+				ResourceCustomizations: "- apps/Deployment",
+				ResourceOverrides:      []ResourceOverride{{Kind: "Service", HealthLua: "return {}"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unparsable resource customizations with health checks",
+			spec: ArgoCDSpec{
+				ResourceCustomizations: "apps/Deployment: ignoreDifferences",
+				HealthChecks:           &ArgoCDHealthChecksSpec{CrossplaneClaims: []metav1.GroupKind{{Group: "example.org", Kind: "Database"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unparsable resource customizations without resource overrides",
+			spec: ArgoCDSpec{ResourceCustomizations: "- apps/Deployment"},
+		},
+		{
+			name: "pod template override",
+			spec: ArgoCDSpec{SSO: &ArgoCDSSOSpec{
+				Provider:            SSOProviderTypeKeycloak,
+				PodTemplateOverride: &runtime.RawExtension{Raw: []byte(`{"spec": {"priorityClassName": "high"}}`)},
+			}},
+		},
+		{
+			name: "invalid pod template override",
+			spec: ArgoCDSpec{Repo: ArgoCDRepoSpec{
+				PodTemplateOverride: &runtime.RawExtension{Raw: []byte(`{"spec": {"containers": "invalid"}}`)},
+			}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid resource overrides health check",
+			spec:    ArgoCDSpec{ResourceOverrides: []ResourceOverride{{Kind: "Service", HealthLua: "return 1\nreturn 2"}}},
+			wantErr: true,
+		},
+		{
+			name: "invalid resource overrides discovery",
+			spec: ArgoCDSpec{ResourceOverrides: []ResourceOverride{{
+				Kind:    "Service",
+				Actions: &ResourceActions{DiscoveryLua: "actions = {"},
+			}}},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cr := &ArgoCD{Spec: test.spec}
+			cr.Name = "argocd"
+
+			err := cr.ValidateCreate()
+			if test.wantErr {
+				assert.Error(t, err)
+				assert.True(t, apierrors.IsInvalid(err))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_ArgoCD_ValidateCreate_HealthChecks(t *testing.T) {
+	os.Setenv("HEALTH_CHECKS_PATH", "../../build/healthchecks")
+	defer os.Unsetenv("HEALTH_CHECKS_PATH")
+
+	cr := &ArgoCD{Spec: ArgoCDSpec{HealthChecks: &ArgoCDHealthChecksSpec{
+		Include:          []string{"cert-manager", "crossplane"},
+		CrossplaneClaims: []metav1.GroupKind{{Group: "example.org", Kind: "Database"}},
+	}}}
+	assert.NoError(t, cr.ValidateCreate())
+
+	cr.Spec.HealthChecks.Include = append(cr.Spec.HealthChecks.Include, "testing")
+	assert.Error(t, cr.ValidateCreate())
+
+	cr.Spec.HealthChecks.Include = nil
+	cr.Spec.HealthChecks.CrossplaneClaims = append(cr.Spec.HealthChecks.CrossplaneClaims, metav1.GroupKind{Group: "example.org"})
+	assert.Error(t, cr.ValidateCreate())
+}
+
+func Test_ArgoCD_ValidateUpdate(t *testing.T) {
+	old := &ArgoCD{}
+	cr := &ArgoCD{Spec: ArgoCDSpec{ResourceTrackingMethod: "invalid"}}
+
+	assert.Error(t, cr.ValidateUpdate(old))
+
+	cr.Spec.ResourceTrackingMethod = stringResourceTrackingMethodAnnotation
+	assert.NoError(t, cr.ValidateUpdate(old))
+
+	// The problems of the old spec are not reported, only the new ones
+	old = &ArgoCD{Spec: ArgoCDSpec{ResourceTrackingMethod: "invalid"}}
+	cr = old.DeepCopy()
+	cr.Finalizers = []string{"argoproj.io/finalizer"}
+	assert.NoError(t, cr.ValidateUpdate(old))
+
+	cr.Spec.Server.Insecure = true
+	assert.NoError(t, cr.ValidateUpdate(old))
+
+	cr.Spec.Server.ExtraCommandArgs = []string{"--insecure"}
+	assert.Error(t, cr.ValidateUpdate(old))
+
+	// An ArgoCD that is being deleted is not validated
+	cr.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	assert.NoError(t, cr.ValidateUpdate(old))
+}
+
+func Test_ArgoCD_ValidateResourceCustomizations(t *testing.T) {
+	cr := &ArgoCD{Spec: ArgoCDSpec{ResourceOverrides: []ResourceOverride{{
+		Group:     "argoproj.io",
+		Kind:      "Rollout",
+		HealthLua: "hs = {\nreturn hs\n",
+	}}}}
+	cr.Name = "argocd"
+
+	err := cr.ValidateResourceCustomizations()
+	assert.True(t, apierrors.IsInvalid(err))
+	assert.Contains(t, err.Error(), "spec.resourceOverrides[0].healthLua: Invalid value: invalid Lua script: argoproj.io/Rollout line:2(column:6) near 'return': syntax error")
+
+	cr.Spec.ResourceOverrides[0].HealthLua = "hs = {}\nreturn hs\n"
+	assert.NoError(t, cr.ValidateResourceCustomizations())
+}
@@ -73,10 +73,18 @@ type ArgoCDExportSpec struct {
 	// Image is the container image to use for the export Job.
 	Image string `json:"image,omitempty"`
 
+	// ImagePullPolicy is the pull policy for the export Job container image. Defaults to the image pull policy of the
+	// ArgoCD.
+	//+kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
 	// Schedule in Cron format, see https://en.wikipedia.org/wiki/Cron.
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Schedule",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
 	Schedule *string `json:"schedule,omitempty"`
 
+	// SecurityContext defines the security contexts of the pods and containers of the export Job.
+	SecurityContext *ArgoCDSecurityContextSpec `json:"securityContext,omitempty"`
+
 	// Storage defines the storage configuration options.
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Storage"
 	Storage *ArgoCDExportStorageSpec `json:"storage,omitempty"`
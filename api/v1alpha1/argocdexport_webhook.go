@@ -0,0 +1,210 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/argoproj-labs/argocd-operator/common"
+)
+
+// argocdexportlog is for logging in this package.
+var argocdexportlog = logf.Log.WithName("argocdexport-resource")
+
+// cronField describes the allowed values for a single field of a cron schedule.
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}},
+	{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}},
+}
+
+var cronDescriptors = []string{
+	"@yearly",
+	"@annually",
+	"@monthly",
+	"@weekly",
+	"@daily",
+	"@midnight",
+	"@hourly",
+}
+
+// SetupWebhookWithManager registers the webhooks for ArgoCDExport with the given manager.
+func (r *ArgoCDExport) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-argoproj-io-v1alpha1-argocdexport,mutating=false,failurePolicy=fail,sideEffects=None,groups=argoproj.io,resources=argocdexports,verbs=create;update,versions=v1alpha1,name=vargocdexport.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &ArgoCDExport{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *ArgoCDExport) ValidateCreate() error {
+	argocdexportlog.Info("validate create", "name", r.Name)
+	return r.validate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *ArgoCDExport) ValidateUpdate(old runtime.Object) error {
+	argocdexportlog.Info("validate update", "name", r.Name)
+	return r.validate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *ArgoCDExport) ValidateDelete() error {
+	return nil
+}
+
+// validate will return an Invalid error listing every problem found in the spec, or nil if the spec is valid.
+func (r *ArgoCDExport) validate() error {
+	allErrs := field.ErrorList{}
+	fldPath := field.NewPath("spec")
+
+	if r.Spec.Storage != nil {
+		switch r.Spec.Storage.Backend {
+		case "", common.ArgoCDExportStorageBackendLocal, common.ArgoCDExportStorageBackendAWS,
+			common.ArgoCDExportStorageBackendAzure, common.ArgoCDExportStorageBackendGCP:
+		default:
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("storage", "backend"), r.Spec.Storage.Backend, []string{
+				common.ArgoCDExportStorageBackendLocal,
+				common.ArgoCDExportStorageBackendAWS,
+				common.ArgoCDExportStorageBackendAzure,
+				common.ArgoCDExportStorageBackendGCP,
+			}))
+		}
+	}
+
+	if r.Spec.Schedule != nil {
+		if err := validateCronSchedule(*r.Spec.Schedule); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("schedule"), *r.Spec.Schedule, err.Error()))
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(schema.GroupKind{Group: GroupVersion.Group, Kind: "ArgoCDExport"}, r.Name, allErrs)
+}
+
+// validateCronSchedule will return an error if the given schedule is not in a format accepted by a Kubernetes CronJob.
+func validateCronSchedule(schedule string) error {
+	schedule = strings.TrimSpace(schedule)
+	if schedule == "" {
+		return fmt.Errorf("schedule must not be empty")
+	}
+
+	if strings.HasPrefix(schedule, "@") {
+		if strings.HasPrefix(schedule, "@every ") {
+			d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(schedule, "@every ")))
+			if err != nil || d <= 0 {
+				return fmt.Errorf("invalid duration in %q", schedule)
+			}
+			return nil
+		}
+		if !containsString(cronDescriptors, schedule) {
+			return fmt.Errorf("unrecognized descriptor %q", schedule)
+		}
+		return nil
+	}
+
+	fields := strings.Fields(schedule)
+	if len(fields) != len(cronFields) {
+		return fmt.Errorf("expected exactly %d fields, found %d", len(cronFields), len(fields))
+	}
+
+	for i, f := range fields {
+		if err := cronFields[i].validate(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validate will return an error if the given value is not valid for the cron field.
+func (c cronField) validate(value string) error {
+	for _, expr := range strings.Split(value, ",") {
+		rng, step := expr, ""
+		if i := strings.Index(expr, "/"); i >= 0 {
+			rng, step = expr[:i], expr[i+1:]
+			if n, err := strconv.Atoi(step); err != nil || n <= 0 {
+				return fmt.Errorf("invalid step %q in %s field", step, c.name)
+			}
+		}
+
+		if rng == "*" || rng == "?" {
+			continue
+		}
+
+		bounds := strings.SplitN(rng, "-", 2)
+		low, err := c.parseValue(bounds[0])
+		if err != nil {
+			return err
+		}
+		high := low
+		if len(bounds) == 2 {
+			if high, err = c.parseValue(bounds[1]); err != nil {
+				return err
+			}
+		}
+		if low > high {
+			return fmt.Errorf("invalid range %q in %s field", rng, c.name)
+		}
+	}
+	return nil
+}
+
+// parseValue will return the numeric value for a single cron field value.
+func (c cronField) parseValue(value string) (int, error) {
+	if n, ok := c.names[strings.ToLower(value)]; ok {
+		return n, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q in %s field", value, c.name)
+	}
+	if n < c.min || n > c.max {
+		return 0, fmt.Errorf("value %d out of range [%d-%d] in %s field", n, c.min, c.max, c.name)
+	}
+	return n, nil
+}
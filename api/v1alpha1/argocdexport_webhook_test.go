@@ -0,0 +1,78 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/argoproj-labs/argocd-operator/common"
+)
+
+func Test_ArgoCDExport_ValidateCreate_Backend(t *testing.T) {
+	tests := []struct {
+		backend string
+		wantErr bool
+	}{
+		{"", false},
+		{common.ArgoCDExportStorageBackendLocal, false},
+		{common.ArgoCDExportStorageBackendAWS, false},
+		{common.ArgoCDExportStorageBackendAzure, false},
+		{common.ArgoCDExportStorageBackendGCP, false},
+		{"s3", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.backend, func(t *testing.T) {
+			cr := &ArgoCDExport{Spec: ArgoCDExportSpec{
+				Storage: &ArgoCDExportStorageSpec{Backend: test.backend},
+			}}
+
+			err := cr.ValidateCreate()
+			if test.wantErr {
+				assert.True(t, apierrors.IsInvalid(err))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_ArgoCDExport_ValidateCreate_Schedule(t *testing.T) {
+	tests := []struct {
+		schedule string
+		wantErr  bool
+	}{
+		{"0 0 * * *", false},
+		{"*/15 * * * *", false},
+		{"0 9-17/2 * * mon-fri", false},
+		{"30 2 1,15 JAN,jul *", false},
+		{"@daily", false},
+		{"@every 1h30m", false},
+		{"", true},
+		{"0 0 * *", true},
+		{"0 0 * * * *", true},
+		{"60 * * * *", true},
+		{"0 24 * * *", true},
+		{"0 0 0 * *", true},
+		{"*/0 * * * *", true},
+		{"0 5-1 * * *", true},
+		{"0 0 * foo *", true},
+		{"@fortnightly", true},
+		{"@every soon", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.schedule, func(t *testing.T) {
+			schedule := test.schedule
+			cr := &ArgoCDExport{Spec: ArgoCDExportSpec{Schedule: &schedule}}
+
+			err := cr.ValidateCreate()
+			if test.wantErr {
+				assert.True(t, apierrors.IsInvalid(err))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
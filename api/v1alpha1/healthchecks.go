@@ -0,0 +1,51 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/argoproj-labs/argocd-operator/common"
+)
+
+// GetHealthChecksPath will return the directory of the health check catalog bundled with the operator.
+func GetHealthChecksPath() string {
+	path := os.Getenv("HEALTH_CHECKS_PATH")
+	if len(path) > 0 {
+		return path
+	}
+	return common.ArgoCDDefaultHealthChecksPath
+}
+
+// GetHealthCheckNames will return the sorted names of the bundled health checks, a health check is a file named
+// <name>.yaml in the catalog directory.
+func GetHealthCheckNames() ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(GetHealthChecksPath(), "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(files))
+	for _, f := range files {
+		names = append(names, strings.TrimSuffix(filepath.Base(f), ".yaml"))
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -27,6 +27,7 @@ import (
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -35,7 +36,7 @@ func (in *ArgoCD) DeepCopyInto(out *ArgoCD) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCD.
@@ -108,6 +109,26 @@ func (in *ArgoCDApplicationControllerSpec) DeepCopyInto(out *ArgoCDApplicationCo
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PodTemplateOverride != nil {
+		in, out := &in.PodTemplateOverride, &out.PodTemplateOverride
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scheduling != nil {
+		in, out := &in.Scheduling, &out.Scheduling
+		*out = new(ArgoCDSchedulingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(ArgoCDSecurityContextSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(ArgoCDPodDisruptionBudgetSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDApplicationControllerSpec.
@@ -128,6 +149,26 @@ func (in *ArgoCDApplicationSet) DeepCopyInto(out *ArgoCDApplicationSet) {
 		*out = new(v1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PodTemplateOverride != nil {
+		in, out := &in.PodTemplateOverride, &out.PodTemplateOverride
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scheduling != nil {
+		in, out := &in.Scheduling, &out.Scheduling
+		*out = new(ArgoCDSchedulingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(ArgoCDSecurityContextSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(ArgoCDPodDisruptionBudgetSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDApplicationSet.
@@ -170,6 +211,21 @@ func (in *ArgoCDCertificateSpec) DeepCopy() *ArgoCDCertificateSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDConfigMigration) DeepCopyInto(out *ArgoCDConfigMigration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDConfigMigration.
+func (in *ArgoCDConfigMigration) DeepCopy() *ArgoCDConfigMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDConfigMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ArgoCDDexOAuthSpec) DeepCopyInto(out *ArgoCDDexOAuthSpec) {
 	*out = *in
@@ -198,6 +254,26 @@ func (in *ArgoCDDexSpec) DeepCopyInto(out *ArgoCDDexSpec) {
 		*out = new(v1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PodTemplateOverride != nil {
+		in, out := &in.PodTemplateOverride, &out.PodTemplateOverride
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scheduling != nil {
+		in, out := &in.Scheduling, &out.Scheduling
+		*out = new(ArgoCDSchedulingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(ArgoCDSecurityContextSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(ArgoCDPodDisruptionBudgetSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDDexSpec.
@@ -210,6 +286,32 @@ func (in *ArgoCDDexSpec) DeepCopy() *ArgoCDDexSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDDriftedResource) DeepCopyInto(out *ArgoCDDriftedResource) {
+	*out = *in
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Managers != nil {
+		in, out := &in.Managers, &out.Managers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.LastDetectedTime.DeepCopyInto(&out.LastDetectedTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDDriftedResource.
+func (in *ArgoCDDriftedResource) DeepCopy() *ArgoCDDriftedResource {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDDriftedResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ArgoCDExport) DeepCopyInto(out *ArgoCDExport) {
 	*out = *in
@@ -277,6 +379,11 @@ func (in *ArgoCDExportSpec) DeepCopyInto(out *ArgoCDExportSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(ArgoCDSecurityContextSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Storage != nil {
 		in, out := &in.Storage, &out.Storage
 		*out = new(ArgoCDExportStorageSpec)
@@ -344,6 +451,21 @@ func (in *ArgoCDGrafanaSpec) DeepCopyInto(out *ArgoCDGrafanaSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.PodTemplateOverride != nil {
+		in, out := &in.PodTemplateOverride, &out.PodTemplateOverride
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scheduling != nil {
+		in, out := &in.Scheduling, &out.Scheduling
+		*out = new(ArgoCDSchedulingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(ArgoCDSecurityContextSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDGrafanaSpec.
@@ -364,6 +486,26 @@ func (in *ArgoCDHASpec) DeepCopyInto(out *ArgoCDHASpec) {
 		*out = new(v1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PodTemplateOverride != nil {
+		in, out := &in.PodTemplateOverride, &out.PodTemplateOverride
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scheduling != nil {
+		in, out := &in.Scheduling, &out.Scheduling
+		*out = new(ArgoCDSchedulingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(ArgoCDSecurityContextSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(ArgoCDPodDisruptionBudgetSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDHASpec.
@@ -376,6 +518,31 @@ func (in *ArgoCDHASpec) DeepCopy() *ArgoCDHASpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDHealthChecksSpec) DeepCopyInto(out *ArgoCDHealthChecksSpec) {
+	*out = *in
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CrossplaneClaims != nil {
+		in, out := &in.CrossplaneClaims, &out.CrossplaneClaims
+		*out = make([]metav1.GroupKind, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDHealthChecksSpec.
+func (in *ArgoCDHealthChecksSpec) DeepCopy() *ArgoCDHealthChecksSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDHealthChecksSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ArgoCDImportSpec) DeepCopyInto(out *ArgoCDImportSpec) {
 	*out = *in
@@ -457,6 +624,35 @@ func (in *ArgoCDList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDNetworkPolicySpec) DeepCopyInto(out *ArgoCDNetworkPolicySpec) {
+	*out = *in
+	if in.MetricsFrom != nil {
+		in, out := &in.MetricsFrom, &out.MetricsFrom
+		*out = make([]networkingv1.NetworkPolicyPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ServerFrom != nil {
+		in, out := &in.ServerFrom, &out.ServerFrom
+		*out = make([]networkingv1.NetworkPolicyPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDNetworkPolicySpec.
+func (in *ArgoCDNetworkPolicySpec) DeepCopy() *ArgoCDNetworkPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDNetworkPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ArgoCDNodePlacementSpec) DeepCopyInto(out *ArgoCDNodePlacementSpec) {
 	*out = *in
@@ -486,6 +682,67 @@ func (in *ArgoCDNodePlacementSpec) DeepCopy() *ArgoCDNodePlacementSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDPatch) DeepCopyInto(out *ArgoCDPatch) {
+	*out = *in
+	out.Target = in.Target
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDPatch.
+func (in *ArgoCDPatch) DeepCopy() *ArgoCDPatch {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDPatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDPatchTarget) DeepCopyInto(out *ArgoCDPatchTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDPatchTarget.
+func (in *ArgoCDPatchTarget) DeepCopy() *ArgoCDPatchTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDPatchTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDPodDisruptionBudgetSpec) DeepCopyInto(out *ArgoCDPodDisruptionBudgetSpec) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDPodDisruptionBudgetSpec.
+func (in *ArgoCDPodDisruptionBudgetSpec) DeepCopy() *ArgoCDPodDisruptionBudgetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDPodDisruptionBudgetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ArgoCDPrometheusSpec) DeepCopyInto(out *ArgoCDPrometheusSpec) {
 	*out = *in
@@ -546,6 +803,26 @@ func (in *ArgoCDRedisSpec) DeepCopyInto(out *ArgoCDRedisSpec) {
 		*out = new(v1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PodTemplateOverride != nil {
+		in, out := &in.PodTemplateOverride, &out.PodTemplateOverride
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scheduling != nil {
+		in, out := &in.Scheduling, &out.Scheduling
+		*out = new(ArgoCDSchedulingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(ArgoCDSecurityContextSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(ArgoCDPodDisruptionBudgetSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDRedisSpec.
@@ -611,6 +888,26 @@ func (in *ArgoCDRepoSpec) DeepCopyInto(out *ArgoCDRepoSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PodTemplateOverride != nil {
+		in, out := &in.PodTemplateOverride, &out.PodTemplateOverride
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scheduling != nil {
+		in, out := &in.Scheduling, &out.Scheduling
+		*out = new(ArgoCDSchedulingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(ArgoCDSecurityContextSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(ArgoCDPodDisruptionBudgetSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDRepoSpec.
@@ -665,6 +962,11 @@ func (in *ArgoCDRouteSpec) DeepCopy() *ArgoCDRouteSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ArgoCDSSOSpec) DeepCopyInto(out *ArgoCDSSOSpec) {
 	*out = *in
+	if in.PodTemplateOverride != nil {
+		in, out := &in.PodTemplateOverride, &out.PodTemplateOverride
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Resources != nil {
 		in, out := &in.Resources, &out.Resources
 		*out = new(v1.ResourceRequirements)
@@ -687,6 +989,63 @@ func (in *ArgoCDSSOSpec) DeepCopy() *ArgoCDSSOSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDSchedulingSpec) DeepCopyInto(out *ArgoCDSchedulingSpec) {
+	*out = *in
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RuntimeClassName != nil {
+		in, out := &in.RuntimeClassName, &out.RuntimeClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]v1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDSchedulingSpec.
+func (in *ArgoCDSchedulingSpec) DeepCopy() *ArgoCDSchedulingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDSchedulingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDSecurityContextSpec) DeepCopyInto(out *ArgoCDSecurityContextSpec) {
+	*out = *in
+	if in.Container != nil {
+		in, out := &in.Container, &out.Container
+		*out = new(v1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Pod != nil {
+		in, out := &in.Pod, &out.Pod
+		*out = new(v1.PodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDSecurityContextSpec.
+func (in *ArgoCDSecurityContextSpec) DeepCopy() *ArgoCDSecurityContextSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDSecurityContextSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ArgoCDServerAutoscaleSpec) DeepCopyInto(out *ArgoCDServerAutoscaleSpec) {
 	*out = *in
@@ -768,6 +1127,26 @@ func (in *ArgoCDServerSpec) DeepCopyInto(out *ArgoCDServerSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.PodTemplateOverride != nil {
+		in, out := &in.PodTemplateOverride, &out.PodTemplateOverride
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scheduling != nil {
+		in, out := &in.Scheduling, &out.Scheduling
+		*out = new(ArgoCDSchedulingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityContext != nil {
+		in, out := &in.SecurityContext, &out.SecurityContext
+		*out = new(ArgoCDSecurityContextSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(ArgoCDPodDisruptionBudgetSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDServerSpec.
@@ -788,10 +1167,67 @@ func (in *ArgoCDSpec) DeepCopyInto(out *ArgoCDSpec) {
 		*out = new(ArgoCDApplicationSet)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CommonAnnotations != nil {
+		in, out := &in.CommonAnnotations, &out.CommonAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CommonLabels != nil {
+		in, out := &in.CommonLabels, &out.CommonLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CommonPodAnnotations != nil {
+		in, out := &in.CommonPodAnnotations, &out.CommonPodAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CommonPodLabels != nil {
+		in, out := &in.CommonPodLabels, &out.CommonPodLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	in.Controller.DeepCopyInto(&out.Controller)
 	in.Dex.DeepCopyInto(&out.Dex)
+	if in.ExcludeDefaultResources != nil {
+		in, out := &in.ExcludeDefaultResources, &out.ExcludeDefaultResources
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ExcludedResources != nil {
+		in, out := &in.ExcludedResources, &out.ExcludedResources
+		*out = make([]ResourceFilter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	in.Grafana.DeepCopyInto(&out.Grafana)
 	in.HA.DeepCopyInto(&out.HA)
+	if in.HealthChecks != nil {
+		in, out := &in.HealthChecks, &out.HealthChecks
+		*out = new(ArgoCDHealthChecksSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.IncludedResources != nil {
+		in, out := &in.IncludedResources, &out.IncludedResources
+		*out = make([]ResourceFilter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Import != nil {
 		in, out := &in.Import, &out.Import
 		*out = new(ArgoCDImportSpec)
@@ -803,15 +1239,32 @@ func (in *ArgoCDSpec) DeepCopyInto(out *ArgoCDSpec) {
 		*out = make([]KustomizeVersionSpec, len(*in))
 		copy(*out, *in)
 	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(ArgoCDNetworkPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.NodePlacement != nil {
 		in, out := &in.NodePlacement, &out.NodePlacement
 		*out = new(ArgoCDNodePlacementSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Patches != nil {
+		in, out := &in.Patches, &out.Patches
+		*out = make([]ArgoCDPatch, len(*in))
+		copy(*out, *in)
+	}
 	in.Prometheus.DeepCopyInto(&out.Prometheus)
 	in.RBAC.DeepCopyInto(&out.RBAC)
 	in.Redis.DeepCopyInto(&out.Redis)
 	in.Repo.DeepCopyInto(&out.Repo)
+	if in.ResourceOverrides != nil {
+		in, out := &in.ResourceOverrides, &out.ResourceOverrides
+		*out = make([]ResourceOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	in.Server.DeepCopyInto(&out.Server)
 	if in.SSO != nil {
 		in, out := &in.SSO, &out.SSO
@@ -819,6 +1272,11 @@ func (in *ArgoCDSpec) DeepCopyInto(out *ArgoCDSpec) {
 		(*in).DeepCopyInto(*out)
 	}
 	in.TLS.DeepCopyInto(&out.TLS)
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(ArgoCDUpgradeSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Banner != nil {
 		in, out := &in.Banner, &out.Banner
 		*out = new(Banner)
@@ -839,6 +1297,37 @@ func (in *ArgoCDSpec) DeepCopy() *ArgoCDSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ArgoCDStatus) DeepCopyInto(out *ArgoCDStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(ArgoCDUpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigMigrations != nil {
+		in, out := &in.ConfigMigrations, &out.ConfigMigrations
+		*out = make([]ArgoCDConfigMigration, len(*in))
+		copy(*out, *in)
+	}
+	if in.DriftedResources != nil {
+		in, out := &in.DriftedResources, &out.DriftedResources
+		*out = make([]ArgoCDDriftedResource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDStatus.
@@ -874,6 +1363,42 @@ func (in *ArgoCDTLSSpec) DeepCopy() *ArgoCDTLSSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDUpgradeSpec) DeepCopyInto(out *ArgoCDUpgradeSpec) {
+	*out = *in
+	if in.ProgressDeadline != nil {
+		in, out := &in.ProgressDeadline, &out.ProgressDeadline
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDUpgradeSpec.
+func (in *ArgoCDUpgradeSpec) DeepCopy() *ArgoCDUpgradeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDUpgradeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDUpgradeStatus) DeepCopyInto(out *ArgoCDUpgradeStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDUpgradeStatus.
+func (in *ArgoCDUpgradeStatus) DeepCopy() *ArgoCDUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Banner) DeepCopyInto(out *Banner) {
 	*out = *in
@@ -889,6 +1414,21 @@ func (in *Banner) DeepCopy() *Banner {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KnownTypeField) DeepCopyInto(out *KnownTypeField) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KnownTypeField.
+func (in *KnownTypeField) DeepCopy() *KnownTypeField {
+	if in == nil {
+		return nil
+	}
+	out := new(KnownTypeField)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KustomizeVersionSpec) DeepCopyInto(out *KustomizeVersionSpec) {
 	*out = *in
@@ -904,6 +1444,131 @@ func (in *KustomizeVersionSpec) DeepCopy() *KustomizeVersionSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceAction) DeepCopyInto(out *ResourceAction) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceAction.
+func (in *ResourceAction) DeepCopy() *ResourceAction {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceActions) DeepCopyInto(out *ResourceActions) {
+	*out = *in
+	if in.Definitions != nil {
+		in, out := &in.Definitions, &out.Definitions
+		*out = make([]ResourceAction, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceActions.
+func (in *ResourceActions) DeepCopy() *ResourceActions {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceActions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceFilter) DeepCopyInto(out *ResourceFilter) {
+	*out = *in
+	if in.APIGroups != nil {
+		in, out := &in.APIGroups, &out.APIGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Kinds != nil {
+		in, out := &in.Kinds, &out.Kinds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceFilter.
+func (in *ResourceFilter) DeepCopy() *ResourceFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceIgnoreDifferences) DeepCopyInto(out *ResourceIgnoreDifferences) {
+	*out = *in
+	if in.JSONPointers != nil {
+		in, out := &in.JSONPointers, &out.JSONPointers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.JQPathExpressions != nil {
+		in, out := &in.JQPathExpressions, &out.JQPathExpressions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ManagedFieldsManagers != nil {
+		in, out := &in.ManagedFieldsManagers, &out.ManagedFieldsManagers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceIgnoreDifferences.
+func (in *ResourceIgnoreDifferences) DeepCopy() *ResourceIgnoreDifferences {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceIgnoreDifferences)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceOverride) DeepCopyInto(out *ResourceOverride) {
+	*out = *in
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = new(ResourceActions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IgnoreDifferences != nil {
+		in, out := &in.IgnoreDifferences, &out.IgnoreDifferences
+		*out = new(ResourceIgnoreDifferences)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KnownTypeFields != nil {
+		in, out := &in.KnownTypeFields, &out.KnownTypeFields
+		*out = make([]KnownTypeField, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceOverride.
+func (in *ResourceOverride) DeepCopy() *ResourceOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SSHHostsSpec) DeepCopyInto(out *SSHHostsSpec) {
 	*out = *in
@@ -9,6 +9,22 @@ const (
 	// namespace a specific object is associated with
 	AnnotationNamespace = "argocds.argoproj.io/namespace"
 
+	// AnnotationCommonMetadata is the annotation on child resources that are written with client-side updates that
+	// records the keys of the common labels and annotations that were set, so that they can be removed again
+	AnnotationCommonMetadata = "argocds.argoproj.io/common-metadata"
+
+	// AnnotationImagePullSecrets is the annotation on ServiceAccounts that records the names of the image pull secrets
+	// that were added by the operator, so that they can be removed again
+	AnnotationImagePullSecrets = "argocds.argoproj.io/image-pull-secrets"
+
+	// AnnotationConfigChecksum is the annotation on the pod templates of the components that contains the checksum of
+	// the ConfigMaps and Secrets consumed by the pods, so that the pods are rolled out when one of them changes
+	AnnotationConfigChecksum = "argocds.argoproj.io/config-checksum"
+
+	// AnnotationMaterializeDefaults is the annotation on ArgoCD resources that opts in to having the effective
+	// defaults written into the spec by the defaulting webhook
+	AnnotationMaterializeDefaults = "argocds.argoproj.io/materialize-defaults"
+
 	// AnnotationOpenShiftServiceCA is the annotation on services used to
 	// request a TLS certificate from OpenShift's Service CA for AutoTLS
 	AnnotationOpenShiftServiceCA = "service.beta.openshift.io/serving-cert-secret-name"
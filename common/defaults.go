@@ -160,6 +160,9 @@ const (
 	// ArgoCDDefaultGrafanaVersion is the Grafana container image tag to use when not specified.
 	ArgoCDDefaultGrafanaVersion = "sha256:afef23a1b4cf159ec3180aac3ad693c10e560657313bfe3ec81f344ace6d2f05" // 6.7.2
 
+	// ArgoCDDefaultHealthChecksPath is the default directory of the bundled health check catalog when not specified.
+	ArgoCDDefaultHealthChecksPath = "/var/lib/healthchecks"
+
 	// ArgoCDDefaultHelpChatURL is the default help chat URL.
 	ArgoCDDefaultHelpChatURL = "https://mycorp.slack.com/argo-cd"
 
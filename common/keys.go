@@ -85,6 +85,9 @@ const (
 	// ArgoCDKeyIngressSSLPassthrough is the ssl passthrough key for labels.
 	ArgoCDKeyIngressSSLPassthrough = "nginx.ingress.kubernetes.io/ssl-passthrough"
 
+	// ArgoCDKeyKeycloakClientSecret is the Argo CD Secret key for the OIDC client secret of Keycloak.
+	ArgoCDKeyKeycloakClientSecret = "oidc.keycloak.clientSecret"
+
 	// ArgoCDKeyKustomizeBuildOptions is the configuration key for the kustomize build options.
 	ArgoCDKeyKustomizeBuildOptions = "kustomize.buildOptions"
 
@@ -173,6 +176,9 @@ const (
 	// ArgoCDKeyTolerateUnreadyEndpounts is the resource tolerate unready endpoints key for labels.
 	ArgoCDKeyTolerateUnreadyEndpounts = "service.alpha.kubernetes.io/tolerate-unready-endpoints"
 
+	// ArgoCDKeyTopologyZone is the topology zone key for labels.
+	ArgoCDKeyTopologyZone = "topology.kubernetes.io/zone"
+
 	// ArgoCDKeyUsersAnonymousEnabled is the configuration key for anonymous user access.
 	ArgoCDKeyUsersAnonymousEnabled = "users.anonymous.enabled"
 
@@ -188,6 +194,10 @@ const (
 	// to used for the argocd container.
 	ArgoCDImageEnvName = "ARGOCD_IMAGE"
 
+	// ArgoCDImageRegistryEnvName is the environment variable used to get the registry
+	// that mirrors the public registries of the images of all instances.
+	ArgoCDImageRegistryEnvName = "ARGOCD_IMAGE_REGISTRY"
+
 	// ArgoCDKeycloakImageEnvName is the environment variable used to get the image
 	// to used for the Keycloak container.
 	ArgoCDKeycloakImageEnvName = "ARGOCD_KEYCLOAK_IMAGE"
@@ -208,6 +218,42 @@ const (
 	// to used for the Grafana container.
 	ArgoCDGrafanaImageEnvName = "ARGOCD_GRAFANA_IMAGE"
 
+	// ArgoCDRelatedImageEnvName is the environment variable used by OLM to set the
+	// image to use for the argocd container.
+	ArgoCDRelatedImageEnvName = "RELATED_IMAGE_ARGOCD"
+
+	// ArgoCDApplicationSetRelatedImageEnvName is the environment variable used by OLM
+	// to set the image to use for the ApplicationSet controller.
+	ArgoCDApplicationSetRelatedImageEnvName = "RELATED_IMAGE_APPLICATIONSET"
+
+	// ArgoCDDexRelatedImageEnvName is the environment variable used by OLM to set the
+	// image to use for the Dex container.
+	ArgoCDDexRelatedImageEnvName = "RELATED_IMAGE_DEX"
+
+	// ArgoCDExportRelatedImageEnvName is the environment variable used by OLM to set
+	// the image to use for the export and import containers.
+	ArgoCDExportRelatedImageEnvName = "RELATED_IMAGE_EXPORT"
+
+	// ArgoCDGrafanaRelatedImageEnvName is the environment variable used by OLM to set
+	// the image to use for the Grafana container.
+	ArgoCDGrafanaRelatedImageEnvName = "RELATED_IMAGE_GRAFANA"
+
+	// ArgoCDKeycloakRelatedImageEnvName is the environment variable used by OLM to set
+	// the image to use for the Keycloak container.
+	ArgoCDKeycloakRelatedImageEnvName = "RELATED_IMAGE_KEYCLOAK"
+
+	// ArgoCDRedisRelatedImageEnvName is the environment variable used by OLM to set
+	// the image to use for the Redis container.
+	ArgoCDRedisRelatedImageEnvName = "RELATED_IMAGE_REDIS"
+
+	// ArgoCDRedisHARelatedImageEnvName is the environment variable used by OLM to set
+	// the image to use for the Redis container in HA mode.
+	ArgoCDRedisHARelatedImageEnvName = "RELATED_IMAGE_REDIS_HA"
+
+	// ArgoCDRedisHAProxyRelatedImageEnvName is the environment variable used by OLM
+	// to set the image to use for the Redis HA Proxy container.
+	ArgoCDRedisHAProxyRelatedImageEnvName = "RELATED_IMAGE_REDIS_HA_PROXY"
+
 	// ArgoCDDeletionFinalizer is a finalizer to implement pre-delete hooks
 	ArgoCDDeletionFinalizer = "argoproj.io/finalizer"
 
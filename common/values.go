@@ -29,6 +29,10 @@ const (
 	// ArgoCDGPGKeysConfigMapName is the upstream hard-coded ArgoCD gpg-keys ConfigMap name.
 	ArgoCDGPGKeysConfigMapName = "argocd-gpg-keys-cm"
 
+	// ArgoCDDriftRetention is the duration that a drifted resource is listed in the status of an ArgoCD after drift was
+	// last detected for it.
+	ArgoCDDriftRetention = time.Hour
+
 	// ArgoCDDuration365Days is a duration representing 365 days.
 	ArgoCDDuration365Days = time.Hour * 24 * 365
 
@@ -53,9 +57,23 @@ const (
 	// ArgoCDGrafanaDashboardConfigMapSuffix is the default suffix for the Grafana dashboards ConfigMap.
 	ArgoCDGrafanaDashboardConfigMapSuffix = "grafana-dashboards"
 
+	// ArgoCDFieldManager is the field manager used by the operator to server-side apply the resources of an ArgoCD.
+	ArgoCDFieldManager = "argocd-operator"
+
 	// ArgoCDKnownHostsConfigMapName is the upstream hard-coded SSH known hosts data ConfigMap name.
 	ArgoCDKnownHostsConfigMapName = "argocd-ssh-known-hosts-cm"
 
+	// ArgoCDLegacyFieldManager is the field manager that the API server recorded for the client-side updates of
+	// earlier versions of the operator, it is derived from the name of the operator binary. The name is shared by other
+	// controllers, see argoutil.ApplyObject for how the entries of the operator are told apart.
+	ArgoCDLegacyFieldManager = "manager"
+
+	// ArgoCDReconcileBaseDelay is the delay before a failed reconciliation of an ArgoCD is retried for the first time.
+	ArgoCDReconcileBaseDelay = time.Second * 5
+
+	// ArgoCDReconcileMaxDelay is the maximum delay before a failed reconciliation of an ArgoCD is retried.
+	ArgoCDReconcileMaxDelay = time.Minute * 5
+
 	// ArgoCDRedisHAConfigMapName is the upstream ArgoCD Redis HA ConfigMap name.
 	ArgoCDRedisHAConfigMapName = "argocd-redis-ha-configmap"
 
@@ -77,6 +95,18 @@ const (
 	// ArgoCDTLSCertsConfigMapName is the upstream hard-coded TLS certificate data ConfigMap name.
 	ArgoCDTLSCertsConfigMapName = "argocd-tls-certs-cm"
 
+	// ArgoCDUpgradeProgressDeadline is the default time that a component has to become ready with a new version during
+	// a managed upgrade, before the upgrade is rolled back.
+	ArgoCDUpgradeProgressDeadline = time.Minute * 10
+
+	// ArgoCDUpgradeRestartThreshold is the number of restarts after which a container in CrashLoopBackOff fails a
+	// managed upgrade.
+	ArgoCDUpgradeRestartThreshold = 3
+
+	// ArgoCDUpgradeRequeueDelay is the delay between the reconciliations of an ArgoCD while a managed upgrade is
+	// progressing, to check whether the component that is rolled out has become ready.
+	ArgoCDUpgradeRequeueDelay = time.Second * 30
+
 	// ArgoCDRepoServerTLSSecretName is the name of the TLS secret for the repo-server
 	ArgoCDRepoServerTLSSecretName = "argocd-repo-server-tls"
 
@@ -17,8 +17,6 @@ package argocd
 import (
 	"context"
 	"fmt"
-	"os"
-	"reflect"
 
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/rbac/v1"
@@ -148,7 +146,7 @@ func (r *ReconcileArgoCD) reconcileApplicationSetDeployment(cr *argoprojv1a1.Arg
 		Command:         getArgoApplicationSetCommand(cr),
 		Env:             appSetEnv,
 		Image:           getApplicationSetContainerImage(cr),
-		ImagePullPolicy: corev1.PullAlways,
+		ImagePullPolicy: getImagePullPolicy(cr, cr.Spec.ApplicationSet.ImagePullPolicy, getApplicationSetContainerImage(cr)),
 		Name:            "argocd-applicationset-controller",
 		Resources:       getApplicationSetResources(cr),
 		VolumeMounts: []corev1.VolumeMount{
@@ -175,39 +173,21 @@ func (r *ReconcileArgoCD) reconcileApplicationSetDeployment(cr *argoprojv1a1.Arg
 		},
 	}}
 
-	if existing := newDeploymentWithSuffix("applicationset-controller", "controller", cr); argoutil.IsObjectFound(r.Client, cr.Namespace, existing.Name, existing) {
-
-		existingSpec := existing.Spec.Template.Spec
-
-		deploymentsDifferent := !reflect.DeepEqual(existingSpec.Containers[0], podSpec.Containers) ||
-			!reflect.DeepEqual(existingSpec.Volumes, podSpec.Volumes) ||
-			existingSpec.ServiceAccountName != podSpec.ServiceAccountName ||
-			!reflect.DeepEqual(existing.Labels, deploy.Labels) ||
-			!reflect.DeepEqual(existing.Spec.Template.Labels, deploy.Spec.Template.Labels) ||
-			!reflect.DeepEqual(existing.Spec.Selector, deploy.Spec.Selector) ||
-			!reflect.DeepEqual(existing.Spec.Template.Spec.NodeSelector, deploy.Spec.Template.Spec.NodeSelector) ||
-			!reflect.DeepEqual(existing.Spec.Template.Spec.Tolerations, deploy.Spec.Template.Spec.Tolerations)
-
-		// If the Deployment already exists, make sure the values we care about are up-to-date
-		if deploymentsDifferent {
-			existing.Spec.Template.Spec.Containers = podSpec.Containers
-			existing.Spec.Template.Spec.Volumes = podSpec.Volumes
-			existing.Spec.Template.Spec.ServiceAccountName = podSpec.ServiceAccountName
-			existing.Labels = deploy.Labels
-			existing.Spec.Template.Labels = deploy.Spec.Template.Labels
-			existing.Spec.Selector = deploy.Spec.Selector
-			existing.Spec.Template.Spec.NodeSelector = deploy.Spec.Template.Spec.NodeSelector
-			existing.Spec.Template.Spec.Tolerations = deploy.Spec.Template.Spec.Tolerations
-			return r.Client.Update(context.TODO(), existing)
-		}
-		return nil // Deployment found with nothing to do, move along...
+	applyScheduling(&deploy.Spec.Template, cr.Spec.ApplicationSet.Scheduling)
+
+	if err := argoutil.ApplySecurityContext(&deploy.Spec.Template.Spec, false, cr.Spec.ApplicationSet.SecurityContext); err != nil {
+		return err
+	}
+
+	if err := applyPodTemplateOverride(&deploy.Spec.Template, cr.Spec.ApplicationSet.PodTemplateOverride); err != nil {
+		return err
 	}
 
-	if err := controllerutil.SetControllerReference(cr, deploy, r.Scheme); err != nil {
+	if err := r.setConfigChecksum(cr, &deploy.Spec.Template, getApplicationSetConfigInputs(cr)); err != nil {
 		return err
 	}
-	return r.Client.Create(context.TODO(), deploy)
 
+	return r.applyResource(cr, deploy)
 }
 
 func (r *ReconcileArgoCD) reconcileApplicationSetServiceAccount(cr *argoprojv1a1.ArgoCD) (*corev1.ServiceAccount, error) {
@@ -224,9 +204,15 @@ func (r *ReconcileArgoCD) reconcileApplicationSetServiceAccount(cr *argoprojv1a1
 	}
 
 	if exists {
+		changed := syncCommonMetadata(cr, sa)
+		if syncImagePullSecrets(cr, sa) || changed {
+			return sa, r.Client.Update(context.TODO(), sa)
+		}
 		return sa, nil
 	}
 
+	syncCommonMetadata(cr, sa)
+	syncImagePullSecrets(cr, sa)
 	if err := controllerutil.SetControllerReference(cr, sa, r.Scheme); err != nil {
 		return nil, err
 	}
@@ -328,6 +314,10 @@ func (r *ReconcileArgoCD) reconcileApplicationSetRole(cr *argoprojv1a1.ArgoCD) (
 		if !errors.IsNotFound(err) {
 			return nil, fmt.Errorf("failed to reconcile the role for the service account associated with %s : %s", role.Name, err)
 		}
+		syncCommonMetadata(cr, role)
+		if err = r.applyPatches(cr, role); err != nil {
+			return nil, err
+		}
 		if err = controllerutil.SetControllerReference(cr, role, r.Scheme); err != nil {
 			return nil, err
 		}
@@ -335,6 +325,10 @@ func (r *ReconcileArgoCD) reconcileApplicationSetRole(cr *argoprojv1a1.ArgoCD) (
 	}
 
 	role.Rules = policyRules
+	syncCommonMetadata(cr, role)
+	if err = r.applyPatches(cr, role); err != nil {
+		return nil, err
+	}
 	if err = controllerutil.SetControllerReference(cr, role, r.Scheme); err != nil {
 		return nil, err
 	}
@@ -373,6 +367,11 @@ func (r *ReconcileArgoCD) reconcileApplicationSetRoleBinding(cr *argoprojv1a1.Ar
 		},
 	}
 
+	syncCommonMetadata(cr, roleBinding)
+	if err := r.applyPatches(cr, roleBinding); err != nil {
+		return err
+	}
+
 	if err := controllerutil.SetControllerReference(cr, roleBinding, r.Scheme); err != nil {
 		return err
 	}
@@ -385,32 +384,13 @@ func (r *ReconcileArgoCD) reconcileApplicationSetRoleBinding(cr *argoprojv1a1.Ar
 }
 
 func getApplicationSetContainerImage(cr *argoprojv1a1.ArgoCD) string {
-	defaultImg, defaultTag := false, false
-
-	img := ""
-	tag := ""
-
-	// First pull from spec, if it exists
+	img, tag := "", ""
 	if cr.Spec.ApplicationSet != nil {
-		img = cr.Spec.ApplicationSet.Image
-		tag = cr.Spec.ApplicationSet.Version
-	}
-
-	// If spec is empty, use the defaults
-	if img == "" {
-		img = common.ArgoCDDefaultApplicationSetImage
-		defaultImg = true
-	}
-	if tag == "" {
-		tag = common.ArgoCDDefaultApplicationSetVersion
-		defaultTag = true
-	}
-
-	// If an env var is specified then use that, but don't override the spec values (if they are present)
-	if e := os.Getenv(common.ArgoCDApplicationSetEnvName); e != "" && (defaultTag && defaultImg) {
-		return e
+		img, tag = cr.Spec.ApplicationSet.Image, cr.Spec.ApplicationSet.Version
 	}
-	return argoutil.CombineImageTag(img, tag)
+	return mirrorImage(cr, argoutil.ContainerImage(img, tag,
+		common.ArgoCDDefaultApplicationSetImage, common.ArgoCDDefaultApplicationSetVersion,
+		common.ArgoCDApplicationSetEnvName, common.ArgoCDApplicationSetRelatedImageEnvName))
 }
 
 // getApplicationSetResources will return the ResourceRequirements for the Application Sets container.
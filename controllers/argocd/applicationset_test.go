@@ -112,6 +112,7 @@ func checkExpectedDeploymentValues(t *testing.T, deployment *appsv1.Deployment,
 		Image:           argoutil.CombineImageTag(common.ArgoCDDefaultApplicationSetImage, common.ArgoCDDefaultApplicationSetVersion),
 		ImagePullPolicy: corev1.PullAlways,
 		Name:            "argocd-applicationset-controller",
+		SecurityContext: argoutil.RestrictedSecurityContext(false),
 		VolumeMounts:    applicationSetDefaultVolumeMounts(),
 	}}
 
@@ -328,7 +329,8 @@ func TestReconcileApplicationSet_Deployments_resourceRequirements(t *testing.T)
 				corev1.ResourceCPU:    resourcev1.MustParse("2000m"),
 			},
 		},
-		VolumeMounts: applicationSetDefaultVolumeMounts(),
+		SecurityContext: argoutil.RestrictedSecurityContext(false),
+		VolumeMounts:    applicationSetDefaultVolumeMounts(),
 	}}
 
 	if diff := cmp.Diff(containerWant, deployment.Spec.Template.Spec.Containers); diff != "" {
@@ -21,13 +21,17 @@ import (
 	"fmt"
 
 	argoproj "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/common"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/util/workqueue"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	logr "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -53,8 +57,9 @@ var log = logr.Log.WithName("controller_argocd")
 //+kubebuilder:rbac:groups=argoproj.io,resources=argocds;argocds/finalizers;argocds/status,verbs=*
 //+kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=*
 //+kubebuilder:rbac:groups=batch,resources=cronjobs;jobs,verbs=*
-//+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=*
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses;networkpolicies,verbs=*
 //+kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheuses;servicemonitors,verbs=*
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=*
 //+kubebuilder:rbac:groups=route.openshift.io,resources=routes;routes/custom-host,verbs=*
 //+kubebuilder:rbac:groups=argoproj.io,resources=applications;appprojects,verbs=*
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=*,verbs=*
@@ -119,18 +124,40 @@ func (r *ReconcileArgoCD) Reconcile(ctx context.Context, request ctrl.Request) (
 		return reconcile.Result{}, err
 	}
 
-	if err := r.reconcileResources(argocd); err != nil {
-		// Error reconciling ArgoCD sub-resources - requeue the request.
+	// the status is computed in memory during the reconciliation and written once at the end
+	original := argocd.DeepCopy()
+
+	reconcileErr := r.reconcileResources(argocd)
+
+	// the status is always written, even if it could not be reconciled completely, so that the fields set by the
+	// steps that succeeded are not lost
+	log.Info("reconciling status")
+	statusErr := r.reconcileStatus(argocd, reconcileErr)
+	if err := r.updateStatus(argocd, original); err != nil {
+		reqLogger.Error(err, "failed to update status")
+		statusErr = utilerrors.NewAggregate([]error{statusErr, err})
+	}
+
+	if err := utilerrors.NewAggregate([]error{reconcileErr, statusErr}); err != nil {
+		// Error reconciling ArgoCD sub-resources or the status - requeue the request.
 		return reconcile.Result{}, err
 	}
 
+	if isUpgradeInProgress(argocd) {
+		// Check whether the upgraded component became ready before the progress deadline.
+		return reconcile.Result{RequeueAfter: common.ArgoCDUpgradeRequeueDelay}, nil
+	}
+
 	// Return and don't requeue
 	return reconcile.Result{}, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ReconcileArgoCD) SetupWithManager(mgr ctrl.Manager) error {
-	bldr := ctrl.NewControllerManagedBy(mgr)
+	bldr := ctrl.NewControllerManagedBy(mgr).WithOptions(controller.Options{
+		// failed reconciliations are retried with an exponential backoff
+		RateLimiter: workqueue.NewItemExponentialFailureRateLimiter(common.ArgoCDReconcileBaseDelay, common.ArgoCDReconcileMaxDelay),
+	})
 	setResourceWatches(bldr, r.clusterResourceMapper, r.tlsSecretMapper, r.namespaceResourceMapper)
 	return bldr.Complete(r)
 }
@@ -0,0 +1,193 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/common"
+	"github.com/argoproj-labs/argocd-operator/controllers/argoutil"
+)
+
+// configInput is a ConfigMap or Secret that is consumed by the pods of a component.
+//
+// The argocd-secret is not an input, the components watch it and pick up changes without a restart, and the server
+// writes to it itself.
+type configInput struct {
+	secret bool
+	name   string
+}
+
+// configMapInput returns the config input for the ConfigMap with the given name.
+func configMapInput(name string) configInput {
+	return configInput{name: name}
+}
+
+// secretInput returns the config input for the Secret with the given name.
+func secretInput(name string) configInput {
+	return configInput{secret: true, name: name}
+}
+
+// getApplicationControllerConfigInputs will return the ConfigMaps and Secrets consumed by the application controller.
+func getApplicationControllerConfigInputs(cr *argoprojv1a1.ArgoCD) []configInput {
+	return []configInput{
+		configMapInput(common.ArgoCDConfigMapName),
+		secretInput(common.ArgoCDRepoServerTLSSecretName),
+	}
+}
+
+// getApplicationSetConfigInputs will return the ConfigMaps and Secrets consumed by the ApplicationSet controller.
+func getApplicationSetConfigInputs(cr *argoprojv1a1.ArgoCD) []configInput {
+	return []configInput{
+		configMapInput(common.ArgoCDKnownHostsConfigMapName),
+		configMapInput(common.ArgoCDTLSCertsConfigMapName),
+		configMapInput(common.ArgoCDGPGKeysConfigMapName),
+		secretInput(common.ArgoCDRepoServerTLSSecretName),
+	}
+}
+
+// getDexConfigInputs will return the ConfigMaps and Secrets consumed by Dex.
+func getDexConfigInputs(cr *argoprojv1a1.ArgoCD) []configInput {
+	return []configInput{
+		configMapInput(common.ArgoCDConfigMapName),
+	}
+}
+
+// getGrafanaConfigInputs will return the ConfigMaps and Secrets consumed by Grafana.
+func getGrafanaConfigInputs(cr *argoprojv1a1.ArgoCD) []configInput {
+	return []configInput{
+		configMapInput(nameWithSuffix(common.ArgoCDGrafanaConfigMapSuffix, cr)),
+		configMapInput(nameWithSuffix(common.ArgoCDGrafanaDashboardConfigMapSuffix, cr)),
+		secretInput(nameWithSuffix("grafana", cr)),
+	}
+}
+
+// getRedisHAConfigInputs will return the ConfigMaps and Secrets consumed by the Redis HA servers.
+func getRedisHAConfigInputs(cr *argoprojv1a1.ArgoCD) []configInput {
+	return []configInput{
+		configMapInput(common.ArgoCDRedisHAConfigMapName),
+		configMapInput(common.ArgoCDRedisHAHealthConfigMapName),
+	}
+}
+
+// getRedisHAProxyConfigInputs will return the ConfigMaps and Secrets consumed by the Redis HA proxy.
+func getRedisHAProxyConfigInputs(cr *argoprojv1a1.ArgoCD) []configInput {
+	return []configInput{
+		configMapInput(common.ArgoCDRedisHAConfigMapName),
+	}
+}
+
+// getRepoServerConfigInputs will return the ConfigMaps and Secrets consumed by the repo server.
+func getRepoServerConfigInputs(cr *argoprojv1a1.ArgoCD) []configInput {
+	return []configInput{
+		configMapInput(common.ArgoCDKnownHostsConfigMapName),
+		configMapInput(common.ArgoCDTLSCertsConfigMapName),
+		configMapInput(common.ArgoCDGPGKeysConfigMapName),
+		secretInput(common.ArgoCDRepoServerTLSSecretName),
+	}
+}
+
+// getServerConfigInputs will return the ConfigMaps and Secrets consumed by the Argo CD server.
+func getServerConfigInputs(cr *argoprojv1a1.ArgoCD) []configInput {
+	return []configInput{
+		configMapInput(common.ArgoCDConfigMapName),
+		configMapInput(common.ArgoCDRBACConfigMapName),
+		configMapInput(common.ArgoCDKnownHostsConfigMapName),
+		configMapInput(common.ArgoCDTLSCertsConfigMapName),
+		configMapInput(getCAConfigMapName(cr)),
+		secretInput(common.ArgoCDRepoServerTLSSecretName),
+		secretInput(common.ArgoCDServerTLSSecretName),
+	}
+}
+
+// setConfigChecksum will annotate the given pod template with the checksum of the given config inputs, so that the
+// pods are rolled out when, and only when, one of the inputs changes.
+func (r *ReconcileArgoCD) setConfigChecksum(cr *argoprojv1a1.ArgoCD, template *corev1.PodTemplateSpec, inputs []configInput) error {
+	checksum, err := r.getConfigChecksum(cr, inputs)
+	if err != nil {
+		return err
+	}
+
+	if template.Annotations == nil {
+		template.Annotations = make(map[string]string)
+	}
+	template.Annotations[common.AnnotationConfigChecksum] = checksum
+	return nil
+}
+
+// getConfigChecksum will return the SHA256 checksum of the data of the given config inputs. An input that does not
+// exist is part of the checksum as well, so that the pods are rolled out once it is created.
+func (r *ReconcileArgoCD) getConfigChecksum(cr *argoprojv1a1.ArgoCD, inputs []configInput) (string, error) {
+	h := sha256.New()
+	for _, input := range inputs {
+		if input.secret {
+			secret := &corev1.Secret{}
+			if err := argoutil.FetchObject(r.Client, cr.Namespace, input.name, secret); err != nil {
+				if !apierrors.IsNotFound(err) {
+					return "", err
+				}
+				fmt.Fprintf(h, "secret/%s absent\x00", input.name)
+				continue
+			}
+
+			fmt.Fprintf(h, "secret/%s\x00", input.name)
+			data := make(map[string]string, len(secret.Data))
+			for k, v := range secret.Data {
+				data[k] = string(v)
+			}
+			writeChecksumData(h, data)
+			continue
+		}
+
+		cm := &corev1.ConfigMap{}
+		if err := argoutil.FetchObject(r.Client, cr.Namespace, input.name, cm); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return "", err
+			}
+			fmt.Fprintf(h, "configmap/%s absent\x00", input.name)
+			continue
+		}
+
+		fmt.Fprintf(h, "configmap/%s\x00", input.name)
+		writeChecksumData(h, cm.Data)
+		data := make(map[string]string, len(cm.BinaryData))
+		for k, v := range cm.BinaryData {
+			data[k] = string(v)
+		}
+		writeChecksumData(h, data)
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// writeChecksumData will write the given data to the given hash, ordered by key.
+func writeChecksumData(h hash.Hash, data map[string]string) {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s\x00%s\x00", k, data[k])
+	}
+	fmt.Fprint(h, "\x00")
+}
@@ -0,0 +1,142 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/argoproj-labs/argocd-operator/common"
+	"github.com/argoproj-labs/argocd-operator/controllers/argoutil"
+)
+
+func TestReconcileArgoCD_getConfigChecksum(t *testing.T) {
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+	inputs := []configInput{configMapInput(common.ArgoCDConfigMapName), secretInput(common.ArgoCDSecretName)}
+
+	absent, err := r.getConfigChecksum(a, inputs)
+	assert.NoError(t, err)
+
+	cm := newConfigMapWithName(common.ArgoCDConfigMapName, a)
+	cm.Data = map[string]string{"admin.enabled": "true", "url": "https://argocd.example.com"}
+	assert.NoError(t, r.Client.Create(context.TODO(), cm))
+
+	created, err := r.getConfigChecksum(a, inputs)
+	assert.NoError(t, err)
+	assert.NotEqual(t, absent, created)
+
+	again, err := r.getConfigChecksum(a, inputs)
+	assert.NoError(t, err)
+	assert.Equal(t, created, again)
+
+	cm.Data["admin.enabled"] = "false"
+	assert.NoError(t, r.Client.Update(context.TODO(), cm))
+
+	changed, err := r.getConfigChecksum(a, inputs)
+	assert.NoError(t, err)
+	assert.NotEqual(t, created, changed)
+
+	// A ConfigMap and a Secret with the same name and data are different inputs
+	secret := argoutil.NewSecretWithName(a, "argocd-example")
+	secret.Data = map[string][]byte{"key": []byte("value")}
+	assert.NoError(t, r.Client.Create(context.TODO(), secret))
+	example := newConfigMapWithName("argocd-example", a)
+	example.Data = map[string]string{"key": "value"}
+	assert.NoError(t, r.Client.Create(context.TODO(), example))
+
+	fromSecret, err := r.getConfigChecksum(a, []configInput{secretInput("argocd-example")})
+	assert.NoError(t, err)
+	fromConfigMap, err := r.getConfigChecksum(a, []configInput{configMapInput("argocd-example")})
+	assert.NoError(t, err)
+	assert.NotEqual(t, fromSecret, fromConfigMap)
+}
+
+func TestReconcileArgoCD_reconcileServerDeployment_configChecksum(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+	assert.NoError(t, r.reconcileArgoConfigMap(a))
+
+	getChecksum := func() string {
+		deploy := &appsv1.Deployment{}
+		assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, nameWithSuffix("server", a), deploy))
+		return deploy.Spec.Template.Annotations[common.AnnotationConfigChecksum]
+	}
+
+	assert.NoError(t, r.reconcileServerDeployment(a))
+	initial := getChecksum()
+	assert.NotEmpty(t, initial)
+
+	// ConfigMaps that are not consumed by the server do not roll it out
+	assert.NoError(t, r.reconcileGPGKeysConfigMap(a))
+	assert.NoError(t, r.reconcileServerDeployment(a))
+	assert.Equal(t, initial, getChecksum())
+
+	// The argocd-secret is watched by the server and does not roll it out
+	argoSecret := argoutil.NewSecretWithName(a, common.ArgoCDSecretName)
+	argoSecret.Data = map[string][]byte{"server.secretkey": []byte("secret")}
+	assert.NoError(t, r.Client.Create(context.TODO(), argoSecret))
+	assert.NoError(t, r.reconcileServerDeployment(a))
+	assert.Equal(t, initial, getChecksum())
+
+	a.Spec.StatusBadgeEnabled = true
+	assert.NoError(t, r.reconcileArgoConfigMap(a))
+	assert.NoError(t, r.reconcileServerDeployment(a))
+	badge := getChecksum()
+	assert.NotEqual(t, initial, badge)
+
+	tlsSecret := argoutil.NewSecretWithName(a, common.ArgoCDServerTLSSecretName)
+	tlsSecret.Type = corev1.SecretTypeTLS
+	tlsSecret.Data = map[string][]byte{corev1.TLSCertKey: []byte("foo"), corev1.TLSPrivateKeyKey: []byte("bar")}
+	assert.NoError(t, r.Client.Create(context.TODO(), tlsSecret))
+	assert.NoError(t, r.reconcileServerDeployment(a))
+	assert.NotEqual(t, badge, getChecksum())
+}
+
+func TestReconcileArgoCD_reconcileRepoDeployment_configChecksum(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	getChecksum := func() string {
+		deploy := &appsv1.Deployment{}
+		assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, nameWithSuffix("repo-server", a), deploy))
+		return deploy.Spec.Template.Annotations[common.AnnotationConfigChecksum]
+	}
+
+	assert.NoError(t, r.reconcileRepoDeployment(a))
+	initial := getChecksum()
+
+	// The argocd-cm is not consumed by the repo server
+	assert.NoError(t, r.reconcileArgoConfigMap(a))
+	assert.NoError(t, r.reconcileRepoDeployment(a))
+	assert.Equal(t, initial, getChecksum())
+
+	secret := &corev1.Secret{}
+	secret.Name = common.ArgoCDRepoServerTLSSecretName
+	secret.Namespace = a.Namespace
+	secret.Type = corev1.SecretTypeTLS
+	secret.Data = map[string][]byte{corev1.TLSCertKey: []byte("foo"), corev1.TLSPrivateKeyKey: []byte("bar")}
+	assert.NoError(t, r.Client.Create(context.TODO(), secret))
+
+	assert.NoError(t, r.reconcileRepoDeployment(a))
+	assert.NotEqual(t, initial, getChecksum())
+}
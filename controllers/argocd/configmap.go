@@ -19,11 +19,13 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"reflect"
 	"strings"
-	"time"
 
+	"gopkg.in/yaml.v2"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
@@ -31,20 +33,6 @@ import (
 	"github.com/argoproj-labs/argocd-operator/controllers/argoutil"
 )
 
-// createRBACConfigMap will create the Argo CD RBAC ConfigMap resource.
-func (r *ReconcileArgoCD) createRBACConfigMap(cm *corev1.ConfigMap, cr *argoprojv1a1.ArgoCD) error {
-	data := make(map[string]string)
-	data[common.ArgoCDKeyRBACPolicyCSV] = getRBACPolicy(cr)
-	data[common.ArgoCDKeyRBACPolicyDefault] = getRBACDefaultPolicy(cr)
-	data[common.ArgoCDKeyRBACScopes] = getRBACScopes(cr)
-	cm.Data = data
-
-	if err := controllerutil.SetControllerReference(cr, cm, r.Scheme); err != nil {
-		return err
-	}
-	return r.Client.Create(context.TODO(), cm)
-}
-
 // getApplicationInstanceLabelKey will return the application instance label key  for the given ArgoCD.
 func getApplicationInstanceLabelKey(cr *argoprojv1a1.ArgoCD) string {
 	key := common.ArgoCDDefaultApplicationInstanceLabelKey
@@ -151,31 +139,263 @@ func getRBACScopes(cr *argoprojv1a1.ArgoCD) string {
 	return scopes
 }
 
-// getResourceCustomizations will return the resource customizations for the given ArgoCD.
-func getResourceCustomizations(cr *argoprojv1a1.ArgoCD) string {
+// getResourceCustomizations will return the resource customizations for the given ArgoCD in the format of the
+// resource.customizations key. The resource overrides replace the customizations of the same group and kind, and the
+// result is merged into the enabled bundled health checks field by field, so that e.g. an action of a resource does
+// not drop its bundled health check. The customizations are merged without parsing the entries, so that keys the
+// operator does not know are passed to Argo CD as is.
+func getResourceCustomizations(cr *argoprojv1a1.ArgoCD) (string, error) {
 	rc := common.ArgoCDDefaultResourceCustomizations
 	if cr.Spec.ResourceCustomizations != "" {
 		rc = cr.Spec.ResourceCustomizations
 	}
-	return rc
+	if len(cr.Spec.ResourceOverrides) == 0 && !isHealthChecksEnabled(cr) {
+		return rc, nil
+	}
+
+	customizations := map[string]map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(rc), &customizations); err != nil {
+		return "", fmt.Errorf("failed to parse resource customizations: %w", err)
+	}
+
+	for _, ro := range cr.Spec.ResourceOverrides {
+		override, err := newResourceOverride(ro)
+		if err != nil {
+			return "", fmt.Errorf("failed to render resource override for %s: %w", getResourceOverrideKey(ro), err)
+		}
+		if customizations[getResourceOverrideKey(ro)], err = toResourceCustomizationFields(override); err != nil {
+			return "", fmt.Errorf("failed to render resource override for %s: %w", getResourceOverrideKey(ro), err)
+		}
+	}
+
+	checks, err := loadHealthChecks(cr)
+	if err != nil {
+		return "", err
+	}
+
+	merged := map[string]map[string]interface{}{}
+	for key, check := range checks {
+		if merged[key], err = toResourceCustomizationFields(check); err != nil {
+			return "", fmt.Errorf("failed to render health check for %s: %w", key, err)
+		}
+	}
+	for key, customization := range customizations {
+		if merged[key] == nil {
+			merged[key] = customization
+			continue
+		}
+		for name, value := range customization {
+			merged[key][name] = value
+		}
+	}
+
+	out, err := yaml.Marshal(merged)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// toResourceCustomizationFields will return the fields of the given resource override keyed by their names in the
+// resource.customizations key.
+func toResourceCustomizationFields(override resourceOverride) (map[string]interface{}, error) {
+	out, err := yaml.Marshal(override)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]interface{}{}
+	if err := yaml.Unmarshal(out, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// getResourceOverrideKey will return the key of the given resource override in the resource.customizations key, the
+// group is omitted for resources of the core group.
+func getResourceOverrideKey(ro argoprojv1a1.ResourceOverride) string {
+	if ro.Group == "" {
+		return ro.Kind
+	}
+	return fmt.Sprintf("%s/%s", ro.Group, ro.Kind)
+}
+
+// newResourceOverride will return the given resource override in the format of the resource.customizations key.
+func newResourceOverride(ro argoprojv1a1.ResourceOverride) (resourceOverride, error) {
+	override := resourceOverride{
+		HealthLua:   ro.HealthLua,
+		UseOpenLibs: ro.UseOpenLibs,
+	}
+
+	if ro.Actions != nil {
+		actions := resourceActions{DiscoveryLua: ro.Actions.DiscoveryLua}
+		for _, definition := range ro.Actions.Definitions {
+			actions.Definitions = append(actions.Definitions, resourceActionDefinition{
+				Name:      definition.Name,
+				ActionLua: definition.ActionLua,
+			})
+		}
+		out, err := yaml.Marshal(actions)
+		if err != nil {
+			return override, err
+		}
+		override.Actions = string(out)
+	}
+
+	if ro.IgnoreDifferences != nil {
+		out, err := yaml.Marshal(resourceIgnoreDifferences{
+			JSONPointers:          ro.IgnoreDifferences.JSONPointers,
+			JQPathExpressions:     ro.IgnoreDifferences.JQPathExpressions,
+			ManagedFieldsManagers: ro.IgnoreDifferences.ManagedFieldsManagers,
+		})
+		if err != nil {
+			return override, err
+		}
+		override.IgnoreDifferences = string(out)
+	}
+
+	for _, field := range ro.KnownTypeFields {
+		override.KnownTypeFields = append(override.KnownTypeFields, knownTypeField{Field: field.Field, Type: field.Type})
+	}
+	return override, nil
+}
+
+// resourceFilter is a resource filter in the format of the resource.exclusions and resource.inclusions keys.
+type resourceFilter struct {
+	APIGroups []string `yaml:"apiGroups,omitempty"`
+	Kinds     []string `yaml:"kinds,omitempty"`
+	Clusters  []string `yaml:"clusters,omitempty"`
+}
+
+// defaultResourceExclusions are the resources that are excluded when the default exclusions are enabled for an ArgoCD.
+// They change often and are not part of applications, watching them only puts load on the application controller.
+var defaultResourceExclusions = []resourceFilter{
+	{APIGroups: []string{""}, Kinds: []string{"Event"}},
+	{APIGroups: []string{"events.k8s.io"}, Kinds: []string{"Event"}},
+	{APIGroups: []string{"coordination.k8s.io"}, Kinds: []string{"Lease"}},
+	{APIGroups: []string{"metrics.k8s.io"}},
 }
 
-// getResourceExclusions will return the resource exclusions for the given ArgoCD.
-func getResourceExclusions(cr *argoprojv1a1.ArgoCD) string {
+// isDefaultResourceExclusionsEnabled returns true if the default exclusions are enabled for the given ArgoCD, which is
+// the case unless they are disabled explicitly.
+func isDefaultResourceExclusionsEnabled(cr *argoprojv1a1.ArgoCD) bool {
+	return cr.Spec.ExcludeDefaultResources == nil || *cr.Spec.ExcludeDefaultResources
+}
+
+// getResourceExclusions will return the resource exclusions for the given ArgoCD. The excluded resources are merged
+// with the resource exclusions, and with the default exclusions if they are enabled.
+func getResourceExclusions(cr *argoprojv1a1.ArgoCD) (string, error) {
 	re := common.ArgoCDDefaultResourceExclusions
 	if cr.Spec.ResourceExclusions != "" {
 		re = cr.Spec.ResourceExclusions
 	}
-	return re
+	enabled := isDefaultResourceExclusionsEnabled(cr)
+	if len(cr.Spec.ExcludedResources) == 0 && !enabled {
+		return re, nil
+	}
+
+	var defaults []resourceFilter
+	if enabled {
+		defaults = defaultResourceExclusions
+	}
+	return mergeResourceFilters(defaults, re, cr.Spec.ExcludedResources)
 }
 
-// getResourceInclusions will return the resource inclusions for the given ArgoCD.
-func getResourceInclusions(cr *argoprojv1a1.ArgoCD) string {
+// getResourceInclusions will return the resource inclusions for the given ArgoCD. The included resources are merged
+// with the resource inclusions.
+func getResourceInclusions(cr *argoprojv1a1.ArgoCD) (string, error) {
 	re := common.ArgoCDDefaultResourceInclusions
 	if cr.Spec.ResourceInclusions != "" {
 		re = cr.Spec.ResourceInclusions
 	}
-	return re
+	if len(cr.Spec.IncludedResources) == 0 {
+		return re, nil
+	}
+	return mergeResourceFilters(nil, re, cr.Spec.IncludedResources)
+}
+
+// mergeResourceFilters will return the given filters, the filters of the given YAML and the given resource filters in
+// YAML, in that order. Filters that are listed more than once, e.g. by several overlays, are only rendered once, even
+// if their keys are listed in a different order. The filters of the YAML are merged without parsing them, so that keys
+// the operator does not know are passed to Argo CD as is.
+func mergeResourceFilters(defaults []resourceFilter, filtersYAML string, rfs []argoprojv1a1.ResourceFilter) (string, error) {
+	parsed := []yaml.MapSlice{}
+	if err := yaml.Unmarshal([]byte(filtersYAML), &parsed); err != nil {
+		return "", fmt.Errorf("failed to parse resource filters: %w", err)
+	}
+
+	typed := make([]resourceFilter, 0, len(defaults)+len(rfs))
+	typed = append(typed, defaults...)
+	for _, rf := range rfs {
+		typed = append(typed, resourceFilter{APIGroups: rf.APIGroups, Kinds: rf.Kinds, Clusters: rf.Clusters})
+	}
+	converted := make([]yaml.MapSlice, 0, len(typed))
+	for _, filter := range typed {
+		out, err := yaml.Marshal(filter)
+		if err != nil {
+			return "", err
+		}
+		fields := yaml.MapSlice{}
+		if err := yaml.Unmarshal(out, &fields); err != nil {
+			return "", err
+		}
+		converted = append(converted, fields)
+	}
+
+	filters := make([]yaml.MapSlice, 0, len(converted)+len(parsed))
+	filters = append(filters, converted[:len(defaults)]...)
+	filters = append(filters, parsed...)
+	filters = append(filters, converted[len(defaults):]...)
+
+	merged := make([]yaml.MapSlice, 0, len(filters))
+	normalized := make([]interface{}, 0, len(filters))
+	for _, filter := range filters {
+		n := normalizeResourceFilter(filter)
+		duplicate := false
+		for _, m := range normalized {
+			if reflect.DeepEqual(m, n) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			merged = append(merged, filter)
+			normalized = append(normalized, n)
+		}
+	}
+
+	out, err := yaml.Marshal(merged)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// normalizeResourceFilter will return the given parsed resource filter with its mappings converted to maps, so that
+// filters that only differ in the order of their keys are equal.
+func normalizeResourceFilter(value interface{}) interface{} {
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		m := make(map[string]interface{}, len(v))
+		for _, item := range v {
+			m[fmt.Sprint(item.Key)] = normalizeResourceFilter(item.Value)
+		}
+		return m
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[fmt.Sprint(key)] = normalizeResourceFilter(item)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			l = append(l, normalizeResourceFilter(item))
+		}
+		return l
+	default:
+		return v
+	}
 }
 
 // getResourceTrackingMethod will return the resource tracking method for the given ArgoCD.
@@ -261,45 +481,24 @@ func newConfigMapWithSuffix(suffix string, cr *argoprojv1a1.ArgoCD) *corev1.Conf
 
 // reconcileConfigMaps will ensure that all ArgoCD ConfigMaps are present.
 func (r *ReconcileArgoCD) reconcileConfigMaps(cr *argoprojv1a1.ArgoCD) error {
-	if err := r.reconcileArgoConfigMap(cr); err != nil {
-		return err
-	}
-
-	if err := r.reconcileRedisConfiguration(cr); err != nil {
-		return err
-	}
-
-	if err := r.reconcileRBAC(cr); err != nil {
-		return err
-	}
-
-	if err := r.reconcileSSHKnownHosts(cr); err != nil {
-		return err
-	}
-
-	if err := r.reconcileTLSCerts(cr); err != nil {
-		return err
-	}
-
-	if err := r.reconcileGrafanaConfiguration(cr); err != nil {
-		return err
-	}
-
-	if err := r.reconcileGrafanaDashboards(cr); err != nil {
-		return err
-	}
-
-	return r.reconcileGPGKeysConfigMap(cr)
+	// Every ConfigMap is reconciled even if another one failed, so that e.g. an invalid resource customization in
+	// argocd-cm does not prevent the RBAC policy from being updated.
+	return utilerrors.NewAggregate([]error{
+		r.reconcileArgoConfigMap(cr),
+		r.reconcileRedisConfiguration(cr),
+		r.reconcileRBAC(cr),
+		r.reconcileSSHKnownHosts(cr),
+		r.reconcileTLSCerts(cr),
+		r.reconcileGrafanaConfiguration(cr),
+		r.reconcileGrafanaDashboards(cr),
+		r.reconcileGPGKeysConfigMap(cr),
+	})
 }
 
 // reconcileCAConfigMap will ensure that the Certificate Authority ConfigMap is present.
 // This ConfigMap holds the CA Certificate data for client use.
 func (r *ReconcileArgoCD) reconcileCAConfigMap(cr *argoprojv1a1.ArgoCD) error {
 	cm := newConfigMapWithName(getCAConfigMapName(cr), cr)
-	if argoutil.IsObjectFound(r.Client, cr.Namespace, cm.Name, cm) {
-		return nil // ConfigMap found, do nothing
-	}
-
 	caSecret := argoutil.NewSecretWithSuffix(cr, common.ArgoCDCASuffix)
 	if !argoutil.IsObjectFound(r.Client, cr.Namespace, caSecret.Name, caSecret) {
 		log.Info(fmt.Sprintf("ca secret [%s] not found, waiting to reconcile ca configmap [%s]", caSecret.Name, cm.Name))
@@ -310,28 +509,16 @@ func (r *ReconcileArgoCD) reconcileCAConfigMap(cr *argoprojv1a1.ArgoCD) error {
 		common.ArgoCDKeyTLSCert: string(caSecret.Data[common.ArgoCDKeyTLSCert]),
 	}
 
-	if err := controllerutil.SetControllerReference(cr, cm, r.Scheme); err != nil {
-		return err
-	}
-	return r.Client.Create(context.TODO(), cm)
+	return r.applyResource(cr, cm)
 }
 
-// reconcileConfiguration will ensure that the main ConfigMap for ArgoCD is present.
+// reconcileArgoConfigMap will ensure that the main ConfigMap for ArgoCD is present and up to date.
 func (r *ReconcileArgoCD) reconcileArgoConfigMap(cr *argoprojv1a1.ArgoCD) error {
-	cm := newConfigMapWithName(common.ArgoCDConfigMapName, cr)
-	if argoutil.IsObjectFound(r.Client, cr.Namespace, cm.Name, cm) {
-		if cr.Spec.SSO == nil {
-			if err := r.reconcileDexConfiguration(cm, cr); err != nil {
-				return err
-			}
-		}
-		return r.reconcileExistingArgoConfigMap(cm, cr)
-	}
-
-	if cm.Data == nil {
-		cm.Data = make(map[string]string)
-	}
+	existing := newConfigMapWithName(common.ArgoCDConfigMapName, cr)
+	found := argoutil.IsObjectFound(r.Client, cr.Namespace, existing.Name, existing)
 
+	cm := newConfigMapWithName(common.ArgoCDConfigMapName, cr)
+	cm.Data = make(map[string]string)
 	cm.Data[common.ArgoCDKeyApplicationInstanceLabelKey] = getApplicationInstanceLabelKey(cr)
 	cm.Data[common.ArgoCDKeyConfigManagementPlugins] = getConfigManagementPlugins(cr)
 	cm.Data[common.ArgoCDKeyAdminEnabled] = fmt.Sprintf("%t", !cr.Spec.DisableAdmin)
@@ -341,18 +528,44 @@ func (r *ReconcileArgoCD) reconcileArgoConfigMap(cr *argoprojv1a1.ArgoCD) error
 	cm.Data[common.ArgoCDKeyHelpChatText] = getHelpChatText(cr)
 	cm.Data[common.ArgoCDKeyKustomizeBuildOptions] = getKustomizeBuildOptions(cr)
 
-	if len(cr.Spec.KustomizeVersions) > 0 {
-		for _, kv := range cr.Spec.KustomizeVersions {
-			cm.Data["kustomize.version."+kv.Version] = kv.Path
-		}
+	for _, kv := range cr.Spec.KustomizeVersions {
+		cm.Data["kustomize.version."+kv.Version] = kv.Path
 	}
 
 	cm.Data[common.ArgoCDKeyOIDCConfig] = getOIDCConfig(cr)
-	if c := getResourceCustomizations(cr); c != "" {
-		cm.Data[common.ArgoCDKeyResourceCustomizations] = c
+	if cr.Spec.SSO != nil && found {
+		// The OIDC configuration for the SSO provider is written by the SSO reconciliation, keep it.
+		if c, ok := existing.Data[common.ArgoCDKeyOIDCConfig]; ok {
+			cm.Data[common.ArgoCDKeyOIDCConfig] = c
+		}
 	}
-	cm.Data[common.ArgoCDKeyResourceExclusions] = getResourceExclusions(cr)
-	cm.Data[common.ArgoCDKeyResourceInclusions] = getResourceInclusions(cr)
+
+	// Customizations with Lua scripts that do not compile are not rendered. The last rendered customizations are kept
+	// until the scripts are fixed, and the error is returned after the other keys have been reconciled.
+	invalidCustomizations := cr.ValidateResourceCustomizations()
+	if invalidCustomizations != nil {
+		if c, ok := existing.Data[common.ArgoCDKeyResourceCustomizations]; found && ok {
+			cm.Data[common.ArgoCDKeyResourceCustomizations] = c
+		}
+	} else {
+		rc, err := getResourceCustomizations(cr)
+		if err != nil {
+			return err
+		}
+		if rc != "" {
+			cm.Data[common.ArgoCDKeyResourceCustomizations] = rc
+		}
+	}
+	exclusions, err := getResourceExclusions(cr)
+	if err != nil {
+		return err
+	}
+	cm.Data[common.ArgoCDKeyResourceExclusions] = exclusions
+	inclusions, err := getResourceInclusions(cr)
+	if err != nil {
+		return err
+	}
+	cm.Data[common.ArgoCDKeyResourceInclusions] = inclusions
 	cm.Data[common.ArgoCDKeyResourceTrackingMethod] = getResourceTrackingMethod(cr)
 	cm.Data[common.ArgoCDKeyRepositories] = getInitialRepositories(cr)
 	cm.Data[common.ArgoCDKeyRepositoryCredentials] = getRepositoryCredentials(cr)
@@ -360,18 +573,16 @@ func (r *ReconcileArgoCD) reconcileArgoConfigMap(cr *argoprojv1a1.ArgoCD) error
 	cm.Data[common.ArgoCDKeyServerURL] = r.getArgoServerURI(cr)
 	cm.Data[common.ArgoCDKeyUsersAnonymousEnabled] = fmt.Sprint(cr.Spec.UsersAnonymousEnabled)
 
-	if !isDexDisabled() {
+	if !isDexDisabled() && cr.Spec.SSO == nil {
 		dexConfig := getDexConfig(cr)
-		if cr.Spec.SSO == nil {
-			if dexConfig == "" && cr.Spec.Dex.OpenShiftOAuth {
-				cfg, err := r.getOpenShiftDexConfig(cr)
-				if err != nil {
-					return err
-				}
-				dexConfig = cfg
+		if dexConfig == "" && cr.Spec.Dex.OpenShiftOAuth {
+			cfg, err := r.getOpenShiftDexConfig(cr)
+			if err != nil {
+				return err
 			}
-			cm.Data[common.ArgoCDKeyDexConfig] = dexConfig
+			dexConfig = cfg
 		}
+		cm.Data[common.ArgoCDKeyDexConfig] = dexConfig
 	}
 
 	if cr.Spec.Banner != nil {
@@ -383,173 +594,11 @@ func (r *ReconcileArgoCD) reconcileArgoConfigMap(cr *argoprojv1a1.ArgoCD) error
 		}
 	}
 
-	if err := controllerutil.SetControllerReference(cr, cm, r.Scheme); err != nil {
+	applyConfigMigrations(cr, cm)
+	if err := r.applyResource(cr, cm); err != nil {
 		return err
 	}
-	return r.Client.Create(context.TODO(), cm)
-}
-
-// reconcileDexConfiguration will ensure that Dex is configured properly.
-func (r *ReconcileArgoCD) reconcileDexConfiguration(cm *corev1.ConfigMap, cr *argoprojv1a1.ArgoCD) error {
-	actual := cm.Data[common.ArgoCDKeyDexConfig]
-	desired := getDexConfig(cr)
-	if len(desired) <= 0 && cr.Spec.Dex.OpenShiftOAuth {
-		cfg, err := r.getOpenShiftDexConfig(cr)
-		if err != nil {
-			return err
-		}
-		desired = cfg
-	}
-
-	if actual != desired {
-		// Update ConfigMap with desired configuration.
-		cm.Data[common.ArgoCDKeyDexConfig] = desired
-		if err := r.Client.Update(context.TODO(), cm); err != nil {
-			return err
-		}
-
-		// Trigger rollout of Dex Deployment to pick up changes.
-		deploy := newDeploymentWithSuffix("dex-server", "dex-server", cr)
-		if !argoutil.IsObjectFound(r.Client, deploy.Namespace, deploy.Name, deploy) {
-			log.Info("unable to locate dex deployment")
-			return nil
-		}
-
-		deploy.Spec.Template.ObjectMeta.Labels["dex.config.changed"] = time.Now().UTC().Format("01022006-150406-MST")
-		return r.Client.Update(context.TODO(), deploy)
-	}
-	return nil
-}
-
-func (r *ReconcileArgoCD) reconcileExistingArgoConfigMap(cm *corev1.ConfigMap, cr *argoprojv1a1.ArgoCD) error {
-	changed := false
-
-	if cm.Data == nil {
-		cm.Data = make(map[string]string)
-	}
-
-	if cm.Data[common.ArgoCDKeyAdminEnabled] == fmt.Sprintf("%t", cr.Spec.DisableAdmin) {
-		cm.Data[common.ArgoCDKeyAdminEnabled] = fmt.Sprintf("%t", !cr.Spec.DisableAdmin)
-		changed = true
-	}
-
-	if cm.Data[common.ArgoCDKeyApplicationInstanceLabelKey] != cr.Spec.ApplicationInstanceLabelKey {
-		cm.Data[common.ArgoCDKeyApplicationInstanceLabelKey] = cr.Spec.ApplicationInstanceLabelKey
-		changed = true
-	}
-
-	if cm.Data[common.ArgoCDKeyConfigManagementPlugins] != cr.Spec.ConfigManagementPlugins {
-		cm.Data[common.ArgoCDKeyConfigManagementPlugins] = cr.Spec.ConfigManagementPlugins
-		changed = true
-	}
-
-	if cm.Data[common.ArgoCDKeyGATrackingID] != cr.Spec.GATrackingID {
-		cm.Data[common.ArgoCDKeyGATrackingID] = cr.Spec.GATrackingID
-		changed = true
-	}
-
-	if cm.Data[common.ArgoCDKeyGAAnonymizeUsers] != fmt.Sprint(cr.Spec.GAAnonymizeUsers) {
-		cm.Data[common.ArgoCDKeyGAAnonymizeUsers] = fmt.Sprint(cr.Spec.GAAnonymizeUsers)
-		changed = true
-	}
-
-	if cm.Data[common.ArgoCDKeyHelpChatURL] != cr.Spec.HelpChatURL {
-		cm.Data[common.ArgoCDKeyHelpChatURL] = cr.Spec.HelpChatURL
-		changed = true
-	}
-
-	if cm.Data[common.ArgoCDKeyHelpChatText] != cr.Spec.HelpChatText {
-		cm.Data[common.ArgoCDKeyHelpChatText] = cr.Spec.HelpChatText
-		changed = true
-	}
-
-	if cm.Data[common.ArgoCDKeyKustomizeBuildOptions] != cr.Spec.KustomizeBuildOptions {
-		cm.Data[common.ArgoCDKeyKustomizeBuildOptions] = cr.Spec.KustomizeBuildOptions
-		changed = true
-	}
-
-	if len(cr.Spec.KustomizeVersions) > 0 {
-		for _, kv := range cr.Spec.KustomizeVersions {
-			if cm.Data["kustomize.version"+kv.Version] != kv.Path {
-				cm.Data["kustomize.version."+kv.Version] = kv.Path
-				changed = true
-			}
-		}
-	}
-
-	if cr.Spec.SSO == nil {
-		if cm.Data[common.ArgoCDKeyOIDCConfig] != cr.Spec.OIDCConfig {
-			cm.Data[common.ArgoCDKeyOIDCConfig] = cr.Spec.OIDCConfig
-			changed = true
-		}
-	}
-
-	if cm.Data[common.ArgoCDKeyResourceCustomizations] != cr.Spec.ResourceCustomizations {
-		cm.Data[common.ArgoCDKeyResourceCustomizations] = cr.Spec.ResourceCustomizations
-		changed = true
-	}
-
-	if cm.Data[common.ArgoCDKeyResourceExclusions] != cr.Spec.ResourceExclusions {
-		cm.Data[common.ArgoCDKeyResourceExclusions] = cr.Spec.ResourceExclusions
-		changed = true
-	}
-
-	if cm.Data[common.ArgoCDKeyResourceInclusions] != cr.Spec.ResourceInclusions {
-		cm.Data[common.ArgoCDKeyResourceInclusions] = cr.Spec.ResourceInclusions
-		changed = true
-	}
-
-	if cm.Data[common.ArgoCDKeyResourceTrackingMethod] != cr.Spec.ResourceTrackingMethod {
-		cm.Data[common.ArgoCDKeyResourceTrackingMethod] = getResourceTrackingMethod(cr)
-		changed = true
-	}
-
-	uri := r.getArgoServerURI(cr)
-	if cm.Data[common.ArgoCDKeyServerURL] != uri {
-		cm.Data[common.ArgoCDKeyServerURL] = uri
-		changed = true
-	}
-
-	if cm.Data[common.ArgoCDKeyStatusBadgeEnabled] != fmt.Sprint(cr.Spec.StatusBadgeEnabled) {
-		cm.Data[common.ArgoCDKeyStatusBadgeEnabled] = fmt.Sprint(cr.Spec.StatusBadgeEnabled)
-		changed = true
-	}
-
-	if cm.Data[common.ArgoCDKeyUsersAnonymousEnabled] != fmt.Sprint(cr.Spec.UsersAnonymousEnabled) {
-		cm.Data[common.ArgoCDKeyUsersAnonymousEnabled] = fmt.Sprint(cr.Spec.UsersAnonymousEnabled)
-		changed = true
-	}
-
-	if cm.Data[common.ArgoCDKeyRepositoryCredentials] != cr.Spec.RepositoryCredentials {
-		cm.Data[common.ArgoCDKeyRepositoryCredentials] = cr.Spec.RepositoryCredentials
-		changed = true
-	}
-
-	if cr.Spec.Banner != nil {
-		if cm.Data[common.ArgoCDKeyBannerContent] != fmt.Sprint(cr.Spec.Banner.Content) {
-			cm.Data[common.ArgoCDKeyBannerContent] = fmt.Sprint(cr.Spec.Banner.Content)
-			changed = true
-		}
-		if cm.Data[common.ArgoCDKeyBannerURL] != fmt.Sprint(cr.Spec.Banner.URL) {
-			cm.Data[common.ArgoCDKeyBannerURL] = fmt.Sprint(cr.Spec.Banner.URL)
-			changed = true
-		}
-	} else {
-		if _, ok := cm.Data[common.ArgoCDKeyBannerContent]; ok {
-			delete(cm.Data, common.ArgoCDKeyBannerContent)
-			changed = true
-		}
-		if _, ok := cm.Data[common.ArgoCDKeyBannerURL]; ok {
-			delete(cm.Data, common.ArgoCDKeyBannerURL)
-			changed = true
-		}
-	}
-
-	if changed {
-		return r.Client.Update(context.TODO(), cm) // TODO: Reload Argo CD server after ConfigMap change (which properties)?
-	}
-
-	return nil // Nothing changed, no update needed...
+	return invalidCustomizations
 }
 
 // reconcileGrafanaConfiguration will ensure that the Grafana configuration ConfigMap is present.
@@ -559,10 +608,6 @@ func (r *ReconcileArgoCD) reconcileGrafanaConfiguration(cr *argoprojv1a1.ArgoCD)
 	}
 
 	cm := newConfigMapWithSuffix(common.ArgoCDGrafanaConfigMapSuffix, cr)
-	if argoutil.IsObjectFound(r.Client, cr.Namespace, cm.Name, cm) {
-		return nil // ConfigMap found, do nothing
-	}
-
 	secret := argoutil.NewSecretWithSuffix(cr, "grafana")
 	secret, err := argoutil.FetchSecret(r.Client, cr.ObjectMeta, secret.Name)
 	if err != nil {
@@ -592,10 +637,7 @@ func (r *ReconcileArgoCD) reconcileGrafanaConfiguration(cr *argoprojv1a1.ArgoCD)
 	}
 	cm.Data = data
 
-	if err := controllerutil.SetControllerReference(cr, cm, r.Scheme); err != nil {
-		return err
-	}
-	return r.Client.Create(context.TODO(), cm)
+	return r.applyResource(cr, cm)
 }
 
 // reconcileGrafanaDashboards will ensure that the Grafana dashboards ConfigMap is present.
@@ -605,10 +647,6 @@ func (r *ReconcileArgoCD) reconcileGrafanaDashboards(cr *argoprojv1a1.ArgoCD) er
 	}
 
 	cm := newConfigMapWithSuffix(common.ArgoCDGrafanaDashboardConfigMapSuffix, cr)
-	if argoutil.IsObjectFound(r.Client, cr.Namespace, cm.Name, cm) {
-		return nil // ConfigMap found, do nothing
-	}
-
 	pattern := filepath.Join(getGrafanaConfigPath(), "dashboards/*.json")
 	dashboards, err := filepath.Glob(pattern)
 	if err != nil {
@@ -628,47 +666,26 @@ func (r *ReconcileArgoCD) reconcileGrafanaDashboards(cr *argoprojv1a1.ArgoCD) er
 	}
 	cm.Data = data
 
-	if err := controllerutil.SetControllerReference(cr, cm, r.Scheme); err != nil {
-		return err
-	}
-	return r.Client.Create(context.TODO(), cm)
+	return r.applyResource(cr, cm)
 }
 
-// reconcileRBAC will ensure that the ArgoCD RBAC ConfigMap is present.
+// reconcileRBAC will ensure that the ArgoCD RBAC ConfigMap is present and up to date.
 func (r *ReconcileArgoCD) reconcileRBAC(cr *argoprojv1a1.ArgoCD) error {
 	cm := newConfigMapWithName(common.ArgoCDRBACConfigMapName, cr)
-	if argoutil.IsObjectFound(r.Client, cr.Namespace, cm.Name, cm) {
-		return r.reconcileRBACConfigMap(cm, cr)
-	}
-	return r.createRBACConfigMap(cm, cr)
-}
-
-// reconcileRBACConfigMap will ensure that the RBAC ConfigMap is syncronized with the given ArgoCD.
-func (r *ReconcileArgoCD) reconcileRBACConfigMap(cm *corev1.ConfigMap, cr *argoprojv1a1.ArgoCD) error {
-	changed := false
-	// Policy CSV
-	if cr.Spec.RBAC.Policy != nil && cm.Data[common.ArgoCDKeyRBACPolicyCSV] != *cr.Spec.RBAC.Policy {
-		cm.Data[common.ArgoCDKeyRBACPolicyCSV] = *cr.Spec.RBAC.Policy
-		changed = true
-	}
-
-	// Default Policy
-	if cr.Spec.RBAC.DefaultPolicy != nil && cm.Data[common.ArgoCDKeyRBACPolicyDefault] != *cr.Spec.RBAC.DefaultPolicy {
-		cm.Data[common.ArgoCDKeyRBACPolicyDefault] = *cr.Spec.RBAC.DefaultPolicy
-		changed = true
-	}
-
-	// Scopes
-	if cr.Spec.RBAC.Scopes != nil && cm.Data[common.ArgoCDKeyRBACScopes] != *cr.Spec.RBAC.Scopes {
-		cm.Data[common.ArgoCDKeyRBACScopes] = *cr.Spec.RBAC.Scopes
-		changed = true
+	cm.Data = map[string]string{
+		common.ArgoCDKeyRBACPolicyCSV:     getRBACPolicy(cr),
+		common.ArgoCDKeyRBACPolicyDefault: getRBACDefaultPolicy(cr),
+		common.ArgoCDKeyRBACScopes:        getRBACScopes(cr),
 	}
 
-	if changed {
-		// TODO: Reload server (and dex?) if RBAC settings change?
-		return r.Client.Update(context.TODO(), cm)
+	// The default scopes are only set when the ConfigMap is created, the SSO reconciliation changes them for Keycloak.
+	existing := newConfigMapWithName(common.ArgoCDRBACConfigMapName, cr)
+	if cr.Spec.RBAC.Scopes == nil && argoutil.IsObjectFound(r.Client, cr.Namespace, existing.Name, existing) &&
+		existing.Data[common.ArgoCDKeyRBACScopes] != "" {
+		cm.Data[common.ArgoCDKeyRBACScopes] = existing.Data[common.ArgoCDKeyRBACScopes]
 	}
-	return nil // ConfigMap exists and nothing to do, move along...
+	applyConfigMigrations(cr, cm)
+	return r.applyResource(cr, cm)
 }
 
 // reconcileRedisConfiguration will ensure that all of the Redis ConfigMaps are present for the given ArgoCD.
@@ -685,15 +702,11 @@ func (r *ReconcileArgoCD) reconcileRedisConfiguration(cr *argoprojv1a1.ArgoCD) e
 // reconcileRedisHAConfigMap will ensure that the Redis HA Health ConfigMap is present for the given ArgoCD.
 func (r *ReconcileArgoCD) reconcileRedisHAHealthConfigMap(cr *argoprojv1a1.ArgoCD) error {
 	cm := newConfigMapWithName(common.ArgoCDRedisHAHealthConfigMapName, cr)
-	if argoutil.IsObjectFound(r.Client, cr.Namespace, cm.Name, cm) {
-		if !cr.Spec.HA.Enabled {
+	if !cr.Spec.HA.Enabled {
+		if argoutil.IsObjectFound(r.Client, cr.Namespace, cm.Name, cm) {
 			// ConfigMap exists but HA enabled flag has been set to false, delete the ConfigMap
 			return r.Client.Delete(context.TODO(), cm)
 		}
-		return nil // ConfigMap found with nothing changed, move along...
-	}
-
-	if !cr.Spec.HA.Enabled {
 		return nil // HA not enabled, do nothing.
 	}
 
@@ -703,24 +716,17 @@ func (r *ReconcileArgoCD) reconcileRedisHAHealthConfigMap(cr *argoprojv1a1.ArgoC
 		"sentinel_liveness.sh": getSentinelLivenessScript(),
 	}
 
-	if err := controllerutil.SetControllerReference(cr, cm, r.Scheme); err != nil {
-		return err
-	}
-	return r.Client.Create(context.TODO(), cm)
+	return r.applyResource(cr, cm)
 }
 
 // reconcileRedisHAConfigMap will ensure that the Redis HA ConfigMap is present for the given ArgoCD.
 func (r *ReconcileArgoCD) reconcileRedisHAConfigMap(cr *argoprojv1a1.ArgoCD) error {
 	cm := newConfigMapWithName(common.ArgoCDRedisHAConfigMapName, cr)
-	if argoutil.IsObjectFound(r.Client, cr.Namespace, cm.Name, cm) {
-		if !cr.Spec.HA.Enabled {
+	if !cr.Spec.HA.Enabled {
+		if argoutil.IsObjectFound(r.Client, cr.Namespace, cm.Name, cm) {
 			// ConfigMap exists but HA enabled flag has been set to false, delete the ConfigMap
 			return r.Client.Delete(context.TODO(), cm)
 		}
-		return nil // ConfigMap found with nothing changed, move along...
-	}
-
-	if !cr.Spec.HA.Enabled {
 		return nil // HA not enabled, do nothing.
 	}
 
@@ -732,16 +738,17 @@ func (r *ReconcileArgoCD) reconcileRedisHAConfigMap(cr *argoprojv1a1.ArgoCD) err
 		"sentinel.conf":   getRedisSentinelConf(),
 	}
 
-	if err := controllerutil.SetControllerReference(cr, cm, r.Scheme); err != nil {
-		return err
-	}
-	return r.Client.Create(context.TODO(), cm)
+	return r.applyResource(cr, cm)
 }
 
 // reconcileSSHKnownHosts will ensure that the ArgoCD SSH Known Hosts ConfigMap is present.
+// The ConfigMap is only seeded with the initial known hosts, it is maintained by users and Argo CD afterwards.
 func (r *ReconcileArgoCD) reconcileSSHKnownHosts(cr *argoprojv1a1.ArgoCD) error {
 	cm := newConfigMapWithName(common.ArgoCDKnownHostsConfigMapName, cr)
 	if argoutil.IsObjectFound(r.Client, cr.Namespace, cm.Name, cm) {
+		if syncCommonMetadata(cr, cm) {
+			return r.Client.Update(context.TODO(), cm)
+		}
 		return nil // ConfigMap found, move along...
 	}
 
@@ -749,6 +756,7 @@ func (r *ReconcileArgoCD) reconcileSSHKnownHosts(cr *argoprojv1a1.ArgoCD) error
 		common.ArgoCDKeySSHKnownHosts: getInitialSSHKnownHosts(cr),
 	}
 
+	syncCommonMetadata(cr, cm)
 	if err := controllerutil.SetControllerReference(cr, cm, r.Scheme); err != nil {
 		return err
 	}
@@ -756,26 +764,35 @@ func (r *ReconcileArgoCD) reconcileSSHKnownHosts(cr *argoprojv1a1.ArgoCD) error
 }
 
 // reconcileTLSCerts will ensure that the ArgoCD TLS Certs ConfigMap is present.
+// The ConfigMap is only seeded with the initial certificates, it is maintained by users and Argo CD afterwards.
 func (r *ReconcileArgoCD) reconcileTLSCerts(cr *argoprojv1a1.ArgoCD) error {
 	cm := newConfigMapWithName(common.ArgoCDTLSCertsConfigMapName, cr)
 	if argoutil.IsObjectFound(r.Client, cr.Namespace, cm.Name, cm) {
+		if syncCommonMetadata(cr, cm) {
+			return r.Client.Update(context.TODO(), cm)
+		}
 		return nil // ConfigMap found, move along...
 	}
 
 	cm.Data = getInitialTLSCerts(cr)
 
+	syncCommonMetadata(cr, cm)
 	if err := controllerutil.SetControllerReference(cr, cm, r.Scheme); err != nil {
 		return err
 	}
 	return r.Client.Create(context.TODO(), cm)
 }
 
-// reconcileGPGKeysConfigMap creates a gpg-keys config map
+// reconcileGPGKeysConfigMap creates a gpg-keys config map, the keys are maintained by users and Argo CD.
 func (r *ReconcileArgoCD) reconcileGPGKeysConfigMap(cr *argoprojv1a1.ArgoCD) error {
 	cm := newConfigMapWithName(common.ArgoCDGPGKeysConfigMapName, cr)
 	if argoutil.IsObjectFound(r.Client, cr.Namespace, cm.Name, cm) {
-		return nil
+		if syncCommonMetadata(cr, cm) {
+			return r.Client.Update(context.TODO(), cm)
+		}
+		return nil // ConfigMap found, move along...
 	}
+	syncCommonMetadata(cr, cm)
 	if err := controllerutil.SetControllerReference(cr, cm, r.Scheme); err != nil {
 		return err
 	}
@@ -122,6 +122,24 @@ func TestReconcileArgoCD_reconcileTLSCerts_withInitialCertsUpdate(t *testing.T)
 	}
 }
 
+// defaultResourceExclusionsYAML are the default exclusions of the operator in the format of the resource.exclusions
+// key.
+const defaultResourceExclusionsYAML = `- apiGroups:
+  - ""
+  kinds:
+  - Event
+- apiGroups:
+  - events.k8s.io
+  kinds:
+  - Event
+- apiGroups:
+  - coordination.k8s.io
+  kinds:
+  - Lease
+- apiGroups:
+  - metrics.k8s.io
+`
+
 func TestReconcileArgoCD_reconcileArgoConfigMap(t *testing.T) {
 	logf.SetLogger(ZapLogger(true))
 
@@ -140,7 +158,7 @@ func TestReconcileArgoCD_reconcileArgoConfigMap(t *testing.T) {
 		"repositories":                       "",
 		"repository.credentials":             "",
 		"resource.inclusions":                "",
-		"resource.exclusions":                "",
+		"resource.exclusions":                defaultResourceExclusionsYAML,
 		"statusbadge.enabled":                "false",
 		"url":                                "https://argocd-server",
 		"users.anonymous.enabled":            "false",
@@ -549,6 +567,80 @@ func TestReconcileArgoCD_reconcileArgoConfigMap_withResourceInclusions(t *testin
 
 }
 
+func TestReconcileArgoCD_reconcileArgoConfigMap_withResourceFilters(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.ResourceExclusions = "- kinds:\n  - CiliumIdentity\n  apiGroups:\n  - cilium.io\n"
+		a.Spec.ExcludedResources = []argoprojv1alpha1.ResourceFilter{
+			{APIGroups: []string{"cilium.io"}, Kinds: []string{"CiliumIdentity"}},
+			{APIGroups: []string{"velero.io"}, Clusters: []string{"https://kubernetes.default.svc"}},
+			{Kinds: []string{"Event"}, APIGroups: []string{"events.k8s.io"}},
+		}
+		a.Spec.IncludedResources = []argoprojv1alpha1.ResourceFilter{
+			{APIGroups: []string{"", "apps"}},
+		}
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NoError(t, r.reconcileArgoConfigMap(a))
+	cm := &corev1.ConfigMap{}
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, common.ArgoCDConfigMapName, cm))
+
+	// The excluded resources are merged with the default exclusions, duplicates are only rendered once even if their
+	// keys are listed in a different order
+	assert.Equal(t, defaultResourceExclusionsYAML+`- kinds:
+  - CiliumIdentity
+  apiGroups:
+  - cilium.io
+- apiGroups:
+  - velero.io
+  clusters:
+  - https://kubernetes.default.svc
+`, cm.Data[common.ArgoCDKeyResourceExclusions])
+	assert.Equal(t, "- apiGroups:\n  - \"\"\n  - apps\n", cm.Data[common.ArgoCDKeyResourceInclusions])
+
+	// The default exclusions are not added when they are disabled, and the keys of the resource exclusions that the
+	// operator does not know are kept
+	disabled := false
+	a.Spec.ExcludeDefaultResources = &disabled
+	a.Spec.ResourceExclusions = "- apiGroups:\n  - cilium.io\n  custom: value\n"
+	assert.NoError(t, r.reconcileArgoConfigMap(a))
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, common.ArgoCDConfigMapName, cm))
+	assert.Equal(t, `- apiGroups:
+  - cilium.io
+  custom: value
+- apiGroups:
+  - cilium.io
+  kinds:
+  - CiliumIdentity
+- apiGroups:
+  - velero.io
+  clusters:
+  - https://kubernetes.default.svc
+- apiGroups:
+  - events.k8s.io
+  kinds:
+  - Event
+`, cm.Data[common.ArgoCDKeyResourceExclusions])
+
+	// Without excluded resources, the resource exclusions are passed to Argo CD as is when the default exclusions are
+	// disabled
+	a.Spec.ExcludedResources = nil
+	a.Spec.ResourceExclusions = "testing: testing"
+	assert.NoError(t, r.reconcileArgoConfigMap(a))
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, common.ArgoCDConfigMapName, cm))
+	assert.Equal(t, "testing: testing", cm.Data[common.ArgoCDKeyResourceExclusions])
+
+	// Resource exclusions that cannot be merged with the default exclusions are rejected
+	a.Spec.ExcludeDefaultResources = nil
+	assert.Error(t, r.reconcileArgoConfigMap(a))
+
+	a.Spec.ResourceExclusions = ""
+	assert.NoError(t, r.reconcileArgoConfigMap(a))
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, common.ArgoCDConfigMapName, cm))
+	assert.Equal(t, defaultResourceExclusionsYAML, cm.Data[common.ArgoCDKeyResourceExclusions])
+}
+
 func TestReconcileArgoCD_reconcileArgoConfigMap_withResourceCustomizations(t *testing.T) {
 	logf.SetLogger(ZapLogger(true))
 	customizations := "testing: testing"
@@ -571,3 +663,245 @@ func TestReconcileArgoCD_reconcileArgoConfigMap_withResourceCustomizations(t *te
 		t.Fatalf("reconcileArgoConfigMap failed got %q, want %q", c, customizations)
 	}
 }
+
+func TestReconcileArgoCD_reconcileArgoConfigMap_withResourceOverrides(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Version = "v2.0.5"
+		a.Spec.ResourceCustomizations = testResourceCustomizations
+		a.Spec.ResourceOverrides = []argoprojv1alpha1.ResourceOverride{
+			{
+				Kind: "Service",
+				Actions: &argoprojv1alpha1.ResourceActions{
+					DiscoveryLua: "return {}\n",
+					Definitions: []argoprojv1alpha1.ResourceAction{
+						{Name: "restart", ActionLua: "return obj\n"},
+					},
+				},
+			},
+			{
+				Group:     "argoproj.io",
+				Kind:      "Rollout",
+				HealthLua: "hs = {}\nreturn hs\n",
+				IgnoreDifferences: &argoprojv1alpha1.ResourceIgnoreDifferences{
+					JSONPointers: []string{"/spec/replicas"},
+				},
+			},
+		}
+	})
+	r := makeTestReconciler(t, a)
+
+	// Argo CD versions before v2.1 read the overrides from the resource.customizations key
+	assert.NoError(t, r.reconcileArgoConfigMap(a))
+	cm := &corev1.ConfigMap{}
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, common.ArgoCDConfigMapName, cm))
+
+	customizations := map[string]resourceOverride{}
+	assert.NoError(t, yaml.UnmarshalStrict([]byte(cm.Data[common.ArgoCDKeyResourceCustomizations]), &customizations))
+	assert.Equal(t, map[string]resourceOverride{
+		"apps/Deployment": {
+			HealthLua:         "hs = {}\nreturn hs\n",
+			UseOpenLibs:       true,
+			IgnoreDifferences: "jsonPointers:\n- /spec/replicas\n",
+			KnownTypeFields:   []knownTypeField{{Field: "spec.template.spec", Type: "core/v1/PodSpec"}},
+		},
+		"Service": {
+			Actions: "discovery.lua: |\n  return {}\ndefinitions:\n- name: restart\n  action.lua: |\n    return obj\n",
+		},
+		"argoproj.io/Rollout": {
+			HealthLua:         "hs = {}\nreturn hs\n",
+			IgnoreDifferences: "jsonPointers:\n- /spec/replicas\n",
+		},
+	}, customizations)
+
+	// Later versions read a key per resource and customization
+	a.Spec.Version = ""
+	assert.NoError(t, r.reconcileArgoConfigMap(a))
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, common.ArgoCDConfigMapName, cm))
+	assert.NotContains(t, cm.Data, common.ArgoCDKeyResourceCustomizations)
+	assert.Equal(t, "discovery.lua: |\n  return {}\ndefinitions:\n- name: restart\n  action.lua: |\n    return obj\n",
+		cm.Data["resource.customizations.actions.Service"])
+	assert.Equal(t, "hs = {}\nreturn hs\n", cm.Data["resource.customizations.health.argoproj.io_Rollout"])
+	assert.Equal(t, "jsonPointers:\n- /spec/replicas\n", cm.Data["resource.customizations.ignoreDifferences.argoproj.io_Rollout"])
+	assert.Equal(t, "true", cm.Data["resource.customizations.useOpenLibs.apps_Deployment"])
+
+	// Customizations that cannot be merged with the overrides are rejected
+	a.Spec.ResourceCustomizations = "testing: testing"
+	assert.Error(t, r.reconcileArgoConfigMap(a))
+}
+
+func TestGetResourceCustomizations_unknownKeys(t *testing.T) {
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.ResourceCustomizations = "apps/Deployment:\n  health.lua: return {}\n  custom.key: value\n"
+		a.Spec.ResourceOverrides = []argoprojv1alpha1.ResourceOverride{
+			{Group: "argoproj.io", Kind: "Rollout", HealthLua: "return {}"},
+		}
+	})
+
+	// The keys of the customizations that the operator does not know are passed to Argo CD
+	rc, err := getResourceCustomizations(a)
+	assert.NoError(t, err)
+	assert.Equal(t, "apps/Deployment:\n  custom.key: value\n  health.lua: return {}\n"+
+		"argoproj.io/Rollout:\n  health.lua: return {}\n", rc)
+}
+
+func TestReconcileArgoCD_reconcileArgoConfigMap_withInvalidLua(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.ResourceOverrides = []argoprojv1alpha1.ResourceOverride{
+			{Group: "argoproj.io", Kind: "Rollout", HealthLua: "hs = {}\nreturn hs\n"},
+		}
+	})
+	r := makeTestReconciler(t, a)
+	assert.NoError(t, r.reconcileArgoConfigMap(a))
+
+	cm := &corev1.ConfigMap{}
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, common.ArgoCDConfigMapName, cm))
+	rendered := cm.Data[common.ArgoCDKeyResourceCustomizations]
+	assert.Contains(t, rendered, "argoproj.io/Rollout")
+
+	// Scripts that do not compile are not passed to Argo CD, the last rendered customizations are kept and the other
+	// keys are still reconciled
+	a.Spec.ResourceOverrides[0].HealthLua = "hs = {\nreturn hs\n"
+	a.Spec.StatusBadgeEnabled = true
+	err := r.reconcileArgoConfigMap(a)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "argoproj.io/Rollout")
+
+	cm = &corev1.ConfigMap{}
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, common.ArgoCDConfigMapName, cm))
+	assert.Equal(t, rendered, cm.Data[common.ArgoCDKeyResourceCustomizations])
+	assert.Equal(t, "true", cm.Data[common.ArgoCDKeyStatusBadgeEnabled])
+}
+
+func TestReconcileArgoCD_reconcileConfigMaps_withInvalidLua(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.ResourceOverrides = []argoprojv1alpha1.ResourceOverride{
+			{Group: "argoproj.io", Kind: "Rollout", HealthLua: "hs = {\nreturn hs\n"},
+		}
+	})
+	r := makeTestReconciler(t, a)
+
+	// The invalid scripts only fail argocd-cm, the other ConfigMaps are reconciled
+	err := r.reconcileConfigMaps(a)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "argoproj.io/Rollout")
+
+	cm := &corev1.ConfigMap{}
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, common.ArgoCDConfigMapName, cm))
+	assert.NotContains(t, cm.Data, common.ArgoCDKeyResourceCustomizations)
+	assert.True(t, argoutil.IsObjectFound(r.Client, a.Namespace, common.ArgoCDRBACConfigMapName, &corev1.ConfigMap{}))
+	assert.True(t, argoutil.IsObjectFound(r.Client, a.Namespace, common.ArgoCDGPGKeysConfigMapName, &corev1.ConfigMap{}))
+}
+
+func TestReconcileArgoCD_reconcileArgoConfigMap_removesKustomizeVersion(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.KustomizeVersions = []argoprojv1alpha1.KustomizeVersionSpec{
+			{Version: "v4.1.0", Path: "/path/to/kustomize-4.1"},
+		}
+	})
+	r := makeTestReconciler(t, a)
+	assert.NoError(t, r.reconcileArgoConfigMap(a))
+
+	a.Spec.KustomizeVersions = []argoprojv1alpha1.KustomizeVersionSpec{
+		{Version: "v4.2.0", Path: "/path/to/kustomize-4.2"},
+	}
+	assert.NoError(t, r.reconcileArgoConfigMap(a))
+
+	cm := &corev1.ConfigMap{}
+	assert.NoError(t, argoutil.FetchObject(r.Client, testNamespace, common.ArgoCDConfigMapName, cm))
+	assert.Equal(t, "/path/to/kustomize-4.2", cm.Data["kustomize.version.v4.2.0"])
+	assert.NotContains(t, cm.Data, "kustomize.version.v4.1.0")
+}
+
+func TestReconcileArgoCD_reconcileArgoConfigMap_keepsOtherFields(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.KustomizeVersions = []argoprojv1alpha1.KustomizeVersionSpec{
+			{Version: "v4.1.0", Path: "/path/to/kustomize-4.1"},
+		}
+	})
+	r := makeTestReconciler(t, a)
+	assert.NoError(t, r.reconcileArgoConfigMap(a))
+
+	// Keys and labels that are set by others are not owned by the operator and must survive an apply
+	cm := &corev1.ConfigMap{}
+	assert.NoError(t, argoutil.FetchObject(r.Client, testNamespace, common.ArgoCDConfigMapName, cm))
+	cm.Data["accounts.alice"] = "apiKey"
+	cm.Labels["example.com/team"] = "platform"
+	assert.NoError(t, r.Client.Update(context.TODO(), cm))
+
+	a.Spec.KustomizeVersions = nil
+	assert.NoError(t, r.reconcileArgoConfigMap(a))
+
+	assert.NoError(t, argoutil.FetchObject(r.Client, testNamespace, common.ArgoCDConfigMapName, cm))
+	assert.NotContains(t, cm.Data, "kustomize.version.v4.1.0")
+	assert.Equal(t, "apiKey", cm.Data["accounts.alice"])
+	assert.Equal(t, "platform", cm.Labels["example.com/team"])
+}
+
+func TestReconcileArgoCD_reconcileArgoConfigMap_correctsDrift(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.InitialRepositories = "- url: https://github.com/argoproj/argocd-example-apps.git"
+	})
+	r := makeTestReconciler(t, a)
+	assert.NoError(t, r.reconcileArgoConfigMap(a))
+
+	cm := &corev1.ConfigMap{}
+	assert.NoError(t, argoutil.FetchObject(r.Client, testNamespace, common.ArgoCDConfigMapName, cm))
+	cm.Data[common.ArgoCDKeyAdminEnabled] = "false"
+	assert.NoError(t, r.Client.Update(context.TODO(), cm))
+
+	a.Spec.InitialRepositories = "- url: https://github.com/argoproj/argo-cd.git"
+	assert.NoError(t, r.reconcileArgoConfigMap(a))
+
+	assert.NoError(t, argoutil.FetchObject(r.Client, testNamespace, common.ArgoCDConfigMapName, cm))
+	assert.Equal(t, "true", cm.Data[common.ArgoCDKeyAdminEnabled])
+	assert.Equal(t, a.Spec.InitialRepositories, cm.Data[common.ArgoCDKeyRepositories])
+}
+
+func TestReconcileArgoCD_reconcileRBAC(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+	assert.NoError(t, r.reconcileRBAC(a))
+
+	cm := &corev1.ConfigMap{}
+	assert.NoError(t, argoutil.FetchObject(r.Client, testNamespace, common.ArgoCDRBACConfigMapName, cm))
+	assert.Equal(t, common.ArgoCDDefaultRBACScopes, cm.Data[common.ArgoCDKeyRBACScopes])
+
+	policy := "g, system:cluster-admins, role:admin"
+	a.Spec.RBAC.Policy = &policy
+	assert.NoError(t, r.reconcileRBAC(a))
+
+	assert.NoError(t, argoutil.FetchObject(r.Client, testNamespace, common.ArgoCDRBACConfigMapName, cm))
+	assert.Equal(t, policy, cm.Data[common.ArgoCDKeyRBACPolicyCSV])
+}
+
+func TestReconcileArgoCD_reconcileRBAC_keycloak(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCDForKeycloak()
+	r := makeTestReconciler(t, a)
+	assert.NoError(t, r.reconcileRBAC(a))
+
+	cm := &corev1.ConfigMap{}
+	assert.NoError(t, argoutil.FetchObject(r.Client, testNamespace, common.ArgoCDRBACConfigMapName, cm))
+	assert.Equal(t, common.ArgoCDDefaultRBACScopes, cm.Data[common.ArgoCDKeyRBACScopes])
+
+	// The scopes set by the SSO reconciliation are kept
+	cm.Data[common.ArgoCDKeyRBACScopes] = "[groups,email]"
+	assert.NoError(t, r.Client.Update(context.TODO(), cm))
+	assert.NoError(t, r.reconcileRBAC(a))
+	assert.NoError(t, argoutil.FetchObject(r.Client, testNamespace, common.ArgoCDRBACConfigMapName, cm))
+	assert.Equal(t, "[groups,email]", cm.Data[common.ArgoCDKeyRBACScopes])
+
+	// unless the scopes are set in the spec
+	scopes := "[groups,name]"
+	a.Spec.RBAC.Scopes = &scopes
+	assert.NoError(t, r.reconcileRBAC(a))
+	assert.NoError(t, argoutil.FetchObject(r.Client, testNamespace, common.ArgoCDRBACConfigMapName, cm))
+	assert.Equal(t, scopes, cm.Data[common.ArgoCDKeyRBACScopes])
+}
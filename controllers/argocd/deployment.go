@@ -19,9 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"reflect"
 	"strings"
-	"time"
 
 	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
 	"github.com/argoproj-labs/argocd-operator/common"
@@ -30,9 +28,9 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 // getArgoCDRepoServerReplicas will return the size value for the argocd-repo-server replica count if it
@@ -142,18 +140,18 @@ func getArgoImportContainerEnv(cr *argoprojv1a1.ArgoCDExport) []corev1.EnvVar {
 }
 
 // getArgoImportContainerImage will return the container image for the Argo CD import process.
-func getArgoImportContainerImage(cr *argoprojv1a1.ArgoCDExport) string {
-	img := common.ArgoCDDefaultExportJobImage
-	if len(cr.Spec.Image) > 0 {
-		img = cr.Spec.Image
-	}
-
-	tag := common.ArgoCDDefaultExportJobVersion
-	if len(cr.Spec.Version) > 0 {
-		tag = cr.Spec.Version
-	}
+func getArgoImportContainerImage(cr *argoprojv1a1.ArgoCD, export *argoprojv1a1.ArgoCDExport) string {
+	return mirrorImage(cr, argoutil.ContainerImage(export.Spec.Image, export.Spec.Version,
+		common.ArgoCDDefaultExportJobImage, common.ArgoCDDefaultExportJobVersion,
+		common.ArgoCDExportRelatedImageEnvName))
+}
 
-	return argoutil.CombineImageTag(img, tag)
+// getArgoImportSecurityContext will return the security context for the ArgoCD import process. The util image writes
+// the decrypted backup to /tmp and runs as the argocd user, which is not numeric, so its uid is set explicitly.
+func getArgoImportSecurityContext() *corev1.SecurityContext {
+	securityContext := argoutil.RestrictedSecurityContext(false)
+	securityContext.RunAsUser = argoutil.FixedID(999)
+	return securityContext
 }
 
 // getArgoImportVolumeMounts will return the VolumneMounts for the given ArgoCDExport.
@@ -353,37 +351,16 @@ func newDeploymentWithSuffix(suffix string, component string, cr *argoprojv1a1.A
 
 // reconcileDeployments will ensure that all Deployment resources are present for the given ArgoCD.
 func (r *ReconcileArgoCD) reconcileDeployments(cr *argoprojv1a1.ArgoCD) error {
-	err := r.reconcileDexDeployment(cr)
-	if err != nil {
-		return err
-	}
-
-	err = r.reconcileRedisDeployment(cr)
-	if err != nil {
-		return err
-	}
-
-	err = r.reconcileRedisHAProxyDeployment(cr)
-	if err != nil {
-		return err
-	}
-
-	err = r.reconcileRepoDeployment(cr)
-	if err != nil {
-		return err
-	}
-
-	err = r.reconcileServerDeployment(cr)
-	if err != nil {
-		return err
-	}
-
-	err = r.reconcileGrafanaDeployment(cr)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	// Every Deployment is reconciled even if another one failed, so that e.g. a failing Dex Deployment does not prevent
+	// the repo server and server from being updated.
+	return utilerrors.NewAggregate([]error{
+		r.reconcileDexDeployment(cr),
+		r.reconcileRedisDeployment(cr),
+		r.reconcileRedisHAProxyDeployment(cr),
+		r.reconcileRepoDeployment(cr),
+		r.reconcileServerDeployment(cr),
+		r.reconcileGrafanaDeployment(cr),
+	})
 }
 
 // reconcileDexDeployment will ensure the Deployment resource is present for the ArgoCD Dex component.
@@ -395,7 +372,7 @@ func (r *ReconcileArgoCD) reconcileDexDeployment(cr *argoprojv1a1.ArgoCD) error
 			"rundex",
 		},
 		Image:           getDexContainerImage(cr),
-		ImagePullPolicy: corev1.PullAlways,
+		ImagePullPolicy: getImagePullPolicy(cr, cr.Spec.Dex.ImagePullPolicy, getDexContainerImage(cr)),
 		Name:            "dex",
 		Env:             proxyEnvVars(),
 		LivenessProbe: &corev1.Probe{
@@ -435,8 +412,8 @@ func (r *ReconcileArgoCD) reconcileDexDeployment(cr *argoprojv1a1.ArgoCD) error
 			"/shared/argocd-dex",
 		},
 		Env:             proxyEnvVars(),
-		Image:           getArgoContainerImage(cr),
-		ImagePullPolicy: corev1.PullAlways,
+		Image:           getArgoComponentContainerImage(cr, "dex-server"),
+		ImagePullPolicy: getImagePullPolicy(cr, cr.Spec.Dex.ImagePullPolicy, getArgoComponentContainerImage(cr, "dex-server")),
 		Name:            "copyutil",
 		Resources:       getDexResources(cr),
 		VolumeMounts: []corev1.VolumeMount{{
@@ -452,67 +429,33 @@ func (r *ReconcileArgoCD) reconcileDexDeployment(cr *argoprojv1a1.ArgoCD) error
 			EmptyDir: &corev1.EmptyDirVolumeSource{},
 		},
 	}}
-	dexDisabled := isDexDisabled()
-	if dexDisabled {
-		log.Info("reconciling for dex, but dex is disabled")
-	}
 
-	existing := newDeploymentWithSuffix("dex-server", "dex-server", cr)
-	if argoutil.IsObjectFound(r.Client, cr.Namespace, existing.Name, existing) {
-		if dexDisabled {
+	if isDexDisabled() {
+		log.Info("reconciling for dex, but dex is disabled")
+		existing := newDeploymentWithSuffix("dex-server", "dex-server", cr)
+		if argoutil.IsObjectFound(r.Client, cr.Namespace, existing.Name, existing) {
 			log.Info("deleting the existing dex deployment because dex is disabled")
 			// Deployment exists but enabled flag has been set to false, delete the Deployment
 			return r.Client.Delete(context.TODO(), existing)
 		}
-		changed := false
-
-		actualImage := existing.Spec.Template.Spec.Containers[0].Image
-		desiredImage := getDexContainerImage(cr)
-		if actualImage != desiredImage {
-			existing.Spec.Template.Spec.Containers[0].Image = desiredImage
-			existing.Spec.Template.ObjectMeta.Labels["image.upgraded"] = time.Now().UTC().Format("01022006-150406-MST")
-			changed = true
-		}
-
-		actualImage = existing.Spec.Template.Spec.InitContainers[0].Image
-		desiredImage = getArgoContainerImage(cr)
-		if actualImage != desiredImage {
-			existing.Spec.Template.Spec.InitContainers[0].Image = desiredImage
-			existing.Spec.Template.ObjectMeta.Labels["image.upgraded"] = time.Now().UTC().Format("01022006-150406-MST")
-			changed = true
-		}
-		updateNodePlacement(existing, deploy, &changed)
-		if !reflect.DeepEqual(existing.Spec.Template.Spec.Containers[0].Env,
-			deploy.Spec.Template.Spec.Containers[0].Env) {
-			existing.Spec.Template.Spec.Containers[0].Env = deploy.Spec.Template.Spec.Containers[0].Env
-			changed = true
-		}
-
-		if !reflect.DeepEqual(existing.Spec.Template.Spec.InitContainers[0].Env,
-			deploy.Spec.Template.Spec.InitContainers[0].Env) {
-			existing.Spec.Template.Spec.InitContainers[0].Env = deploy.Spec.Template.Spec.InitContainers[0].Env
-			changed = true
-		}
+		return nil
+	}
 
-		if !reflect.DeepEqual(deploy.Spec.Template.Spec.Containers[0].Resources, existing.Spec.Template.Spec.Containers[0].Resources) {
-			existing.Spec.Template.Spec.Containers[0].Resources = deploy.Spec.Template.Spec.Containers[0].Resources
-			changed = true
-		}
+	applyScheduling(&deploy.Spec.Template, cr.Spec.Dex.Scheduling)
 
-		if changed {
-			return r.Client.Update(context.TODO(), existing)
-		}
-		return nil // Deployment found with nothing to do, move along...
+	if err := argoutil.ApplySecurityContext(&deploy.Spec.Template.Spec, false, cr.Spec.Dex.SecurityContext); err != nil {
+		return err
 	}
 
-	if dexDisabled {
-		return nil
+	if err := applyPodTemplateOverride(&deploy.Spec.Template, cr.Spec.Dex.PodTemplateOverride); err != nil {
+		return err
 	}
 
-	if err := controllerutil.SetControllerReference(cr, deploy, r.Scheme); err != nil {
+	if err := r.setConfigChecksum(cr, &deploy.Spec.Template, getDexConfigInputs(cr)); err != nil {
 		return err
 	}
-	return r.Client.Create(context.TODO(), deploy)
+
+	return r.applyResource(cr, deploy)
 }
 
 // reconcileGrafanaDeployment will ensure the Deployment resource is present for the ArgoCD Grafana component.
@@ -521,7 +464,7 @@ func (r *ReconcileArgoCD) reconcileGrafanaDeployment(cr *argoprojv1a1.ArgoCD) er
 	deploy.Spec.Replicas = getGrafanaReplicas(cr)
 	deploy.Spec.Template.Spec.Containers = []corev1.Container{{
 		Image:           getGrafanaContainerImage(cr),
-		ImagePullPolicy: corev1.PullAlways,
+		ImagePullPolicy: getImagePullPolicy(cr, cr.Spec.Grafana.ImagePullPolicy, getGrafanaContainerImage(cr)),
 		Name:            "grafana",
 		Ports: []corev1.ContainerPort{
 			{
@@ -599,40 +542,30 @@ func (r *ReconcileArgoCD) reconcileGrafanaDeployment(cr *argoprojv1a1.ArgoCD) er
 		},
 	}
 
-	existing := newDeploymentWithSuffix("grafana", "grafana", cr)
-	if argoutil.IsObjectFound(r.Client, cr.Namespace, existing.Name, existing) {
-		if !cr.Spec.Grafana.Enabled {
+	if !cr.Spec.Grafana.Enabled {
+		existing := newDeploymentWithSuffix("grafana", "grafana", cr)
+		if argoutil.IsObjectFound(r.Client, cr.Namespace, existing.Name, existing) {
 			// Deployment exists but enabled flag has been set to false, delete the Deployment
 			return r.Client.Delete(context.TODO(), existing)
 		}
-		changed := false
-		if hasGrafanaSpecChanged(existing, cr) {
-			existing.Spec.Replicas = cr.Spec.Grafana.Size
-			changed = true
-		}
-		updateNodePlacement(existing, deploy, &changed)
-		if !reflect.DeepEqual(existing.Spec.Template.Spec.Containers[0].Env,
-			deploy.Spec.Template.Spec.Containers[0].Env) {
-			existing.Spec.Template.Spec.Containers[0].Env = deploy.Spec.Template.Spec.Containers[0].Env
-			changed = true
-		}
-		if !reflect.DeepEqual(deploy.Spec.Template.Spec.Containers[0].Resources, existing.Spec.Template.Spec.Containers[0].Resources) {
-			existing.Spec.Template.Spec.Containers[0].Resources = deploy.Spec.Template.Spec.Containers[0].Resources
-			changed = true
-		}
-		if changed {
-			return r.Client.Update(context.TODO(), existing)
-		}
-		return nil // Deployment found, do nothing
+		return nil // Grafana not enabled, do nothing.
 	}
 
-	if !cr.Spec.Grafana.Enabled {
-		return nil // Grafana not enabled, do nothing.
+	applyScheduling(&deploy.Spec.Template, cr.Spec.Grafana.Scheduling)
+
+	if err := argoutil.ApplySecurityContext(&deploy.Spec.Template.Spec, false, cr.Spec.Grafana.SecurityContext); err != nil {
+		return err
 	}
-	if err := controllerutil.SetControllerReference(cr, deploy, r.Scheme); err != nil {
+
+	if err := applyPodTemplateOverride(&deploy.Spec.Template, cr.Spec.Grafana.PodTemplateOverride); err != nil {
+		return err
+	}
+
+	if err := r.setConfigChecksum(cr, &deploy.Spec.Template, getGrafanaConfigInputs(cr)); err != nil {
 		return err
 	}
-	return r.Client.Create(context.TODO(), deploy)
+
+	return r.applyResource(cr, deploy)
 }
 
 // reconcileRedisDeployment will ensure the Deployment resource is present for the ArgoCD Redis component.
@@ -646,7 +579,7 @@ func (r *ReconcileArgoCD) reconcileRedisDeployment(cr *argoprojv1a1.ArgoCD) erro
 			"no",
 		},
 		Image:           getRedisContainerImage(cr),
-		ImagePullPolicy: corev1.PullAlways,
+		ImagePullPolicy: getImagePullPolicy(cr, cr.Spec.Redis.ImagePullPolicy, getRedisContainerImage(cr)),
 		Name:            "redis",
 		Ports: []corev1.ContainerPort{
 			{
@@ -657,78 +590,47 @@ func (r *ReconcileArgoCD) reconcileRedisDeployment(cr *argoprojv1a1.ArgoCD) erro
 		Env:       proxyEnvVars(),
 	}}
 
+	// The Redis image runs as root unless told otherwise, 999 is the uid of the redis user in the image
+	deploy.Spec.Template.Spec.SecurityContext = &corev1.PodSecurityContext{
+		RunAsUser: argoutil.FixedID(999),
+	}
+
 	if err := applyReconcilerHook(cr, deploy, ""); err != nil {
 		return err
 	}
 
-	existing := newDeploymentWithSuffix("redis", "redis", cr)
-	if argoutil.IsObjectFound(r.Client, cr.Namespace, existing.Name, existing) {
-		if cr.Spec.HA.Enabled {
-			// Deployment exists but HA enabled flag has been set to true, delete the Deployment
-			return r.Client.Delete(context.TODO(), deploy)
-		}
-		changed := false
-		actualImage := deploy.Spec.Template.Spec.Containers[0].Image
-		desiredImage := getRedisContainerImage(cr)
-		if actualImage != desiredImage {
-			existing.Spec.Template.Spec.Containers[0].Image = desiredImage
-			existing.Spec.Template.ObjectMeta.Labels["image.upgraded"] = time.Now().UTC().Format("01022006-150406-MST")
-			changed = true
-		}
-		updateNodePlacement(existing, deploy, &changed)
-		if !reflect.DeepEqual(existing.Spec.Template.Spec.Containers[0].Env,
-			deploy.Spec.Template.Spec.Containers[0].Env) {
-			existing.Spec.Template.Spec.Containers[0].Env = deploy.Spec.Template.Spec.Containers[0].Env
-			changed = true
-		}
+	applyScheduling(&deploy.Spec.Template, cr.Spec.Redis.Scheduling)
 
-		if !reflect.DeepEqual(deploy.Spec.Template.Spec.Containers[0].Resources, existing.Spec.Template.Spec.Containers[0].Resources) {
-			existing.Spec.Template.Spec.Containers[0].Resources = deploy.Spec.Template.Spec.Containers[0].Resources
-			changed = true
-		}
+	if err := argoutil.ApplySecurityContext(&deploy.Spec.Template.Spec, true, cr.Spec.Redis.SecurityContext); err != nil {
+		return err
+	}
 
-		if changed {
-			return r.Client.Update(context.TODO(), existing)
-		}
-		return nil // Deployment found with nothing to do, move along...
+	if err := applyPodTemplateOverride(&deploy.Spec.Template, cr.Spec.Redis.PodTemplateOverride); err != nil {
+		return err
 	}
 
 	if cr.Spec.HA.Enabled {
+		existing := newDeploymentWithSuffix("redis", "redis", cr)
+		if argoutil.IsObjectFound(r.Client, cr.Namespace, existing.Name, existing) {
+			// Deployment exists but HA enabled flag has been set to true, delete the Deployment
+			return r.Client.Delete(context.TODO(), existing)
+		}
 		return nil // HA enabled, do nothing.
 	}
-	if err := controllerutil.SetControllerReference(cr, deploy, r.Scheme); err != nil {
-		return err
-	}
-	return r.Client.Create(context.TODO(), deploy)
+
+	return r.applyResource(cr, deploy)
 }
 
 // reconcileRedisHAProxyDeployment will ensure the Deployment resource is present for the Redis HA Proxy component.
 func (r *ReconcileArgoCD) reconcileRedisHAProxyDeployment(cr *argoprojv1a1.ArgoCD) error {
 	deploy := newDeploymentWithSuffix("redis-ha-haproxy", "redis", cr)
 
-	existing := newDeploymentWithSuffix("redis-ha-haproxy", "redis", cr)
-	if argoutil.IsObjectFound(r.Client, cr.Namespace, existing.Name, existing) {
-		if !cr.Spec.HA.Enabled {
+	if !cr.Spec.HA.Enabled {
+		existing := newDeploymentWithSuffix("redis-ha-haproxy", "redis", cr)
+		if argoutil.IsObjectFound(r.Client, cr.Namespace, existing.Name, existing) {
 			// Deployment exists but HA enabled flag has been set to false, delete the Deployment
 			return r.Client.Delete(context.TODO(), existing)
 		}
-		changed := false
-		actualImage := existing.Spec.Template.Spec.Containers[0].Image
-		desiredImage := getRedisHAProxyContainerImage(cr)
-
-		if actualImage != desiredImage {
-			existing.Spec.Template.Spec.Containers[0].Image = desiredImage
-			existing.Spec.Template.ObjectMeta.Labels["image.upgraded"] = time.Now().UTC().Format("01022006-150406-MST")
-			changed = true
-		}
-		updateNodePlacement(existing, deploy, &changed)
-		if changed {
-			return r.Client.Update(context.TODO(), existing)
-		}
-		return nil // Deployment found, do nothing
-	}
-
-	if !cr.Spec.HA.Enabled {
 		return nil // HA not enabled, do nothing.
 	}
 
@@ -762,7 +664,7 @@ func (r *ReconcileArgoCD) reconcileRedisHAProxyDeployment(cr *argoprojv1a1.ArgoC
 
 	deploy.Spec.Template.Spec.Containers = []corev1.Container{{
 		Image:           getRedisHAProxyContainerImage(cr),
-		ImagePullPolicy: corev1.PullIfNotPresent,
+		ImagePullPolicy: getImagePullPolicy(cr, cr.Spec.HA.ImagePullPolicy, getRedisHAProxyContainerImage(cr), corev1.PullIfNotPresent),
 		Name:            "haproxy",
 		Env:             proxyEnvVars(),
 		LivenessProbe: &corev1.Probe{
@@ -802,7 +704,7 @@ func (r *ReconcileArgoCD) reconcileRedisHAProxyDeployment(cr *argoprojv1a1.ArgoC
 			"sh",
 		},
 		Image:           getRedisHAProxyContainerImage(cr),
-		ImagePullPolicy: corev1.PullIfNotPresent,
+		ImagePullPolicy: getImagePullPolicy(cr, cr.Spec.HA.ImagePullPolicy, getRedisHAProxyContainerImage(cr), corev1.PullIfNotPresent),
 		Name:            "config-init",
 		Env:             proxyEnvVars(),
 		Resources:       getRedisHAProxyResources(cr),
@@ -846,14 +748,30 @@ func (r *ReconcileArgoCD) reconcileRedisHAProxyDeployment(cr *argoprojv1a1.ArgoC
 
 	deploy.Spec.Template.Spec.ServiceAccountName = fmt.Sprintf("%s-%s", cr.Name, "argocd-redis-ha")
 
+	// The HAProxy image runs as the haproxy user, which is not numeric
+	deploy.Spec.Template.Spec.SecurityContext = &corev1.PodSecurityContext{
+		RunAsUser: argoutil.FixedID(1000),
+	}
+
 	if err := applyReconcilerHook(cr, deploy, ""); err != nil {
 		return err
 	}
 
-	if err := controllerutil.SetControllerReference(cr, deploy, r.Scheme); err != nil {
+	applyScheduling(&deploy.Spec.Template, cr.Spec.HA.Scheduling)
+
+	if err := argoutil.ApplySecurityContext(&deploy.Spec.Template.Spec, true, cr.Spec.HA.SecurityContext); err != nil {
+		return err
+	}
+
+	if err := applyPodTemplateOverride(&deploy.Spec.Template, cr.Spec.HA.PodTemplateOverride); err != nil {
 		return err
 	}
-	return r.Client.Create(context.TODO(), deploy)
+
+	if err := r.setConfigChecksum(cr, &deploy.Spec.Template, getRedisHAProxyConfigInputs(cr)); err != nil {
+		return err
+	}
+
+	return r.applyResource(cr, deploy)
 }
 
 // reconcileRepoDeployment will ensure the Deployment resource is present for the ArgoCD Repo component.
@@ -880,9 +798,9 @@ func (r *ReconcileArgoCD) reconcileRepoDeployment(cr *argoprojv1a1.ArgoCD) error
 
 	deploy.Spec.Template.Spec.InitContainers = []corev1.Container{{
 		Name:            "copyutil",
-		Image:           getArgoContainerImage(cr),
+		Image:           getArgoComponentContainerImage(cr, "repo-server"),
 		Command:         getArgoCmpServerInitCommand(),
-		ImagePullPolicy: corev1.PullAlways,
+		ImagePullPolicy: getImagePullPolicy(cr, cr.Spec.Repo.ImagePullPolicy, getArgoComponentContainerImage(cr, "repo-server")),
 		Resources:       getArgoRepoResources(cr),
 		Env:             proxyEnvVars(),
 		VolumeMounts: []corev1.VolumeMount{
@@ -934,8 +852,8 @@ func (r *ReconcileArgoCD) reconcileRepoDeployment(cr *argoprojv1a1.ArgoCD) error
 
 	deploy.Spec.Template.Spec.Containers = []corev1.Container{{
 		Command:         getArgoRepoCommand(cr),
-		Image:           getRepoServerContainerImage(cr),
-		ImagePullPolicy: corev1.PullAlways,
+		Image:           getComponentMainContainerImage(cr, "repo-server"),
+		ImagePullPolicy: getImagePullPolicy(cr, cr.Spec.Repo.ImagePullPolicy, getComponentMainContainerImage(cr, "repo-server")),
 		LivenessProbe: &corev1.Probe{
 			Handler: corev1.Handler{
 				TCPSocket: &corev1.TCPSocketAction{
@@ -1049,68 +967,43 @@ func (r *ReconcileArgoCD) reconcileRepoDeployment(cr *argoprojv1a1.ArgoCD) error
 		deploy.Spec.Replicas = replicas
 	}
 
-	existing := newDeploymentWithSuffix("repo-server", "repo-server", cr)
-	if argoutil.IsObjectFound(r.Client, cr.Namespace, existing.Name, existing) {
-		changed := false
-		actualImage := existing.Spec.Template.Spec.Containers[0].Image
-		desiredImage := getRepoServerContainerImage(cr)
-		if actualImage != desiredImage {
-			existing.Spec.Template.Spec.Containers[0].Image = desiredImage
-			if existing.Spec.Template.ObjectMeta.Labels == nil {
-				existing.Spec.Template.ObjectMeta.Labels = map[string]string{
-					"image.upgraded": time.Now().UTC().Format("01022006-150406-MST"),
-				}
-			}
-			existing.Spec.Template.ObjectMeta.Labels["image.upgraded"] = time.Now().UTC().Format("01022006-150406-MST")
-			changed = true
-		}
-		updateNodePlacement(existing, deploy, &changed)
-		if !reflect.DeepEqual(deploy.Spec.Template.Spec.Volumes, existing.Spec.Template.Spec.Volumes) {
-			existing.Spec.Template.Spec.Volumes = deploy.Spec.Template.Spec.Volumes
-			changed = true
-		}
-		if !reflect.DeepEqual(deploy.Spec.Template.Spec.Containers[0].VolumeMounts,
-			existing.Spec.Template.Spec.Containers[0].VolumeMounts) {
-			existing.Spec.Template.Spec.Containers[0].VolumeMounts = deploy.Spec.Template.Spec.Containers[0].VolumeMounts
-			changed = true
-		}
-		if !reflect.DeepEqual(deploy.Spec.Template.Spec.Containers[0].Env,
-			existing.Spec.Template.Spec.Containers[0].Env) {
-			existing.Spec.Template.Spec.Containers[0].Env = deploy.Spec.Template.Spec.Containers[0].Env
-			changed = true
-		}
-		if !reflect.DeepEqual(deploy.Spec.Template.Spec.Containers[0].Resources, existing.Spec.Template.Spec.Containers[0].Resources) {
-			existing.Spec.Template.Spec.Containers[0].Resources = deploy.Spec.Template.Spec.Containers[0].Resources
-			changed = true
-		}
-		if !reflect.DeepEqual(deploy.Spec.Template.Spec.Containers[0].Command, existing.Spec.Template.Spec.Containers[0].Command) {
-			existing.Spec.Template.Spec.Containers[0].Command = deploy.Spec.Template.Spec.Containers[0].Command
-			changed = true
-		}
-		if !reflect.DeepEqual(deploy.Spec.Template.Spec.Containers[1:],
-			existing.Spec.Template.Spec.Containers[1:]) {
-			existing.Spec.Template.Spec.Containers = append(existing.Spec.Template.Spec.Containers[0:1],
-				deploy.Spec.Template.Spec.Containers[1:]...)
-			changed = true
-		}
-		if !reflect.DeepEqual(deploy.Spec.Template.Spec.InitContainers, existing.Spec.Template.Spec.InitContainers) {
-			existing.Spec.Template.Spec.InitContainers = deploy.Spec.Template.Spec.InitContainers
-			changed = true
-		}
-		if !reflect.DeepEqual(deploy.Spec.Replicas, existing.Spec.Replicas) {
-			existing.Spec.Replicas = deploy.Spec.Replicas
-			changed = true
-		}
-		if changed {
-			return r.Client.Update(context.TODO(), existing)
+	if cr.Spec.HA.Enabled {
+		// Spread the repo servers across zones and nodes
+		deploy.Spec.Template.Spec.Affinity = &corev1.Affinity{
+			PodAntiAffinity: &corev1.PodAntiAffinity{
+				PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+					zoneAntiAffinityTerm(deploy.Name),
+					{
+						PodAffinityTerm: corev1.PodAffinityTerm{
+							LabelSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{
+									common.ArgoCDKeyName: deploy.Name,
+								},
+							},
+							TopologyKey: common.ArgoCDKeyHostname,
+						},
+						Weight: int32(50),
+					},
+				},
+			},
 		}
-		return nil // Deployment found with nothing to do, move along...
 	}
 
-	if err := controllerutil.SetControllerReference(cr, deploy, r.Scheme); err != nil {
+	applyScheduling(&deploy.Spec.Template, cr.Spec.Repo.Scheduling)
+
+	if err := argoutil.ApplySecurityContext(&deploy.Spec.Template.Spec, true, cr.Spec.Repo.SecurityContext); err != nil {
+		return err
+	}
+
+	if err := applyPodTemplateOverride(&deploy.Spec.Template, cr.Spec.Repo.PodTemplateOverride); err != nil {
 		return err
 	}
-	return r.Client.Create(context.TODO(), deploy)
+
+	if err := r.setConfigChecksum(cr, &deploy.Spec.Template, getRepoServerConfigInputs(cr)); err != nil {
+		return err
+	}
+
+	return r.applyResource(cr, deploy)
 }
 
 // reconcileServerDeployment will ensure the Deployment resource is present for the ArgoCD Server component.
@@ -1120,8 +1013,8 @@ func (r *ReconcileArgoCD) reconcileServerDeployment(cr *argoprojv1a1.ArgoCD) err
 	serverEnv = argoutil.EnvMerge(serverEnv, proxyEnvVars(), false)
 	deploy.Spec.Template.Spec.Containers = []corev1.Container{{
 		Command:         getArgoServerCommand(cr),
-		Image:           getArgoContainerImage(cr),
-		ImagePullPolicy: corev1.PullAlways,
+		Image:           getArgoComponentContainerImage(cr, "server"),
+		ImagePullPolicy: getImagePullPolicy(cr, cr.Spec.Server.ImagePullPolicy, getArgoComponentContainerImage(cr, "server")),
 		Env:             serverEnv,
 		LivenessProbe: &corev1.Probe{
 			Handler: corev1.Handler{
@@ -1201,66 +1094,21 @@ func (r *ReconcileArgoCD) reconcileServerDeployment(cr *argoprojv1a1.ArgoCD) err
 		deploy.Spec.Replicas = replicas
 	}
 
-	existing := newDeploymentWithSuffix("server", "server", cr)
-	if argoutil.IsObjectFound(r.Client, cr.Namespace, existing.Name, existing) {
-		actualImage := existing.Spec.Template.Spec.Containers[0].Image
-		desiredImage := getArgoContainerImage(cr)
-		changed := false
-		if actualImage != desiredImage {
-			existing.Spec.Template.Spec.Containers[0].Image = desiredImage
-			existing.Spec.Template.ObjectMeta.Labels["image.upgraded"] = time.Now().UTC().Format("01022006-150406-MST")
-			changed = true
-		}
-		updateNodePlacement(existing, deploy, &changed)
-		if !reflect.DeepEqual(existing.Spec.Template.Spec.Containers[0].Env,
-			deploy.Spec.Template.Spec.Containers[0].Env) {
-			existing.Spec.Template.Spec.Containers[0].Env = deploy.Spec.Template.Spec.Containers[0].Env
-			changed = true
-		}
-		if !reflect.DeepEqual(existing.Spec.Template.Spec.Containers[0].Command,
-			deploy.Spec.Template.Spec.Containers[0].Command) {
-			existing.Spec.Template.Spec.Containers[0].Command = deploy.Spec.Template.Spec.Containers[0].Command
-			changed = true
-		}
-		if !reflect.DeepEqual(deploy.Spec.Template.Spec.Volumes, existing.Spec.Template.Spec.Volumes) {
-			existing.Spec.Template.Spec.Volumes = deploy.Spec.Template.Spec.Volumes
-			changed = true
-		}
-		if !reflect.DeepEqual(deploy.Spec.Template.Spec.Containers[0].VolumeMounts,
-			existing.Spec.Template.Spec.Containers[0].VolumeMounts) {
-			existing.Spec.Template.Spec.Containers[0].VolumeMounts = deploy.Spec.Template.Spec.Containers[0].VolumeMounts
-			changed = true
-		}
-		if !reflect.DeepEqual(deploy.Spec.Template.Spec.Containers[0].Resources,
-			existing.Spec.Template.Spec.Containers[0].Resources) {
-			existing.Spec.Template.Spec.Containers[0].Resources = deploy.Spec.Template.Spec.Containers[0].Resources
-			changed = true
-		}
-		if !reflect.DeepEqual(deploy.Spec.Replicas, existing.Spec.Replicas) {
-			existing.Spec.Replicas = deploy.Spec.Replicas
-			changed = true
-		}
-		if changed {
-			return r.Client.Update(context.TODO(), existing)
-		}
-		return nil // Deployment found with nothing to do, move along...
+	applyScheduling(&deploy.Spec.Template, cr.Spec.Server.Scheduling)
+
+	if err := argoutil.ApplySecurityContext(&deploy.Spec.Template.Spec, false, cr.Spec.Server.SecurityContext); err != nil {
+		return err
 	}
 
-	if err := controllerutil.SetControllerReference(cr, deploy, r.Scheme); err != nil {
+	if err := applyPodTemplateOverride(&deploy.Spec.Template, cr.Spec.Server.PodTemplateOverride); err != nil {
 		return err
 	}
-	return r.Client.Create(context.TODO(), deploy)
-}
 
-// triggerDeploymentRollout will update the label with the given key to trigger a new rollout of the Deployment.
-func (r *ReconcileArgoCD) triggerDeploymentRollout(deployment *appsv1.Deployment, key string) error {
-	if !argoutil.IsObjectFound(r.Client, deployment.Namespace, deployment.Name, deployment) {
-		log.Info(fmt.Sprintf("unable to locate deployment with name: %s", deployment.Name))
-		return nil
+	if err := r.setConfigChecksum(cr, &deploy.Spec.Template, getServerConfigInputs(cr)); err != nil {
+		return err
 	}
 
-	deployment.Spec.Template.ObjectMeta.Labels[key] = nowNano()
-	return r.Client.Update(context.TODO(), deployment)
+	return r.applyResource(cr, deploy)
 }
 
 func proxyEnvVars(vars ...corev1.EnvVar) []corev1.EnvVar {
@@ -1292,15 +1140,3 @@ func isDexDisabled() bool {
 	}
 	return false
 }
-
-// to update nodeSelector and tolerations in reconciler
-func updateNodePlacement(existing *appsv1.Deployment, deploy *appsv1.Deployment, changed *bool) {
-	if !reflect.DeepEqual(existing.Spec.Template.Spec.NodeSelector, deploy.Spec.Template.Spec.NodeSelector) {
-		existing.Spec.Template.Spec.NodeSelector = deploy.Spec.Template.Spec.NodeSelector
-		*changed = true
-	}
-	if !reflect.DeepEqual(existing.Spec.Template.Spec.Tolerations, deploy.Spec.Template.Spec.Tolerations) {
-		existing.Spec.Template.Spec.Tolerations = deploy.Spec.Template.Spec.Tolerations
-		*changed = true
-	}
-}
@@ -2,6 +2,7 @@ package argocd
 
 import (
 	"context"
+	"errors"
 	"os"
 	"strings"
 	"testing"
@@ -18,6 +19,7 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/argoproj-labs/argocd-operator/common"
+	"github.com/argoproj-labs/argocd-operator/controllers/argoutil"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
@@ -871,7 +873,8 @@ func TestReconcileArgoCD_reconcileDexDeployment(t *testing.T) {
 						MountPath: "/shared",
 					},
 				},
-				ImagePullPolicy: corev1.PullAlways,
+				ImagePullPolicy: corev1.PullIfNotPresent,
+				SecurityContext: argoutil.RestrictedSecurityContext(false),
 			},
 		},
 		Containers: []corev1.Container{
@@ -908,9 +911,11 @@ func TestReconcileArgoCD_reconcileDexDeployment(t *testing.T) {
 				},
 				VolumeMounts: []corev1.VolumeMount{
 					{Name: "static-files", MountPath: "/shared"}},
-				ImagePullPolicy: corev1.PullAlways,
+				ImagePullPolicy: corev1.PullIfNotPresent,
+				SecurityContext: argoutil.RestrictedSecurityContext(false),
 			},
 		},
+		SecurityContext:    argoutil.RestrictedPodSecurityContext(),
 		ServiceAccountName: "argocd-argocd-dex-server",
 	}
 
@@ -964,6 +969,7 @@ func TestReconcileArgoCD_reconcileDexDeployment_withUpdate(t *testing.T) {
 					},
 				},
 				ImagePullPolicy: corev1.PullAlways,
+				SecurityContext: argoutil.RestrictedSecurityContext(false),
 			},
 		},
 		Containers: []corev1.Container{
@@ -1001,8 +1007,10 @@ func TestReconcileArgoCD_reconcileDexDeployment_withUpdate(t *testing.T) {
 				VolumeMounts: []corev1.VolumeMount{
 					{Name: "static-files", MountPath: "/shared"}},
 				ImagePullPolicy: corev1.PullAlways,
+				SecurityContext: argoutil.RestrictedSecurityContext(false),
 			},
 		},
+		SecurityContext:    argoutil.RestrictedPodSecurityContext(),
 		ServiceAccountName: "argocd-argocd-dex-server",
 	}
 	assert.Equal(t, want, deployment.Spec.Template.Spec)
@@ -1027,7 +1035,8 @@ func TestReconcileArgoCD_reconcileServerDeployment(t *testing.T) {
 			{
 				Name:            "argocd-server",
 				Image:           getArgoContainerImage(a),
-				ImagePullPolicy: corev1.PullAlways,
+				ImagePullPolicy: corev1.PullIfNotPresent,
+				SecurityContext: argoutil.RestrictedSecurityContext(false),
 				Command: []string{
 					"argocd-server",
 					"--staticassets",
@@ -1071,6 +1080,7 @@ func TestReconcileArgoCD_reconcileServerDeployment(t *testing.T) {
 			},
 		},
 		Volumes:            serverDefaultVolumes(),
+		SecurityContext:    argoutil.RestrictedPodSecurityContext(),
 		ServiceAccountName: "argocd-argocd-server",
 	}
 
@@ -1202,7 +1212,8 @@ func TestReconcileArgoCD_reconcileServerDeploymentWithInsecure(t *testing.T) {
 			{
 				Name:            "argocd-server",
 				Image:           getArgoContainerImage(a),
-				ImagePullPolicy: corev1.PullAlways,
+				ImagePullPolicy: corev1.PullIfNotPresent,
+				SecurityContext: argoutil.RestrictedSecurityContext(false),
 				Command: []string{
 					"argocd-server",
 					"--insecure",
@@ -1247,6 +1258,7 @@ func TestReconcileArgoCD_reconcileServerDeploymentWithInsecure(t *testing.T) {
 			},
 		},
 		Volumes:            serverDefaultVolumes(),
+		SecurityContext:    argoutil.RestrictedPodSecurityContext(),
 		ServiceAccountName: "argocd-argocd-server",
 	}
 
@@ -1278,7 +1290,8 @@ func TestReconcileArgoCD_reconcileServerDeploymentChangedToInsecure(t *testing.T
 			{
 				Name:            "argocd-server",
 				Image:           getArgoContainerImage(a),
-				ImagePullPolicy: corev1.PullAlways,
+				ImagePullPolicy: corev1.PullIfNotPresent,
+				SecurityContext: argoutil.RestrictedSecurityContext(false),
 				Command: []string{
 					"argocd-server",
 					"--insecure",
@@ -1323,6 +1336,7 @@ func TestReconcileArgoCD_reconcileServerDeploymentChangedToInsecure(t *testing.T
 			},
 		},
 		Volumes:            serverDefaultVolumes(),
+		SecurityContext:    argoutil.RestrictedPodSecurityContext(),
 		ServiceAccountName: "argocd-argocd-server",
 	}
 
@@ -1381,63 +1395,53 @@ func appSync(d time.Duration) argoCDOpt {
 		a.Spec.Controller.AppSync = &metav1.Duration{Duration: d}
 	}
 }
-func Test_UpdateNodePlacement(t *testing.T) {
+func TestReconcileArgoCD_reconcileDeployment_nodePlacementUpdate(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+	assert.NoError(t, r.reconcileServerDeployment(a))
 
-	deployment := &appsv1.Deployment{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "argocd-sample-server",
-			Namespace: testNamespace,
-		},
-		Spec: appsv1.DeploymentSpec{
-			Template: corev1.PodTemplateSpec{
-				Spec: corev1.PodSpec{
-					NodeSelector: map[string]string{
-						"test_key1": "test_value1",
-						"test_key2": "test_value2",
-					},
-					Tolerations: []corev1.Toleration{
-						{
-							Key:    "test_key1",
-							Value:  "test_value1",
-							Effect: corev1.TaintEffectNoSchedule,
-						},
-					},
-				},
-			},
-		},
-	}
-	deployment2 := &appsv1.Deployment{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "argocd-sample-server",
-			Namespace: testNamespace,
-		},
-		Spec: appsv1.DeploymentSpec{
-			Template: corev1.PodTemplateSpec{
-				Spec: corev1.PodSpec{
-					NodeSelector: map[string]string{
-						"test_key1": "test_value1",
-					},
-					Tolerations: []corev1.Toleration{
-						{
-							Key:    "test_key1",
-							Value:  "test_value1",
-							Effect: corev1.TaintEffectNoExecute,
-						},
-					},
-				},
-			},
-		},
-	}
-	expectedChange := false
-	actualChange := false
-	updateNodePlacement(deployment, deployment, &actualChange)
-	if actualChange != expectedChange {
-		t.Fatalf("updateNodePlacement failed, value of changed: %t", actualChange)
-	}
-	updateNodePlacement(deployment, deployment2, &actualChange)
-	if actualChange == expectedChange {
-		t.Fatalf("updateNodePlacement failed, value of changed: %t", actualChange)
+	a.Spec.NodePlacement = &argoprojv1alpha1.ArgoCDNodePlacementSpec{
+		NodeSelector: deploymentDefaultNodeSelector(),
+		Tolerations:  deploymentDefaultTolerations(),
 	}
+	assert.NoError(t, r.reconcileServerDeployment(a))
+
+	deployment := &appsv1.Deployment{}
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: "argocd-server", Namespace: testNamespace}, deployment))
+	assert.Equal(t, deploymentDefaultNodeSelector(), deployment.Spec.Template.Spec.NodeSelector)
+	assert.Equal(t, deploymentDefaultTolerations(), deployment.Spec.Template.Spec.Tolerations)
+
+	a.Spec.NodePlacement = nil
+	assert.NoError(t, r.reconcileServerDeployment(a))
+
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: "argocd-server", Namespace: testNamespace}, deployment))
+	assert.Nil(t, deployment.Spec.Template.Spec.NodeSelector)
+	assert.Nil(t, deployment.Spec.Template.Spec.Tolerations)
+}
+
+func TestReconcileArgoCD_reconcileDexDeployment_correctsDrift(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	restoreEnv(t)
+	os.Setenv("DISABLE_DEX", "false")
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+	assert.NoError(t, r.reconcileDexDeployment(a))
+
+	deployment := &appsv1.Deployment{}
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: "argocd-dex-server", Namespace: testNamespace}, deployment))
+	want := deployment.Spec.Template.Spec.DeepCopy()
+
+	// fields that were not compared field by field before are corrected as well
+	deployment.Spec.Template.Spec.Containers[0].LivenessProbe = nil
+	deployment.Spec.Template.Spec.Containers[0].Ports = nil
+	deployment.Spec.Template.Spec.ServiceAccountName = "default"
+	assert.NoError(t, r.Client.Update(context.TODO(), deployment))
+
+	assert.NoError(t, r.reconcileDexDeployment(a))
+
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: "argocd-dex-server", Namespace: testNamespace}, deployment))
+	assert.Equal(t, *want, deployment.Spec.Template.Spec)
 }
 
 func parallelismLimit(n int32) argoCDOpt {
@@ -1641,3 +1645,56 @@ func serverDefaultVolumeMounts() []corev1.VolumeMount {
 	}
 	return mounts
 }
+
+func TestReconcileArgoCD_reconcileRedisDeployment_securityContext(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+	assert.NoError(t, r.reconcileRedisDeployment(a))
+
+	deployment := &appsv1.Deployment{}
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: "argocd-redis", Namespace: testNamespace}, deployment))
+	assert.Equal(t, argoutil.RestrictedSecurityContext(true), deployment.Spec.Template.Spec.Containers[0].SecurityContext)
+	podSecurityContext := deployment.Spec.Template.Spec.SecurityContext
+	assert.Equal(t, int64(999), *podSecurityContext.RunAsUser)
+	assert.True(t, *podSecurityContext.RunAsNonRoot)
+	assert.Equal(t, corev1.SeccompProfileTypeRuntimeDefault, podSecurityContext.SeccompProfile.Type)
+
+	runAsUser := int64(1001)
+	a.Spec.Redis.SecurityContext = &argoprojv1alpha1.ArgoCDSecurityContextSpec{
+		Pod: &corev1.PodSecurityContext{RunAsUser: &runAsUser},
+	}
+	assert.NoError(t, r.reconcileRedisDeployment(a))
+
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: "argocd-redis", Namespace: testNamespace}, deployment))
+	assert.Equal(t, runAsUser, *deployment.Spec.Template.Spec.SecurityContext.RunAsUser)
+	assert.True(t, *deployment.Spec.Template.Spec.SecurityContext.RunAsNonRoot)
+}
+
+// failingPatchClient fails the patches of the object with the given name.
+type failingPatchClient struct {
+	client.Client
+	name string
+}
+
+func (c *failingPatchClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if obj.GetName() == c.name {
+		return apierrors.NewInternalError(errors.New("patch failed"))
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func TestReconcileArgoCD_reconcileDeployments_continuesAfterFailure(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+	r.Client = &failingPatchClient{Client: r.Client, name: nameWithSuffix("dex-server", a)}
+
+	// A failing Dex Deployment does not prevent the other Deployments from being reconciled
+	err := r.reconcileDeployments(a)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "patch failed")
+	for _, suffix := range []string{"redis", "repo-server", "server"} {
+		assert.True(t, argoutil.IsObjectFound(r.Client, a.Namespace, nameWithSuffix(suffix, a), &appsv1.Deployment{}), suffix)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/common"
+	"github.com/argoproj-labs/argocd-operator/controllers/argoutil"
+)
+
+// recordDrift will record that the given fields of the given object were modified out-of-band, by emitting an Event
+// for the given ArgoCD and listing the object in the drifted resources of the ArgoCD status.
+func (r *ReconcileArgoCD) recordDrift(cr *argoprojv1a1.ArgoCD, obj client.Object, conflicts []argoutil.FieldConflict) {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if gvk, err := apiutil.GVKForObject(obj, r.Scheme); err == nil {
+		kind = gvk.Kind
+	}
+
+	var fields, managers []string
+	for _, conflict := range conflicts {
+		if !contains(fields, conflict.Field) {
+			fields = append(fields, conflict.Field)
+		}
+		if !contains(managers, conflict.Manager) {
+			managers = append(managers, conflict.Manager)
+		}
+	}
+	sort.Strings(fields)
+	sort.Strings(managers)
+
+	message := fmt.Sprintf("%s %s was modified by %s, the fields %s have been reset",
+		kind, obj.GetName(), strings.Join(managers, ", "), strings.Join(fields, ", "))
+	log.Info(message)
+	if err := argoutil.CreateEvent(r.Client, "Reconciling", message, "ResourceDrifted", cr.ObjectMeta); err != nil {
+		log.Error(err, "failed to create drift event")
+	}
+
+	setDriftedResource(cr, argoprojv1a1.ArgoCDDriftedResource{
+		Kind:             kind,
+		Name:             obj.GetName(),
+		Fields:           fields,
+		Managers:         managers,
+		LastDetectedTime: metav1.Now(),
+	})
+}
+
+// setDriftedResource will add the given drifted resource to the status of the given ArgoCD, or replace the entry for
+// the same resource.
+func setDriftedResource(cr *argoprojv1a1.ArgoCD, drifted argoprojv1a1.ArgoCDDriftedResource) {
+	for i, existing := range cr.Status.DriftedResources {
+		if existing.Kind == drifted.Kind && existing.Name == drifted.Name {
+			cr.Status.DriftedResources[i] = drifted
+			return
+		}
+	}
+
+	cr.Status.DriftedResources = append(cr.Status.DriftedResources, drifted)
+	sort.Slice(cr.Status.DriftedResources, func(i, j int) bool {
+		a, b := cr.Status.DriftedResources[i], cr.Status.DriftedResources[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+}
+
+// reconcileStatusDriftedResources will remove the drifted resources from the status of the given ArgoCD, for which no
+// drift has been detected within the drift retention.
+func (r *ReconcileArgoCD) reconcileStatusDriftedResources(cr *argoprojv1a1.ArgoCD) {
+	var drifted []argoprojv1a1.ArgoCDDriftedResource
+	for _, resource := range cr.Status.DriftedResources {
+		if metav1.Now().Sub(resource.LastDetectedTime.Time) < common.ArgoCDDriftRetention {
+			drifted = append(drifted, resource)
+		}
+	}
+	cr.Status.DriftedResources = drifted
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/common"
+	"github.com/argoproj-labs/argocd-operator/controllers/argoutil"
+)
+
+func TestReconcileArgoCD_recordDrift(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	cm := newConfigMapWithName(common.ArgoCDConfigMapName, a)
+	r.recordDrift(a, cm, []argoutil.FieldConflict{
+		{Field: ".data.admin.enabled", Manager: "kubectl-edit"},
+		{Field: ".data.url", Manager: "kubectl-edit"},
+	})
+
+	assert.Len(t, a.Status.DriftedResources, 1)
+	drifted := a.Status.DriftedResources[0]
+	assert.Equal(t, "ConfigMap", drifted.Kind)
+	assert.Equal(t, common.ArgoCDConfigMapName, drifted.Name)
+	assert.Equal(t, []string{".data.admin.enabled", ".data.url"}, drifted.Fields)
+	assert.Equal(t, []string{"kubectl-edit"}, drifted.Managers)
+
+	events := &corev1.EventList{}
+	assert.NoError(t, r.Client.List(context.TODO(), events, client.InNamespace(a.Namespace)))
+	assert.Len(t, events.Items, 1)
+	assert.Equal(t, "ResourceDrifted", events.Items[0].Reason)
+	assert.Contains(t, events.Items[0].Message, "ConfigMap argocd-cm was modified by kubectl-edit")
+}
+
+func TestSetDriftedResource(t *testing.T) {
+	a := makeTestArgoCD()
+
+	setDriftedResource(a, argoprojv1alpha1.ArgoCDDriftedResource{Kind: "Deployment", Name: "argocd-server"})
+	setDriftedResource(a, argoprojv1alpha1.ArgoCDDriftedResource{Kind: "ConfigMap", Name: "argocd-rbac-cm"})
+	setDriftedResource(a, argoprojv1alpha1.ArgoCDDriftedResource{Kind: "ConfigMap", Name: "argocd-cm"})
+	setDriftedResource(a, argoprojv1alpha1.ArgoCDDriftedResource{Kind: "Deployment", Name: "argocd-server", Fields: []string{".spec.replicas"}})
+
+	assert.Equal(t, []argoprojv1alpha1.ArgoCDDriftedResource{
+		{Kind: "ConfigMap", Name: "argocd-cm"},
+		{Kind: "ConfigMap", Name: "argocd-rbac-cm"},
+		{Kind: "Deployment", Name: "argocd-server", Fields: []string{".spec.replicas"}},
+	}, a.Status.DriftedResources)
+}
+
+func TestReconcileArgoCD_reconcileStatusDriftedResources(t *testing.T) {
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Status.DriftedResources = []argoprojv1alpha1.ArgoCDDriftedResource{
+			{Kind: "ConfigMap", Name: "argocd-cm", LastDetectedTime: metav1.NewTime(time.Now().Add(-2 * common.ArgoCDDriftRetention))},
+			{Kind: "ConfigMap", Name: "argocd-rbac-cm", LastDetectedTime: metav1.Now()},
+		}
+	})
+	r := makeTestReconciler(t, a)
+
+	r.reconcileStatusDriftedResources(a)
+	assert.Len(t, a.Status.DriftedResources, 1)
+	assert.Equal(t, "argocd-rbac-cm", a.Status.DriftedResources[0].Name)
+}
@@ -24,7 +24,6 @@ import (
 	"text/template"
 
 	"github.com/sethvargo/go-password/password"
-	appsv1 "k8s.io/api/apps/v1"
 
 	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
 	"github.com/argoproj-labs/argocd-operator/common"
@@ -88,21 +87,6 @@ func getGrafanaConfigPath() string {
 	return common.ArgoCDDefaultGrafanaConfigPath
 }
 
-// hasGrafanaSpecChanged will return true if the supported properties differs in the actual versus the desired state.
-func hasGrafanaSpecChanged(actual *appsv1.Deployment, desired *argoprojv1a1.ArgoCD) bool {
-	// Replica count
-	if desired.Spec.Grafana.Size != nil { // Replica count specified in desired state
-		if *desired.Spec.Grafana.Size >= 0 && *actual.Spec.Replicas != *desired.Spec.Grafana.Size {
-			return true
-		}
-	} else { // Replica count NOT specified in desired state
-		if *actual.Spec.Replicas != common.ArgoCDDefaultGrafanaReplicas {
-			return true
-		}
-	}
-	return false
-}
-
 // loadGrafanaConfigs will scan the config directory and read any files ending with '.yaml'
 func loadGrafanaConfigs() (map[string]string, error) {
 	data := make(map[string]string)
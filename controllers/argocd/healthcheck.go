@@ -0,0 +1,90 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+)
+
+// crossplaneHealthCheckKey is the entry of the crossplane health check whose script applies to any Crossplane resource
+// that reports conditions, it is used for the Crossplane claims and composite resources of an ArgoCD.
+const crossplaneHealthCheckKey = "pkg.crossplane.io/Provider"
+
+// isHealthChecksEnabled returns true if bundled health checks are enabled for the given ArgoCD.
+func isHealthChecksEnabled(cr *argoprojv1a1.ArgoCD) bool {
+	return cr.Spec.HealthChecks != nil &&
+		(len(cr.Spec.HealthChecks.Include) > 0 || len(cr.Spec.HealthChecks.CrossplaneClaims) > 0)
+}
+
+// loadHealthChecks will return the bundled health checks that are enabled for the given ArgoCD, keyed by group/kind
+// in the format of the resource.customizations key.
+func loadHealthChecks(cr *argoprojv1a1.ArgoCD) (map[string]resourceOverride, error) {
+	checks := map[string]resourceOverride{}
+	if !isHealthChecksEnabled(cr) {
+		return checks, nil
+	}
+
+	names, err := argoprojv1a1.GetHealthCheckNames()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range cr.Spec.HealthChecks.Include {
+		if !containsString(names, name) {
+			return nil, fmt.Errorf("unknown health check %q, the bundled health checks are: %s", name, strings.Join(names, ", "))
+		}
+
+		overrides, err := loadHealthCheck(name)
+		if err != nil {
+			return nil, err
+		}
+		for key, override := range overrides {
+			checks[key] = override
+		}
+	}
+
+	if len(cr.Spec.HealthChecks.CrossplaneClaims) > 0 {
+		overrides, err := loadHealthCheck("crossplane")
+		if err != nil {
+			return nil, err
+		}
+		for _, claim := range cr.Spec.HealthChecks.CrossplaneClaims {
+			checks[fmt.Sprintf("%s/%s", claim.Group, claim.Kind)] = overrides[crossplaneHealthCheckKey]
+		}
+	}
+	return checks, nil
+}
+
+// loadHealthCheck will return the resource customizations of the bundled health check with the given name, keyed by
+// group/kind.
+func loadHealthCheck(name string) (map[string]resourceOverride, error) {
+	data, err := ioutil.ReadFile(filepath.Join(argoprojv1a1.GetHealthChecksPath(), name+".yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	overrides := map[string]resourceOverride{}
+	if err := yaml.UnmarshalStrict(data, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to parse health check %s: %w", name, err)
+	}
+	return overrides, nil
+}
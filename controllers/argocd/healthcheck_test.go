@@ -0,0 +1,120 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/common"
+	"github.com/argoproj-labs/argocd-operator/controllers/argoutil"
+)
+
+const testHealthChecksPath = "../../build/healthchecks"
+
+func TestLoadHealthChecks_bundled(t *testing.T) {
+	os.Setenv("HEALTH_CHECKS_PATH", testHealthChecksPath)
+	defer os.Unsetenv("HEALTH_CHECKS_PATH")
+
+	names, err := argoprojv1alpha1.GetHealthCheckNames()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cert-manager", "crossplane", "external-secrets", "strimzi"}, names)
+
+	// Every bundled health check can be enabled and compiles
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.HealthChecks = &argoprojv1alpha1.ArgoCDHealthChecksSpec{Include: names}
+	})
+	checks, err := loadHealthChecks(a)
+	assert.NoError(t, err)
+	for key, check := range checks {
+		assert.NotEmpty(t, check.HealthLua, key)
+	}
+
+	rc, err := getResourceCustomizations(a)
+	assert.NoError(t, err)
+	a.Spec.ResourceCustomizations = rc
+	assert.NoError(t, a.ValidateResourceCustomizations())
+}
+
+func TestReconcileArgoCD_reconcileArgoConfigMap_withHealthChecks(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	os.Setenv("HEALTH_CHECKS_PATH", testHealthChecksPath)
+	defer os.Unsetenv("HEALTH_CHECKS_PATH")
+
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Version = "v2.0.5"
+		a.Spec.HealthChecks = &argoprojv1alpha1.ArgoCDHealthChecksSpec{Include: []string{"cert-manager"}}
+		a.Spec.ResourceCustomizations = "cert-manager.io/Issuer:\n  health.lua: |\n    return {}\n"
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NoError(t, r.reconcileArgoConfigMap(a))
+	cm := &corev1.ConfigMap{}
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, common.ArgoCDConfigMapName, cm))
+
+	// The customizations of the user take precedence over the bundled health checks
+	customizations := map[string]resourceOverride{}
+	assert.NoError(t, yaml.UnmarshalStrict([]byte(cm.Data[common.ArgoCDKeyResourceCustomizations]), &customizations))
+	assert.Len(t, customizations, 3)
+	assert.Contains(t, customizations["cert-manager.io/Certificate"].HealthLua, "Waiting for certificate")
+	assert.Contains(t, customizations["cert-manager.io/ClusterIssuer"].HealthLua, "Initializing issuer")
+	assert.Equal(t, "return {}\n", customizations["cert-manager.io/Issuer"].HealthLua)
+
+	// Later versions of Argo CD read a key per resource
+	a.Spec.Version = ""
+	assert.NoError(t, r.reconcileArgoConfigMap(a))
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, common.ArgoCDConfigMapName, cm))
+	assert.Contains(t, cm.Data["resource.customizations.health.cert-manager.io_Certificate"], "Waiting for certificate")
+
+	a.Spec.HealthChecks.Include = []string{"cert-manager", "testing"}
+	err := r.reconcileArgoConfigMap(a)
+	assert.EqualError(t, err, `unknown health check "testing", the bundled health checks are: cert-manager, crossplane, external-secrets, strimzi`)
+}
+
+func TestGetResourceCustomizations_withHealthChecks(t *testing.T) {
+	os.Setenv("HEALTH_CHECKS_PATH", testHealthChecksPath)
+	defer os.Unsetenv("HEALTH_CHECKS_PATH")
+
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.HealthChecks = &argoprojv1alpha1.ArgoCDHealthChecksSpec{
+			Include:          []string{"cert-manager"},
+			CrossplaneClaims: []metav1.GroupKind{{Group: "example.org", Kind: "Database"}},
+		}
+		a.Spec.ResourceCustomizations = "cert-manager.io/Certificate:\n  actions: |\n    discovery.lua: return {}\n"
+	})
+
+	rc, err := getResourceCustomizations(a)
+	assert.NoError(t, err)
+	customizations := map[string]resourceOverride{}
+	assert.NoError(t, yaml.UnmarshalStrict([]byte(rc), &customizations))
+
+	// The customizations are merged into the bundled health checks field by field
+	assert.Contains(t, customizations["cert-manager.io/Certificate"].HealthLua, "Waiting for certificate")
+	assert.Equal(t, "discovery.lua: return {}\n", customizations["cert-manager.io/Certificate"].Actions)
+
+	// The Crossplane claims use the health check of the Crossplane packages, without enabling it for the packages
+	crossplane, err := loadHealthCheck("crossplane")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, customizations["example.org/Database"].HealthLua)
+	assert.Equal(t, crossplane[crossplaneHealthCheckKey].HealthLua, customizations["example.org/Database"].HealthLua)
+	assert.NotContains(t, customizations, crossplaneHealthCheckKey)
+}
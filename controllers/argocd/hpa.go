@@ -57,6 +57,9 @@ func (r *ReconcileArgoCD) reconcileServerHPA(cr *argoprojv1a1.ArgoCD) error {
 		if !cr.Spec.Server.Autoscale.Enabled {
 			return r.Client.Delete(context.TODO(), hpa) // HorizontalPodAutoscaler found but globally disabled, delete it.
 		}
+		if syncCommonMetadata(cr, hpa) {
+			return r.Client.Update(context.TODO(), hpa)
+		}
 		return nil // HorizontalPodAutoscaler found and configured, nothing do to, move along...
 	}
 
@@ -82,6 +85,7 @@ func (r *ReconcileArgoCD) reconcileServerHPA(cr *argoprojv1a1.ArgoCD) error {
 		}
 	}
 
+	syncCommonMetadata(cr, hpa)
 	return r.Client.Create(context.TODO(), hpa)
 }
 
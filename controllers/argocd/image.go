@@ -0,0 +1,116 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"encoding/json"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/common"
+	"github.com/argoproj-labs/argocd-operator/controllers/argoutil"
+)
+
+// mirrorImage will point the given image reference to the image registry of the given ArgoCD, if the image is hosted
+// on one of the mirrored public registries.
+func mirrorImage(cr *argoprojv1a1.ArgoCD, image string) string {
+	return argoutil.RewriteImageRegistry(image, argoutil.ImageRegistry(cr))
+}
+
+// getImagePullPolicy will return the pull policy for the given image of a component, preferring the given pull policy
+// of the component over the one of the given ArgoCD. Any fallbacks are used when neither is set, before falling back
+// to the default for the image.
+func getImagePullPolicy(cr *argoprojv1a1.ArgoCD, policy corev1.PullPolicy, image string, fallbacks ...corev1.PullPolicy) corev1.PullPolicy {
+	return argoutil.ImagePullPolicy(image, append([]corev1.PullPolicy{policy, cr.Spec.ImagePullPolicy}, fallbacks...)...)
+}
+
+// setImagePullSecrets will add the image pull secrets of the given ArgoCD that are missing from the pod template of the
+// given Deployment or StatefulSet. It returns true if any secret was added.
+func setImagePullSecrets(cr *argoprojv1a1.ArgoCD, obj client.Object) bool {
+	template := podTemplateOf(obj)
+	if template == nil {
+		return false
+	}
+
+	var merged bool
+	template.Spec.ImagePullSecrets, merged = mergeImagePullSecrets(template.Spec.ImagePullSecrets, cr.Spec.ImagePullSecrets)
+	return merged
+}
+
+// syncImagePullSecrets will set the image pull secrets of the given ArgoCD on the given ServiceAccount, which is
+// written with client-side updates. The names of the secrets of the ArgoCD are recorded in an annotation of the
+// ServiceAccount, so that the secrets that are no longer part of the ArgoCD are removed again. Secrets that were added
+// by others, e.g. the dockercfg secret on OpenShift, are retained. Returns true if the ServiceAccount has changed.
+func syncImagePullSecrets(cr *argoprojv1a1.ArgoCD, sa *corev1.ServiceAccount) bool {
+	original := sa.DeepCopy()
+
+	var previous []string
+	if value, ok := sa.Annotations[common.AnnotationImagePullSecrets]; ok {
+		_ = json.Unmarshal([]byte(value), &previous) // An invalid record has no secrets to remove
+	}
+	removed := make(map[string]bool, len(previous))
+	for _, name := range previous {
+		removed[name] = true
+	}
+	var current []string
+	for _, secret := range cr.Spec.ImagePullSecrets {
+		delete(removed, secret.Name)
+		current = append(current, secret.Name)
+	}
+
+	var secrets []corev1.LocalObjectReference
+	for _, secret := range sa.ImagePullSecrets {
+		if !removed[secret.Name] {
+			secrets = append(secrets, secret)
+		}
+	}
+	sa.ImagePullSecrets, _ = mergeImagePullSecrets(secrets, cr.Spec.ImagePullSecrets)
+
+	sa.Annotations = removeMetadata(sa.Annotations, []string{common.AnnotationImagePullSecrets})
+	if len(current) > 0 {
+		record, _ := json.Marshal(current) // Marshaling a string slice cannot fail
+		sa.Annotations = mergeMetadata(sa.Annotations, nil, map[string]string{
+			common.AnnotationImagePullSecrets: string(record),
+		})
+	}
+
+	return !reflect.DeepEqual(original.ImagePullSecrets, sa.ImagePullSecrets) ||
+		!reflect.DeepEqual(original.Annotations, sa.Annotations)
+}
+
+// containsImagePullSecret returns true if the given image pull secrets contain a secret with the given name.
+func containsImagePullSecret(secrets []corev1.LocalObjectReference, name string) bool {
+	for _, secret := range secrets {
+		if secret.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeImagePullSecrets will append the given additions that are missing from the existing image pull secrets.
+func mergeImagePullSecrets(existing []corev1.LocalObjectReference, additions []corev1.LocalObjectReference) ([]corev1.LocalObjectReference, bool) {
+	merged := false
+	for _, addition := range additions {
+		if !containsImagePullSecret(existing, addition.Name) {
+			existing = append(existing, addition)
+			merged = true
+		}
+	}
+	return existing, merged
+}
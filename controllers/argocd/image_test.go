@@ -0,0 +1,119 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/common"
+	"github.com/argoproj-labs/argocd-operator/controllers/argoutil"
+)
+
+func TestReconcileArgoCD_imageRegistry(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.ImageRegistry = "mirror.example.com"
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NoError(t, r.reconcileServerDeployment(a))
+	deployment := &appsv1.Deployment{}
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, nameWithSuffix("server", a), deployment))
+	assert.Equal(t,
+		"mirror.example.com/argoproj/argocd@"+common.ArgoCDDefaultArgoVersion,
+		deployment.Spec.Template.Spec.Containers[0].Image)
+
+	// Images that are not hosted on one of the mirrored registries are left untouched
+	a.Spec.Image = "registry.example.com/argocd"
+	a.Spec.Version = "v2.3.3"
+	assert.Equal(t, "registry.example.com/argocd:v2.3.3", getArgoContainerImage(a))
+}
+
+func TestReconcileArgoCD_imagePullSecrets(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	pullSecrets := []corev1.LocalObjectReference{{Name: "mirror-credentials"}}
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.ImagePullSecrets = pullSecrets
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NoError(t, r.reconcileServerDeployment(a))
+	deployment := &appsv1.Deployment{}
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, nameWithSuffix("server", a), deployment))
+	assert.Equal(t, pullSecrets, deployment.Spec.Template.Spec.ImagePullSecrets)
+
+	// The secrets of an existing ServiceAccount, e.g. the dockercfg secret on OpenShift, are retained
+	existing := newServiceAccountWithName(common.ArgoCDServerComponent, a)
+	existing.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "argocd-server-dockercfg"}}
+	assert.NoError(t, r.Client.Create(context.TODO(), existing))
+
+	_, err := r.reconcileServiceAccount(common.ArgoCDServerComponent, a)
+	assert.NoError(t, err)
+	sa := &corev1.ServiceAccount{}
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, existing.Name, sa))
+	assert.Equal(t, []corev1.LocalObjectReference{
+		{Name: "argocd-server-dockercfg"},
+		{Name: "mirror-credentials"},
+	}, sa.ImagePullSecrets)
+
+	// Secrets that are removed from the ArgoCD are removed from the ServiceAccount
+	a.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "other-credentials"}}
+	_, err = r.reconcileServiceAccount(common.ArgoCDServerComponent, a)
+	assert.NoError(t, err)
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, existing.Name, sa))
+	assert.Equal(t, []corev1.LocalObjectReference{
+		{Name: "argocd-server-dockercfg"},
+		{Name: "other-credentials"},
+	}, sa.ImagePullSecrets)
+
+	a.Spec.ImagePullSecrets = nil
+	_, err = r.reconcileServiceAccount(common.ArgoCDServerComponent, a)
+	assert.NoError(t, err)
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, existing.Name, sa))
+	assert.Equal(t, []corev1.LocalObjectReference{{Name: "argocd-server-dockercfg"}}, sa.ImagePullSecrets)
+	assert.NotContains(t, sa.Annotations, common.AnnotationImagePullSecrets)
+}
+
+func TestReconcileArgoCD_imagePullPolicy(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	// The default images are referenced by digest, so they are only pulled if not present
+	assert.NoError(t, r.reconcileRepoDeployment(a))
+	deployment := &appsv1.Deployment{}
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, nameWithSuffix("repo-server", a), deployment))
+	assert.Equal(t, corev1.PullIfNotPresent, deployment.Spec.Template.Spec.InitContainers[0].ImagePullPolicy)
+	assert.Equal(t, corev1.PullIfNotPresent, deployment.Spec.Template.Spec.Containers[0].ImagePullPolicy)
+
+	// The pull policy of the component takes precedence over the one of the instance
+	a.Spec.ImagePullPolicy = corev1.PullAlways
+	a.Spec.Repo.ImagePullPolicy = corev1.PullNever
+	assert.NoError(t, r.reconcileRepoDeployment(a))
+	assert.NoError(t, r.reconcileServerDeployment(a))
+
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, nameWithSuffix("repo-server", a), deployment))
+	assert.Equal(t, corev1.PullNever, deployment.Spec.Template.Spec.InitContainers[0].ImagePullPolicy)
+	assert.Equal(t, corev1.PullNever, deployment.Spec.Template.Spec.Containers[0].ImagePullPolicy)
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, nameWithSuffix("server", a), deployment))
+	assert.Equal(t, corev1.PullAlways, deployment.Spec.Template.Spec.Containers[0].ImagePullPolicy)
+}
@@ -20,7 +20,6 @@ import (
 
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
 	"github.com/argoproj-labs/argocd-operator/common"
@@ -94,15 +93,11 @@ func (r *ReconcileArgoCD) reconcileIngresses(cr *argoprojv1a1.ArgoCD) error {
 // reconcileArgoServerIngress will ensure that the ArgoCD Server Ingress is present.
 func (r *ReconcileArgoCD) reconcileArgoServerIngress(cr *argoprojv1a1.ArgoCD) error {
 	ingress := newIngressWithSuffix("server", cr)
-	if argoutil.IsObjectFound(r.Client, cr.Namespace, ingress.Name, ingress) {
-		if !cr.Spec.Server.Ingress.Enabled {
+	if !cr.Spec.Server.Ingress.Enabled {
+		if argoutil.IsObjectFound(r.Client, cr.Namespace, ingress.Name, ingress) {
 			// Ingress exists but enabled flag has been set to false, delete the Ingress
 			return r.Client.Delete(context.TODO(), ingress)
 		}
-		return nil // Ingress found and enabled, do nothing
-	}
-
-	if !cr.Spec.Server.Ingress.Enabled {
 		return nil // Ingress not enabled, move along...
 	}
 
@@ -159,24 +154,17 @@ func (r *ReconcileArgoCD) reconcileArgoServerIngress(cr *argoprojv1a1.ArgoCD) er
 		ingress.Spec.TLS = cr.Spec.Server.Ingress.TLS
 	}
 
-	if err := controllerutil.SetControllerReference(cr, ingress, r.Scheme); err != nil {
-		return err
-	}
-	return r.Client.Create(context.TODO(), ingress)
+	return r.applyResource(cr, ingress)
 }
 
 // reconcileArgoServerGRPCIngress will ensure that the ArgoCD Server GRPC Ingress is present.
 func (r *ReconcileArgoCD) reconcileArgoServerGRPCIngress(cr *argoprojv1a1.ArgoCD) error {
 	ingress := newIngressWithSuffix("grpc", cr)
-	if argoutil.IsObjectFound(r.Client, cr.Namespace, ingress.Name, ingress) {
-		if !cr.Spec.Server.GRPC.Ingress.Enabled {
+	if !cr.Spec.Server.GRPC.Ingress.Enabled {
+		if argoutil.IsObjectFound(r.Client, cr.Namespace, ingress.Name, ingress) {
 			// Ingress exists but enabled flag has been set to false, delete the Ingress
 			return r.Client.Delete(context.TODO(), ingress)
 		}
-		return nil // Ingress found and enabled, do nothing
-	}
-
-	if !cr.Spec.Server.GRPC.Ingress.Enabled {
 		return nil // Ingress not enabled, move along...
 	}
 
@@ -232,24 +220,17 @@ func (r *ReconcileArgoCD) reconcileArgoServerGRPCIngress(cr *argoprojv1a1.ArgoCD
 		ingress.Spec.TLS = cr.Spec.Server.GRPC.Ingress.TLS
 	}
 
-	if err := controllerutil.SetControllerReference(cr, ingress, r.Scheme); err != nil {
-		return err
-	}
-	return r.Client.Create(context.TODO(), ingress)
+	return r.applyResource(cr, ingress)
 }
 
 // reconcileGrafanaIngress will ensure that the ArgoCD Server GRPC Ingress is present.
 func (r *ReconcileArgoCD) reconcileGrafanaIngress(cr *argoprojv1a1.ArgoCD) error {
 	ingress := newIngressWithSuffix("grafana", cr)
-	if argoutil.IsObjectFound(r.Client, cr.Namespace, ingress.Name, ingress) {
-		if !cr.Spec.Grafana.Enabled || !cr.Spec.Grafana.Ingress.Enabled {
+	if !cr.Spec.Grafana.Enabled || !cr.Spec.Grafana.Ingress.Enabled {
+		if argoutil.IsObjectFound(r.Client, cr.Namespace, ingress.Name, ingress) {
 			// Ingress exists but enabled flag has been set to false, delete the Ingress
 			return r.Client.Delete(context.TODO(), ingress)
 		}
-		return nil // Ingress found and enabled, do nothing
-	}
-
-	if !cr.Spec.Grafana.Enabled || !cr.Spec.Grafana.Ingress.Enabled {
 		return nil // Grafana itself or Ingress not enabled, move along...
 	}
 
@@ -307,24 +288,17 @@ func (r *ReconcileArgoCD) reconcileGrafanaIngress(cr *argoprojv1a1.ArgoCD) error
 		ingress.Spec.TLS = cr.Spec.Grafana.Ingress.TLS
 	}
 
-	if err := controllerutil.SetControllerReference(cr, ingress, r.Scheme); err != nil {
-		return err
-	}
-	return r.Client.Create(context.TODO(), ingress)
+	return r.applyResource(cr, ingress)
 }
 
 // reconcilePrometheusIngress will ensure that the Prometheus Ingress is present.
 func (r *ReconcileArgoCD) reconcilePrometheusIngress(cr *argoprojv1a1.ArgoCD) error {
 	ingress := newIngressWithSuffix("prometheus", cr)
-	if argoutil.IsObjectFound(r.Client, cr.Namespace, ingress.Name, ingress) {
-		if !cr.Spec.Prometheus.Enabled || !cr.Spec.Prometheus.Ingress.Enabled {
+	if !cr.Spec.Prometheus.Enabled || !cr.Spec.Prometheus.Ingress.Enabled {
+		if argoutil.IsObjectFound(r.Client, cr.Namespace, ingress.Name, ingress) {
 			// Ingress exists but enabled flag has been set to false, delete the Ingress
 			return r.Client.Delete(context.TODO(), ingress)
 		}
-		return nil // Ingress found and enabled, do nothing
-	}
-
-	if !cr.Spec.Prometheus.Enabled || !cr.Spec.Prometheus.Ingress.Enabled {
 		return nil // Prometheus itself or Ingress not enabled, move along...
 	}
 
@@ -379,8 +353,5 @@ func (r *ReconcileArgoCD) reconcilePrometheusIngress(cr *argoprojv1a1.ArgoCD) er
 		ingress.Spec.TLS = cr.Spec.Prometheus.Ingress.TLS
 	}
 
-	if err := controllerutil.SetControllerReference(cr, ingress, r.Scheme); err != nil {
-		return err
-	}
-	return r.Client.Create(context.TODO(), ingress)
+	return r.applyResource(cr, ingress)
 }
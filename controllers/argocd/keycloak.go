@@ -20,7 +20,6 @@ import (
 	b64 "encoding/base64"
 	json "encoding/json"
 	"fmt"
-	"os"
 
 	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
 	"github.com/argoproj-labs/argocd-operator/common"
@@ -154,38 +153,25 @@ type CustomKeycloakAPIRealm struct {
 
 // getKeycloakContainerImage will return the container image for the Keycloak.
 //
-// There are three possible options for configuring the image, and this is the
+// There are four possible options for configuring the image, and this is the
 // order of preference.
 //
 // 1. from the Spec, the spec.sso field has an image and version to use for
 // generating an image reference.
 // 2. From the Environment, this looks for the `ARGOCD_KEYCLOAK_IMAGE` field and uses
 // that if the spec is not configured.
-// 3. the default is configured in common.ArgoCDKeycloakVersion and
+// 3. From the Environment, this looks for the `RELATED_IMAGE_KEYCLOAK` field set
+// by OLM and uses that if neither of the above is configured.
+// 4. the default is configured in common.ArgoCDKeycloakVersion and
 // common.ArgoCDKeycloakImageName.
 func getKeycloakContainerImage(cr *argoprojv1a1.ArgoCD) string {
-	defaultImg, defaultTag := false, false
-	img := cr.Spec.SSO.Image
-	if img == "" {
-		img = common.ArgoCDKeycloakImage
-		if IsTemplateAPIAvailable() {
-			img = common.ArgoCDKeycloakImageForOpenShift
-		}
-		defaultImg = true
-	}
-
-	tag := cr.Spec.SSO.Version
-	if tag == "" {
-		tag = common.ArgoCDKeycloakVersion
-		if IsTemplateAPIAvailable() {
-			tag = common.ArgoCDKeycloakVersionForOpenShift
-		}
-		defaultTag = true
-	}
-	if e := os.Getenv(common.ArgoCDKeycloakImageEnvName); e != "" && (defaultTag && defaultImg) {
-		return e
+	defaultImg, defaultTag := common.ArgoCDKeycloakImage, common.ArgoCDKeycloakVersion
+	if IsTemplateAPIAvailable() {
+		defaultImg, defaultTag = common.ArgoCDKeycloakImageForOpenShift, common.ArgoCDKeycloakVersionForOpenShift
 	}
-	return argoutil.CombineImageTag(img, tag)
+	return mirrorImage(cr, argoutil.ContainerImage(cr.Spec.SSO.Image, cr.Spec.SSO.Version,
+		defaultImg, defaultTag,
+		common.ArgoCDKeycloakImageEnvName, common.ArgoCDKeycloakRelatedImageEnvName))
 }
 
 func getKeycloakConfigMapTemplate(ns string) *corev1.ConfigMap {
@@ -270,7 +256,7 @@ func getKeycloakContainer(cr *argoprojv1a1.ArgoCD) corev1.Container {
 	return corev1.Container{
 		Env:             proxyEnvVars(envVars...),
 		Image:           getKeycloakContainerImage(cr),
-		ImagePullPolicy: "Always",
+		ImagePullPolicy: getImagePullPolicy(cr, cr.Spec.SSO.ImagePullPolicy, getKeycloakContainerImage(cr)),
 		LivenessProbe: &corev1.Probe{
 			FailureThreshold: 3,
 			Handler: corev1.Handler{
@@ -407,6 +393,8 @@ func getKeycloakDeploymentConfigTemplate(cr *argoprojv1a1.ArgoCD) *appsv1.Deploy
 		dc.Spec.Template.Spec.Tolerations = cr.Spec.NodePlacement.Tolerations
 	}
 
+	dc.Spec.Template.Spec.ImagePullSecrets = cr.Spec.ImagePullSecrets
+
 	return dc
 
 }
@@ -476,6 +464,9 @@ func newKeycloakTemplate(cr *argoprojv1a1.ArgoCD) (template.Template, error) {
 	configMapTemplate := getKeycloakConfigMapTemplate(ns)
 	secretTemplate := getKeycloakSecretTemplate(ns)
 	deploymentConfigTemplate := getKeycloakDeploymentConfigTemplate(cr)
+	if err := applyPodTemplateOverride(deploymentConfigTemplate.Spec.Template, cr.Spec.SSO.PodTemplateOverride); err != nil {
+		return tmpl, err
+	}
 	serviceTemplate := getKeycloakServiceTemplate(ns)
 	routeTemplate := getKeycloakRouteTemplate(ns)
 
@@ -659,9 +650,10 @@ func newKeycloakDeployment(cr *argoprojv1a1.ArgoCD) *k8sappsv1.Deployment {
 				Spec: corev1.PodSpec{
 					Containers: []corev1.Container{
 						{
-							Name:  defaultKeycloakIdentifier,
-							Image: getKeycloakContainerImage(cr),
-							Env:   proxyEnvVars(getKeycloakContainerEnv()...),
+							Name:            defaultKeycloakIdentifier,
+							Image:           getKeycloakContainerImage(cr),
+							ImagePullPolicy: getImagePullPolicy(cr, cr.Spec.SSO.ImagePullPolicy, getKeycloakContainerImage(cr), corev1.PullIfNotPresent),
+							Env:             proxyEnvVars(getKeycloakContainerEnv()...),
 							Ports: []corev1.ContainerPort{
 								{Name: "http", ContainerPort: httpPort},
 								{Name: "https", ContainerPort: portTLS},
@@ -676,6 +668,7 @@ func newKeycloakDeployment(cr *argoprojv1a1.ArgoCD) *k8sappsv1.Deployment {
 							},
 						},
 					},
+					ImagePullSecrets: cr.Spec.ImagePullSecrets,
 				},
 			},
 		},
@@ -691,6 +684,7 @@ func (r *ReconcileArgoCD) newKeycloakInstance(cr *argoprojv1a1.ArgoCD) error {
 
 	if err != nil {
 		if errors.IsNotFound(err) {
+			syncCommonMetadata(cr, ing)
 			if err := controllerutil.SetControllerReference(cr, ing, r.Scheme); err != nil {
 				return err
 			}
@@ -701,6 +695,10 @@ func (r *ReconcileArgoCD) newKeycloakInstance(cr *argoprojv1a1.ArgoCD) error {
 		} else {
 			return err
 		}
+	} else if syncCommonMetadata(cr, ing) {
+		if err := r.Client.Update(context.TODO(), ing); err != nil {
+			return err
+		}
 	}
 
 	// Create Keycloak Service
@@ -710,6 +708,7 @@ func (r *ReconcileArgoCD) newKeycloakInstance(cr *argoprojv1a1.ArgoCD) error {
 
 	if err != nil {
 		if errors.IsNotFound(err) {
+			syncCommonMetadata(cr, svc)
 			if err := controllerutil.SetControllerReference(cr, svc, r.Scheme); err != nil {
 				return err
 			}
@@ -720,15 +719,26 @@ func (r *ReconcileArgoCD) newKeycloakInstance(cr *argoprojv1a1.ArgoCD) error {
 		} else {
 			return err
 		}
+	} else if syncCommonMetadata(cr, svc) {
+		if err := r.Client.Update(context.TODO(), svc); err != nil {
+			return err
+		}
 	}
 
 	// Create Keycloak Deployment
 	dep := newKeycloakDeployment(cr)
+	if err := argoutil.ApplySecurityContext(&dep.Spec.Template.Spec, false, nil); err != nil {
+		return err
+	}
+	if err := applyPodTemplateOverride(&dep.Spec.Template, cr.Spec.SSO.PodTemplateOverride); err != nil {
+		return err
+	}
 	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: dep.Name,
 		Namespace: dep.Namespace}, dep)
 
 	if err != nil {
 		if errors.IsNotFound(err) {
+			syncCommonMetadata(cr, dep)
 			if err := controllerutil.SetControllerReference(cr, dep, r.Scheme); err != nil {
 				return err
 			}
@@ -739,6 +749,10 @@ func (r *ReconcileArgoCD) newKeycloakInstance(cr *argoprojv1a1.ArgoCD) error {
 		} else {
 			return err
 		}
+	} else if syncCommonMetadata(cr, dep) {
+		if err := r.Client.Update(context.TODO(), dep); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -1036,7 +1050,7 @@ func (r *ReconcileArgoCD) updateArgoCDConfiguration(cr *argoprojv1a1.ArgoCD, kRo
 		return err
 	}
 
-	argoCDSecret.Data["oidc.keycloak.clientSecret"] = []byte(oAuthClientSecret)
+	argoCDSecret.Data[common.ArgoCDKeyKeycloakClientSecret] = []byte(oAuthClientSecret)
 	err = r.Client.Update(context.TODO(), argoCDSecret)
 	if err != nil {
 		log.Error(err, fmt.Sprintf("Error updating ArgoCD Secret for ArgoCD %s in namespace %s",
@@ -1061,6 +1075,7 @@ func (r *ReconcileArgoCD) updateArgoCDConfiguration(cr *argoprojv1a1.ArgoCD, kRo
 			GrantMethod: "prompt",
 		}
 
+		syncCommonMetadata(cr, oAuthClient)
 		err = controllerutil.SetOwnerReference(cr, oAuthClient, r.Scheme)
 		if err != nil {
 			return err
@@ -1074,6 +1089,10 @@ func (r *ReconcileArgoCD) updateArgoCDConfiguration(cr *argoprojv1a1.ArgoCD, kRo
 					return err
 				}
 			}
+		} else if syncCommonMetadata(cr, oAuthClient) {
+			if err := r.Client.Update(context.TODO(), oAuthClient); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -1295,13 +1314,15 @@ func (r *ReconcileArgoCD) reconcileKeycloakForOpenShift(cr *argoprojv1a1.ArgoCD)
 	if err != nil {
 		return err
 	}
+	existingTemplateInstance := &template.TemplateInstance{}
 	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: templateInstanceRef.Name,
-		Namespace: templateInstanceRef.Namespace}, &template.TemplateInstance{})
+		Namespace: templateInstanceRef.Namespace}, existingTemplateInstance)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			log.Info(fmt.Sprintf("Template API found, Installing keycloak using openshift templates for ArgoCD %s in namespace %s",
 				cr.Name, cr.Namespace))
 
+			syncCommonMetadata(cr, templateInstanceRef)
 			if err := controllerutil.SetControllerReference(cr, templateInstanceRef, r.Scheme); err != nil {
 				return err
 			}
@@ -1313,6 +1334,10 @@ func (r *ReconcileArgoCD) reconcileKeycloakForOpenShift(cr *argoprojv1a1.ArgoCD)
 		} else {
 			return err
 		}
+	} else if syncCommonMetadata(cr, existingTemplateInstance) {
+		if err := r.Client.Update(context.TODO(), existingTemplateInstance); err != nil {
+			return err
+		}
 	}
 
 	existingDC := &oappsv1.DeploymentConfig{
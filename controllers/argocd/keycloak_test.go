@@ -16,6 +16,7 @@ package argocd
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 	"testing"
 
@@ -25,6 +26,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	resourcev1 "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	argoappv1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
 	"github.com/argoproj-labs/argocd-operator/common"
@@ -167,6 +169,29 @@ func TestNewKeycloakTemplate_testDeploymentConfig(t *testing.T) {
 	assert.Equal(t, dc.Spec.Template.Spec.Volumes, fakeVolumes)
 }
 
+func TestNewKeycloakTemplate_podTemplateOverride(t *testing.T) {
+	// For OpenShift Container Platform.
+	templateAPIFound = true
+	defer removeTemplateAPI()
+
+	a := makeTestArgoCD()
+	a.Spec.SSO = &argoappv1.ArgoCDSSOSpec{
+		Provider:            "keycloak",
+		PodTemplateOverride: &runtime.RawExtension{Raw: []byte(`{"spec": {"priorityClassName": "system-cluster-critical"}}`)},
+	}
+	tmpl, err := newKeycloakTemplate(a)
+	assert.NoError(t, err)
+
+	dc := &appsv1.DeploymentConfig{}
+	assert.NoError(t, json.Unmarshal(tmpl.Objects[2].Raw, dc))
+	assert.Equal(t, "system-cluster-critical", dc.Spec.Template.Spec.PriorityClassName)
+	assert.Equal(t, fakeVolumes, dc.Spec.Template.Spec.Volumes)
+
+	a.Spec.SSO.PodTemplateOverride = &runtime.RawExtension{Raw: []byte(`{"spec": {"containers": "invalid"}}`)}
+	_, err = newKeycloakTemplate(a)
+	assert.Error(t, err)
+}
+
 func TestNewKeycloakTemplate_testKeycloakContainer(t *testing.T) {
 	// For OpenShift Container Platform.
 	templateAPIFound = true
@@ -179,7 +204,7 @@ func TestNewKeycloakTemplate_testKeycloakContainer(t *testing.T) {
 	kc := getKeycloakContainer(a)
 	assert.Equal(t, kc.Image,
 		"registry.redhat.io/rh-sso-7/sso75-openshift-rhel8@sha256:720a7e4c4926c41c1219a90daaea3b971a3d0da5a152a96fed4fb544d80f52e3")
-	assert.Equal(t, kc.ImagePullPolicy, corev1.PullAlways)
+	assert.Equal(t, kc.ImagePullPolicy, corev1.PullIfNotPresent)
 	assert.Equal(t, kc.Name, "${APPLICATION_NAME}")
 }
 
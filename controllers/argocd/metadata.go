@@ -0,0 +1,192 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/common"
+)
+
+// reservedLabels are the labels set by the operator that are used in selectors, they cannot be overridden by the
+// common labels of an ArgoCD.
+var reservedLabels = []string{
+	common.ArgoCDKeyName,
+	common.ArgoCDKeyPartOf,
+	common.ArgoCDKeyManagedBy,
+}
+
+// setCommonMetadata will add the common labels and annotations of the given ArgoCD to the given generated object. The
+// pod template of Deployments and StatefulSets also gets the common pod labels and annotations, which take precedence
+// over the common ones. Labels of the pod template that are set by the operator are never overridden, as the selector
+// of the workload depends on them.
+func setCommonMetadata(cr *argoprojv1a1.ArgoCD, obj client.Object) {
+	obj.SetLabels(mergeMetadata(obj.GetLabels(), reservedLabels, cr.Spec.CommonLabels))
+	obj.SetAnnotations(mergeMetadata(obj.GetAnnotations(), nil, cr.Spec.CommonAnnotations))
+
+	template := podTemplateOf(obj)
+	if template == nil {
+		return
+	}
+
+	// Every existing label of the pod template is reserved
+	reserved := make([]string, 0, len(template.Labels))
+	for key := range template.Labels {
+		reserved = append(reserved, key)
+	}
+	template.Labels = mergeMetadata(template.Labels, reserved, cr.Spec.CommonLabels, cr.Spec.CommonPodLabels)
+	template.Annotations = mergeMetadata(template.Annotations, nil, cr.Spec.CommonAnnotations, cr.Spec.CommonPodAnnotations)
+}
+
+// commonMetadataKeys are the keys of the common labels and annotations that were set on an object, they are recorded
+// in the common.AnnotationCommonMetadata annotation of objects that are written with client-side updates.
+type commonMetadataKeys struct {
+	Labels              []string `json:"labels,omitempty"`
+	Annotations         []string `json:"annotations,omitempty"`
+	TemplateLabels      []string `json:"templateLabels,omitempty"`
+	TemplateAnnotations []string `json:"templateAnnotations,omitempty"`
+}
+
+// syncCommonMetadata will set the common metadata of the given ArgoCD on the given object like setCommonMetadata, for
+// objects that are written with client-side updates instead of a server-side apply. A client-side update does not
+// remove the labels and annotations that were set before, so the keys that are set are recorded in an annotation of
+// the object and the keys that are no longer part of the common metadata are removed. Returns true if the metadata of
+// the object has changed.
+func syncCommonMetadata(cr *argoprojv1a1.ArgoCD, obj client.Object) bool {
+	template := podTemplateOf(obj)
+	original := obj.DeepCopyObject().(client.Object)
+
+	var previous commonMetadataKeys
+	if value, ok := obj.GetAnnotations()[common.AnnotationCommonMetadata]; ok {
+		_ = json.Unmarshal([]byte(value), &previous) // An invalid record has no keys to remove
+	}
+	obj.SetLabels(removeMetadata(obj.GetLabels(), previous.Labels))
+	obj.SetAnnotations(removeMetadata(obj.GetAnnotations(), append(previous.Annotations, common.AnnotationCommonMetadata)))
+
+	current := commonMetadataKeys{
+		Labels:      addedKeys(obj.GetLabels(), reservedLabels, cr.Spec.CommonLabels),
+		Annotations: addedKeys(obj.GetAnnotations(), nil, cr.Spec.CommonAnnotations),
+	}
+	if template != nil {
+		template.Labels = removeMetadata(template.Labels, previous.TemplateLabels)
+		template.Annotations = removeMetadata(template.Annotations, previous.TemplateAnnotations)
+
+		reserved := make([]string, 0, len(template.Labels))
+		for key := range template.Labels {
+			reserved = append(reserved, key)
+		}
+		current.TemplateLabels = addedKeys(template.Labels, reserved, cr.Spec.CommonLabels, cr.Spec.CommonPodLabels)
+		current.TemplateAnnotations = addedKeys(template.Annotations, nil, cr.Spec.CommonAnnotations, cr.Spec.CommonPodAnnotations)
+	}
+
+	setCommonMetadata(cr, obj)
+	if !reflect.DeepEqual(current, commonMetadataKeys{}) {
+		record, _ := json.Marshal(current) // Marshaling string slices cannot fail
+		obj.SetAnnotations(mergeMetadata(obj.GetAnnotations(), nil, map[string]string{
+			common.AnnotationCommonMetadata: string(record),
+		}))
+	}
+
+	changed := !reflect.DeepEqual(original.GetLabels(), obj.GetLabels()) ||
+		!reflect.DeepEqual(original.GetAnnotations(), obj.GetAnnotations())
+	if template != nil {
+		originalTemplate := podTemplateOf(original)
+		changed = changed || !reflect.DeepEqual(originalTemplate.Labels, template.Labels) ||
+			!reflect.DeepEqual(originalTemplate.Annotations, template.Annotations)
+	}
+	return changed
+}
+
+// addedKeys returns the sorted keys of the given additions that mergeMetadata will set on the given existing labels or
+// annotations, i.e. every key except for the reserved keys that are already present.
+func addedKeys(existing map[string]string, reserved []string, additions ...map[string]string) []string {
+	skip := make(map[string]bool, len(reserved))
+	for _, key := range reserved {
+		if _, ok := existing[key]; ok {
+			skip[key] = true
+		}
+	}
+
+	var keys []string
+	seen := make(map[string]bool)
+	for _, addition := range additions {
+		for key := range addition {
+			if !skip[key] && !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// removeMetadata will remove the given keys from the given labels or annotations.
+func removeMetadata(existing map[string]string, keys []string) map[string]string {
+	if len(existing) == 0 || len(keys) == 0 {
+		return existing
+	}
+
+	removed := make(map[string]string, len(existing))
+	for key, value := range existing {
+		removed[key] = value
+	}
+	for _, key := range keys {
+		delete(removed, key)
+	}
+	return removed
+}
+
+// podTemplateOf returns the pod template of the given object, or nil if the object has no pod template.
+func podTemplateOf(obj client.Object) *corev1.PodTemplateSpec {
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		return &o.Spec.Template
+	case *appsv1.StatefulSet:
+		return &o.Spec.Template
+	}
+	return nil
+}
+
+// mergeMetadata will merge the given additions into the existing labels or annotations, in order, except for the
+// reserved keys that are already present.
+func mergeMetadata(existing map[string]string, reserved []string, additions ...map[string]string) map[string]string {
+	merged := make(map[string]string, len(existing))
+	for key, value := range existing {
+		merged[key] = value
+	}
+	for _, addition := range additions {
+		for key, value := range addition {
+			merged[key] = value
+		}
+	}
+	for _, key := range reserved {
+		if value, ok := existing[key]; ok {
+			merged[key] = value
+		}
+	}
+
+	if len(merged) == 0 {
+		return existing
+	}
+	return merged
+}
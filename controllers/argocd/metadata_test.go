@@ -0,0 +1,169 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/common"
+	"github.com/argoproj-labs/argocd-operator/controllers/argoutil"
+)
+
+func withCommonMetadata(a *argoprojv1alpha1.ArgoCD) {
+	a.Spec.CommonLabels = map[string]string{
+		"example.com/team":     "platform",
+		common.ArgoCDKeyPartOf: "other",
+	}
+	a.Spec.CommonAnnotations = map[string]string{"example.com/owner": "team-a"}
+	a.Spec.CommonPodLabels = map[string]string{
+		"example.com/team":   "platform-pods",
+		common.ArgoCDKeyName: "other",
+		"example.com/tier":   "frontend",
+	}
+	a.Spec.CommonPodAnnotations = map[string]string{"example.com/scrape": "true"}
+}
+
+func TestSetCommonMetadata(t *testing.T) {
+	a := makeTestArgoCD(withCommonMetadata)
+
+	deploy := newDeploymentWithSuffix("server", "server", a)
+	deploy.Spec.Template.Labels = map[string]string{common.ArgoCDKeyName: nameWithSuffix("server", a)}
+	setCommonMetadata(a, deploy)
+
+	// The reserved labels are not overridden
+	assert.Equal(t, "platform", deploy.Labels["example.com/team"])
+	assert.Equal(t, common.ArgoCDAppName, deploy.Labels[common.ArgoCDKeyPartOf])
+	assert.Equal(t, "team-a", deploy.Annotations["example.com/owner"])
+
+	// The pod labels take precedence over the common labels, but not over the selector labels
+	assert.Equal(t, map[string]string{
+		common.ArgoCDKeyName: nameWithSuffix("server", a),
+		"example.com/team":   "platform-pods",
+		"example.com/tier":   "frontend",
+	}, deploy.Spec.Template.Labels)
+	assert.Equal(t, map[string]string{
+		"example.com/owner":  "team-a",
+		"example.com/scrape": "true",
+	}, deploy.Spec.Template.Annotations)
+
+	// Objects without common metadata are left untouched
+	b := makeTestArgoCD()
+	role := newRole(common.ArgoCDServerComponent, nil, b)
+	labels := role.Labels
+	setCommonMetadata(b, role)
+	assert.Equal(t, labels, role.Labels)
+	assert.Nil(t, role.Annotations)
+}
+
+func TestSyncCommonMetadata(t *testing.T) {
+	a := makeTestArgoCD(withCommonMetadata)
+
+	role := newRole(common.ArgoCDServerComponent, nil, a)
+	assert.True(t, syncCommonMetadata(a, role))
+	assert.Equal(t, "platform", role.Labels["example.com/team"])
+	assert.Equal(t, common.ArgoCDAppName, role.Labels[common.ArgoCDKeyPartOf])
+	assert.Equal(t, "team-a", role.Annotations["example.com/owner"])
+	assert.False(t, syncCommonMetadata(a, role))
+
+	// Keys that are no longer part of the common metadata are removed, the reserved labels are kept
+	a.Spec.CommonLabels = map[string]string{"example.com/cost-center": "42"}
+	a.Spec.CommonAnnotations = nil
+	assert.True(t, syncCommonMetadata(a, role))
+	assert.Equal(t, "42", role.Labels["example.com/cost-center"])
+	assert.NotContains(t, role.Labels, "example.com/team")
+	assert.Equal(t, common.ArgoCDAppName, role.Labels[common.ArgoCDKeyPartOf])
+	assert.NotContains(t, role.Annotations, "example.com/owner")
+
+	a.Spec.CommonLabels = nil
+	assert.True(t, syncCommonMetadata(a, role))
+	assert.NotContains(t, role.Labels, "example.com/cost-center")
+	assert.NotContains(t, role.Annotations, common.AnnotationCommonMetadata)
+
+	// The pod template labels that are set by the operator are never removed
+	b := makeTestArgoCD(withCommonMetadata)
+	deploy := newDeploymentWithSuffix("server", "server", b)
+	deploy.Spec.Template.Labels = map[string]string{common.ArgoCDKeyName: nameWithSuffix("server", b)}
+	syncCommonMetadata(b, deploy)
+	b.Spec.CommonLabels = nil
+	b.Spec.CommonPodLabels = nil
+	syncCommonMetadata(b, deploy)
+	assert.Equal(t, map[string]string{common.ArgoCDKeyName: nameWithSuffix("server", b)}, deploy.Spec.Template.Labels)
+}
+
+func TestReconcileArgoCD_reconcileSSHKnownHosts_commonMetadata(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD(withCommonMetadata)
+	r := makeTestReconciler(t, a)
+	assert.NoError(t, r.reconcileSSHKnownHosts(a))
+
+	a.Spec.CommonLabels = map[string]string{"example.com/cost-center": "42"}
+	assert.NoError(t, r.reconcileSSHKnownHosts(a))
+
+	cm := &corev1.ConfigMap{}
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, common.ArgoCDKnownHostsConfigMapName, cm))
+	assert.Equal(t, "42", cm.Labels["example.com/cost-center"])
+	assert.NotContains(t, cm.Labels, "example.com/team")
+	assert.Equal(t, "team-a", cm.Annotations["example.com/owner"])
+}
+
+func TestReconcileArgoCD_reconcileServerDeployment_commonMetadata(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD(withCommonMetadata)
+	r := makeTestReconciler(t, a)
+
+	assert.NoError(t, r.reconcileServerDeployment(a))
+
+	deploy := &appsv1.Deployment{}
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, nameWithSuffix("server", a), deploy))
+	assert.Equal(t, "platform", deploy.Labels["example.com/team"])
+	assert.Equal(t, "team-a", deploy.Annotations["example.com/owner"])
+	assert.Equal(t, "platform-pods", deploy.Spec.Template.Labels["example.com/team"])
+	assert.Equal(t, "true", deploy.Spec.Template.Annotations["example.com/scrape"])
+	assert.Equal(t, deploy.Spec.Selector.MatchLabels[common.ArgoCDKeyName], deploy.Spec.Template.Labels[common.ArgoCDKeyName])
+}
+
+func TestReconcileArgoCD_reconcileClusterRole_commonMetadata(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	os.Setenv("ARGOCD_CLUSTER_CONFIG_NAMESPACES", a.Namespace)
+	defer os.Unsetenv("ARGOCD_CLUSTER_CONFIG_NAMESPACES")
+
+	workloadIdentifier := common.ArgoCDApplicationControllerComponent
+	_, err := r.reconcileClusterRole(workloadIdentifier, policyRuleForApplicationController(), a)
+	assert.NoError(t, err)
+
+	// The common metadata is added to existing cluster roles
+	withCommonMetadata(a)
+	_, err = r.reconcileClusterRole(workloadIdentifier, policyRuleForApplicationController(), a)
+	assert.NoError(t, err)
+
+	clusterRole := &v1.ClusterRole{}
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: GenerateUniqueResourceName(workloadIdentifier, a)}, clusterRole))
+	assert.Equal(t, "platform", clusterRole.Labels["example.com/team"])
+	assert.Equal(t, common.ArgoCDAppName, clusterRole.Labels[common.ArgoCDKeyPartOf])
+	assert.Equal(t, "team-a", clusterRole.Annotations["example.com/owner"])
+}
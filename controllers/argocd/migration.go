@@ -0,0 +1,240 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/common"
+)
+
+// configMigration rewrites the generated content of an Argo CD ConfigMap into the format of a newer Argo CD version.
+type configMigration struct {
+	name string
+
+	// configMap is the name of the ConfigMap that the migration applies to.
+	configMap string
+
+	// version is the minor version of Argo CD that introduced the format, the migration is applied from this version.
+	version string
+
+	// message describes the migration in the status of the ArgoCD.
+	message string
+
+	// migrate rewrites the given data of the ConfigMap in place, it returns false if there was nothing to migrate.
+	migrate func(data map[string]string) bool
+}
+
+// configMigrations lists the migrations of the generated Argo CD configuration, in the order that they are applied.
+var configMigrations = []configMigration{
+	{
+		name:      "SplitResourceCustomizations",
+		configMap: common.ArgoCDConfigMapName,
+		version:   "v2.1",
+		message:   "The resource.customizations key was split into a key per resource and customization",
+		migrate:   splitResourceCustomizations,
+	},
+	{
+		name:      "GrantApplicationLogs",
+		configMap: common.ArgoCDRBACConfigMapName,
+		version:   "v2.4",
+		message:   "The logs permission was granted to every policy that allows getting applications",
+		migrate:   grantApplicationLogs,
+	},
+	{
+		name:      "RemoveConfigManagementPlugins",
+		configMap: common.ArgoCDConfigMapName,
+		version:   "v2.8",
+		message:   "The configManagementPlugins key was removed, the plugins must be configured as sidecar config management plugins",
+		migrate:   removeConfigManagementPlugins,
+	},
+}
+
+// getConfigArgoVersion will return the Argo CD version that the configuration of the given ArgoCD is generated for.
+// During a managed upgrade this is the previous version, so that the configuration is only migrated once all
+// components run the new version.
+func getConfigArgoVersion(cr *argoprojv1a1.ArgoCD) string {
+	if isUpgradeManaged(cr) && cr.Status.Version != "" {
+		return cr.Status.Version
+	}
+	return getDesiredArgoVersion(cr)
+}
+
+// applyConfigMigrations will apply the migrations for the Argo CD version of the given ArgoCD to the data of the given
+// ConfigMap, and record the applied migrations in the status. No migration is applied if the version is unknown.
+func applyConfigMigrations(cr *argoprojv1a1.ArgoCD, cm *corev1.ConfigMap) {
+	migrations := []argoprojv1a1.ArgoCDConfigMigration{}
+	for _, migration := range cr.Status.ConfigMigrations {
+		if migration.ConfigMap != cm.Name {
+			migrations = append(migrations, migration)
+		}
+	}
+
+	if v, ok := parseArgoVersion(getConfigArgoVersion(cr)); ok {
+		for _, migration := range configMigrations {
+			boundary, _ := parseArgoVersion(migration.version)
+			if migration.configMap != cm.Name || !isVersionReached(v, boundary) || !migration.migrate(cm.Data) {
+				continue
+			}
+			migrations = append(migrations, argoprojv1a1.ArgoCDConfigMigration{
+				Name:      migration.name,
+				ConfigMap: migration.configMap,
+				Version:   migration.version,
+				Message:   migration.message,
+			})
+		}
+	}
+
+	if len(migrations) == 0 {
+		migrations = nil
+	}
+	cr.Status.ConfigMigrations = migrations
+}
+
+// resourceOverride is a resource customization in the format of the resource.customizations key.
+type resourceOverride struct {
+	HealthLua         string           `yaml:"health.lua,omitempty"`
+	UseOpenLibs       bool             `yaml:"health.lua.useOpenLibs,omitempty"`
+	Actions           string           `yaml:"actions,omitempty"`
+	IgnoreDifferences string           `yaml:"ignoreDifferences,omitempty"`
+	KnownTypeFields   []knownTypeField `yaml:"knownTypeFields,omitempty"`
+}
+
+type knownTypeField struct {
+	Field string `yaml:"field"`
+	Type  string `yaml:"type"`
+}
+
+// resourceActions are the actions of a resource customization in the format of the actions key.
+type resourceActions struct {
+	DiscoveryLua string                     `yaml:"discovery.lua,omitempty"`
+	Definitions  []resourceActionDefinition `yaml:"definitions,omitempty"`
+}
+
+type resourceActionDefinition struct {
+	Name      string `yaml:"name"`
+	ActionLua string `yaml:"action.lua"`
+}
+
+// resourceIgnoreDifferences are the ignored differences of a resource customization in the format of the
+// ignoreDifferences key.
+type resourceIgnoreDifferences struct {
+	JSONPointers          []string `yaml:"jsonPointers,omitempty"`
+	JQPathExpressions     []string `yaml:"jqPathExpressions,omitempty"`
+	ManagedFieldsManagers []string `yaml:"managedFieldsManagers,omitempty"`
+}
+
+// splitResourceCustomizations will replace the resource.customizations key with a key per resource and customization,
+// e.g. resource.customizations.health.apps_Deployment. Customizations that cannot be parsed are left untouched.
+func splitResourceCustomizations(data map[string]string) bool {
+	if data[common.ArgoCDKeyResourceCustomizations] == "" {
+		return false
+	}
+
+	overrides := map[string]resourceOverride{}
+	if err := yaml.UnmarshalStrict([]byte(data[common.ArgoCDKeyResourceCustomizations]), &overrides); err != nil {
+		log.Info(fmt.Sprintf("not splitting resource customizations: %v", err))
+		return false
+	}
+
+	split := map[string]string{}
+	for key, override := range overrides {
+		groupKind := strings.Replace(key, "/", "_", 1)
+		if override.HealthLua != "" {
+			split[resourceCustomizationKey("health", groupKind)] = override.HealthLua
+		}
+		if override.UseOpenLibs {
+			split[resourceCustomizationKey("useOpenLibs", groupKind)] = "true"
+		}
+		if override.Actions != "" {
+			split[resourceCustomizationKey("actions", groupKind)] = override.Actions
+		}
+		if override.IgnoreDifferences != "" {
+			split[resourceCustomizationKey("ignoreDifferences", groupKind)] = override.IgnoreDifferences
+		}
+		if len(override.KnownTypeFields) > 0 {
+			fields, err := yaml.Marshal(override.KnownTypeFields)
+			if err != nil {
+				return false
+			}
+			split[resourceCustomizationKey("knownTypeFields", groupKind)] = string(fields)
+		}
+	}
+
+	delete(data, common.ArgoCDKeyResourceCustomizations)
+	for key, value := range split {
+		data[key] = value
+	}
+	return true
+}
+
+// resourceCustomizationKey returns the argocd-cm key for the given customization of the given resource.
+func resourceCustomizationKey(customization string, groupKind string) string {
+	return fmt.Sprintf("%s.%s.%s", common.ArgoCDKeyResourceCustomizations, customization, groupKind)
+}
+
+// grantApplicationLogs will add a policy that allows getting the logs of applications for every policy that allows
+// getting applications, so that the access to logs is retained when Argo CD enforces the logs permission.
+func grantApplicationLogs(data map[string]string) bool {
+	policy := data[common.ArgoCDKeyRBACPolicyCSV]
+	if policy == "" {
+		return false
+	}
+
+	existing := map[string]bool{}
+	grants := []string{}
+	for _, line := range strings.Split(policy, "\n") {
+		fields := strings.Split(line, ",")
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		if len(fields) != 6 || fields[0] != "p" {
+			continue
+		}
+		existing[strings.Join(fields, ", ")] = true
+		if fields[2] == "applications" && (fields[3] == "get" || fields[3] == "*") {
+			grants = append(grants, strings.Join([]string{"p", fields[1], "logs", "get", fields[4], fields[5]}, ", "))
+		}
+	}
+
+	added := []string{}
+	for _, grant := range grants {
+		if !existing[grant] {
+			existing[grant] = true
+			added = append(added, grant)
+		}
+	}
+	if len(added) == 0 {
+		return false
+	}
+
+	data[common.ArgoCDKeyRBACPolicyCSV] = strings.TrimRight(policy, "\n") + "\n" + strings.Join(added, "\n") + "\n"
+	return true
+}
+
+// removeConfigManagementPlugins will remove the configManagementPlugins key, which is no longer read by Argo CD.
+func removeConfigManagementPlugins(data map[string]string) bool {
+	plugins, ok := data[common.ArgoCDKeyConfigManagementPlugins]
+	if !ok {
+		return false
+	}
+	delete(data, common.ArgoCDKeyConfigManagementPlugins)
+	return plugins != ""
+}
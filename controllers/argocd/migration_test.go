@@ -0,0 +1,131 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/common"
+	"github.com/argoproj-labs/argocd-operator/controllers/argoutil"
+)
+
+const testResourceCustomizations = `apps/Deployment:
+  health.lua: |
+    hs = {}
+    return hs
+  health.lua.useOpenLibs: true
+  ignoreDifferences: |
+    jsonPointers:
+    - /spec/replicas
+  knownTypeFields:
+  - field: spec.template.spec
+    type: core/v1/PodSpec
+Service:
+  actions: |
+    discovery.lua: |
+      return {}
+`
+
+func TestSplitResourceCustomizations(t *testing.T) {
+	data := map[string]string{common.ArgoCDKeyResourceCustomizations: testResourceCustomizations}
+
+	assert.True(t, splitResourceCustomizations(data))
+	assert.Equal(t, map[string]string{
+		"resource.customizations.health.apps_Deployment":            "hs = {}\nreturn hs\n",
+		"resource.customizations.useOpenLibs.apps_Deployment":       "true",
+		"resource.customizations.ignoreDifferences.apps_Deployment": "jsonPointers:\n- /spec/replicas\n",
+		"resource.customizations.knownTypeFields.apps_Deployment":   "- field: spec.template.spec\n  type: core/v1/PodSpec\n",
+		"resource.customizations.actions.Service":                   "discovery.lua: |\n  return {}\n",
+	}, data)
+
+	// Customizations that cannot be parsed are left untouched
+	data = map[string]string{common.ArgoCDKeyResourceCustomizations: "testing: testing"}
+	assert.False(t, splitResourceCustomizations(data))
+	assert.Equal(t, "testing: testing", data[common.ArgoCDKeyResourceCustomizations])
+}
+
+func TestGrantApplicationLogs(t *testing.T) {
+	data := map[string]string{common.ArgoCDKeyRBACPolicyCSV: "p, role:dev, applications, get, dev/*, allow\n" +
+		"p, role:ops, applications, *, */*, allow\n" +
+		"p, role:ops, logs, get, */*, allow\n" +
+		"g, dev-team, role:dev\n"}
+
+	assert.True(t, grantApplicationLogs(data))
+	assert.Equal(t, "p, role:dev, applications, get, dev/*, allow\n"+
+		"p, role:ops, applications, *, */*, allow\n"+
+		"p, role:ops, logs, get, */*, allow\n"+
+		"g, dev-team, role:dev\n"+
+		"p, role:dev, logs, get, dev/*, allow\n", data[common.ArgoCDKeyRBACPolicyCSV])
+
+	// The migration is idempotent
+	assert.False(t, grantApplicationLogs(data))
+}
+
+func TestReconcileArgoCD_configMigrations(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	policy := "p, role:dev, applications, get, */*, allow"
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Version = "v2.0.5"
+		a.Spec.ResourceCustomizations = testResourceCustomizations
+		a.Spec.RBAC.Policy = &policy
+	})
+	r := makeTestReconciler(t, a)
+
+	// No migration is applied before the version that introduced the format
+	assert.NoError(t, r.reconcileArgoConfigMap(a))
+	cm := &corev1.ConfigMap{}
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, common.ArgoCDConfigMapName, cm))
+	assert.Equal(t, testResourceCustomizations, cm.Data[common.ArgoCDKeyResourceCustomizations])
+	assert.Nil(t, a.Status.ConfigMigrations)
+
+	a.Spec.Version = "v2.4.0"
+	assert.NoError(t, r.reconcileArgoConfigMap(a))
+	assert.NoError(t, r.reconcileRBAC(a))
+
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, common.ArgoCDConfigMapName, cm))
+	assert.NotContains(t, cm.Data, common.ArgoCDKeyResourceCustomizations)
+	assert.Equal(t, "hs = {}\nreturn hs\n", cm.Data["resource.customizations.health.apps_Deployment"])
+	assert.Contains(t, cm.Data, common.ArgoCDKeyConfigManagementPlugins)
+
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, common.ArgoCDRBACConfigMapName, cm))
+	assert.Equal(t, policy+"\np, role:dev, logs, get, */*, allow\n", cm.Data[common.ArgoCDKeyRBACPolicyCSV])
+
+	assert.Equal(t, []argoprojv1alpha1.ArgoCDConfigMigration{
+		{
+			Name:      "SplitResourceCustomizations",
+			ConfigMap: common.ArgoCDConfigMapName,
+			Version:   "v2.1",
+			Message:   "The resource.customizations key was split into a key per resource and customization",
+		},
+		{
+			Name:      "GrantApplicationLogs",
+			ConfigMap: common.ArgoCDRBACConfigMapName,
+			Version:   "v2.4",
+			Message:   "The logs permission was granted to every policy that allows getting applications",
+		},
+	}, a.Status.ConfigMigrations)
+
+	// During a managed upgrade the configuration is generated for the previous version
+	a.Spec.Upgrade = &argoprojv1alpha1.ArgoCDUpgradeSpec{Managed: true}
+	a.Status.Version = "v2.0.5"
+	assert.NoError(t, r.reconcileArgoConfigMap(a))
+	assert.NoError(t, r.reconcileRBAC(a))
+	assert.Nil(t, a.Status.ConfigMigrations)
+}
@@ -0,0 +1,221 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/common"
+	"github.com/argoproj-labs/argocd-operator/controllers/argoutil"
+)
+
+// networkPolicyTarget describes the pods of a component that are isolated by a NetworkPolicy.
+type networkPolicyTarget struct {
+	// suffix is the suffix of the name of the NetworkPolicy, which is the same as the name of the workload.
+	suffix string
+	// component is the value of the component label of the NetworkPolicy.
+	component string
+	// selector is the value of the name label of the pods.
+	selector string
+	// installed is true if the workload of the component is installed.
+	installed bool
+	// ingress are the flows that are allowed to reach the pods.
+	ingress []networkingv1.NetworkPolicyIngressRule
+}
+
+// getNetworkPolicyTargets returns the components of the given ArgoCD that are isolated by a NetworkPolicy, along with
+// the flows that each of them needs.
+func getNetworkPolicyTargets(cr *argoprojv1a1.ArgoCD) []networkPolicyTarget {
+	var metricsFrom, serverFrom []networkingv1.NetworkPolicyPeer
+	if cr.Spec.NetworkPolicy != nil {
+		metricsFrom = cr.Spec.NetworkPolicy.MetricsFrom
+		serverFrom = cr.Spec.NetworkPolicy.ServerFrom
+	}
+
+	server := podPeer(nameWithSuffix("server", cr))
+	controller := podPeer(nameWithSuffix("application-controller", cr))
+	repoServer := podPeer(nameWithSuffix("repo-server", cr))
+
+	// The repo server is also used by the ApplicationSet controller, when installed
+	repoServerFrom := []networkingv1.NetworkPolicyPeer{server, controller}
+	if cr.Spec.ApplicationSet != nil {
+		repoServerFrom = append(repoServerFrom, podPeer(nameWithSuffix("applicationset-controller", cr)))
+	}
+
+	redisFrom := []networkingv1.NetworkPolicyPeer{server, controller, repoServer}
+
+	return []networkPolicyTarget{
+		{
+			suffix:    "application-controller",
+			component: "application-controller",
+			selector:  nameWithSuffix("application-controller", cr),
+			installed: true,
+			ingress: []networkingv1.NetworkPolicyIngressRule{
+				ingressRule(metricsFrom, 8082),
+			},
+		},
+		{
+			suffix:    "dex-server",
+			component: "dex-server",
+			selector:  nameWithSuffix("dex-server", cr),
+			installed: !isDexDisabled(),
+			ingress: []networkingv1.NetworkPolicyIngressRule{
+				ingressRule([]networkingv1.NetworkPolicyPeer{server}, common.ArgoCDDefaultDexHTTPPort, common.ArgoCDDefaultDexGRPCPort),
+				ingressRule(metricsFrom, common.ArgoCDDefaultDexMetricsPort),
+			},
+		},
+		{
+			suffix:    "grafana",
+			component: "grafana",
+			selector:  nameWithSuffix("grafana", cr),
+			installed: cr.Spec.Grafana.Enabled,
+			ingress: []networkingv1.NetworkPolicyIngressRule{
+				ingressRule(serverFrom, 3000),
+			},
+		},
+		{
+			suffix:    "redis",
+			component: "redis",
+			selector:  nameWithSuffix("redis", cr),
+			installed: !cr.Spec.HA.Enabled,
+			ingress: []networkingv1.NetworkPolicyIngressRule{
+				ingressRule(redisFrom, common.ArgoCDDefaultRedisPort),
+			},
+		},
+		{
+			suffix:    "redis-ha-haproxy",
+			component: "redis",
+			selector:  nameWithSuffix("redis-ha-haproxy", cr),
+			installed: cr.Spec.HA.Enabled,
+			ingress: []networkingv1.NetworkPolicyIngressRule{
+				ingressRule(redisFrom, common.ArgoCDDefaultRedisPort),
+			},
+		},
+		{
+			suffix:    "redis-ha-server",
+			component: "redis",
+			selector:  nameWithSuffix("redis-ha", cr),
+			installed: cr.Spec.HA.Enabled,
+			ingress: []networkingv1.NetworkPolicyIngressRule{
+				ingressRule([]networkingv1.NetworkPolicyPeer{
+					podPeer(nameWithSuffix("redis-ha-haproxy", cr)),
+					podPeer(nameWithSuffix("redis-ha", cr)),
+				}, common.ArgoCDDefaultRedisPort, common.ArgoCDDefaultRedisSentinelPort),
+			},
+		},
+		{
+			suffix:    "repo-server",
+			component: "repo-server",
+			selector:  nameWithSuffix("repo-server", cr),
+			installed: true,
+			ingress: []networkingv1.NetworkPolicyIngressRule{
+				ingressRule(repoServerFrom, common.ArgoCDDefaultRepoServerPort),
+				ingressRule(metricsFrom, common.ArgoCDDefaultRepoMetricsPort),
+			},
+		},
+		{
+			suffix:    "server",
+			component: "server",
+			selector:  nameWithSuffix("server", cr),
+			installed: true,
+			ingress: []networkingv1.NetworkPolicyIngressRule{
+				ingressRule(serverFrom, 8080),
+				ingressRule(metricsFrom, 8083),
+			},
+		},
+	}
+}
+
+// podPeer returns a NetworkPolicyPeer that selects the pods with the given name in the namespace of the NetworkPolicy.
+func podPeer(name string) networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{
+		PodSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				common.ArgoCDKeyName: name,
+			},
+		},
+	}
+}
+
+// ingressRule returns a NetworkPolicyIngressRule that allows the given peers to reach the given TCP ports. All sources
+// are allowed if no peers are given.
+func ingressRule(from []networkingv1.NetworkPolicyPeer, ports ...int) networkingv1.NetworkPolicyIngressRule {
+	tcp := corev1.ProtocolTCP
+	rule := networkingv1.NetworkPolicyIngressRule{From: from}
+	for _, port := range ports {
+		p := intstr.FromInt(port)
+		rule.Ports = append(rule.Ports, networkingv1.NetworkPolicyPort{
+			Protocol: &tcp,
+			Port:     &p,
+		})
+	}
+	return rule
+}
+
+// newNetworkPolicyWithSuffix returns a new NetworkPolicy instance for the given ArgoCD using the given suffix.
+func newNetworkPolicyWithSuffix(suffix string, component string, cr *argoprojv1a1.ArgoCD) *networkingv1.NetworkPolicy {
+	name := nameWithSuffix(suffix, cr)
+
+	lbls := argoutil.LabelsForCluster(cr)
+	lbls[common.ArgoCDKeyName] = name
+	lbls[common.ArgoCDKeyComponent] = component
+
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cr.Namespace,
+			Labels:    lbls,
+		},
+	}
+}
+
+// reconcileNetworkPolicies will ensure that the NetworkPolicies of the installed components are present for the given
+// ArgoCD, and that the NetworkPolicies of the components that are not installed are removed.
+func (r *ReconcileArgoCD) reconcileNetworkPolicies(cr *argoprojv1a1.ArgoCD) error {
+	for _, target := range getNetworkPolicyTargets(cr) {
+		if err := r.reconcileNetworkPolicy(cr, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcileNetworkPolicy will ensure that the NetworkPolicy for the given target is present, if enabled.
+func (r *ReconcileArgoCD) reconcileNetworkPolicy(cr *argoprojv1a1.ArgoCD, target networkPolicyTarget) error {
+	np := newNetworkPolicyWithSuffix(target.suffix, target.component, cr)
+	if cr.Spec.NetworkPolicy == nil || !cr.Spec.NetworkPolicy.Enabled || !target.installed {
+		if argoutil.IsObjectFound(r.Client, cr.Namespace, np.Name, np) {
+			// NetworkPolicy exists but is not needed anymore, delete the NetworkPolicy
+			return r.Client.Delete(context.TODO(), np)
+		}
+		return nil // NetworkPolicy not enabled, move along...
+	}
+
+	np.Spec.PodSelector = metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			common.ArgoCDKeyName: target.selector,
+		},
+	}
+	np.Spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+	np.Spec.Ingress = target.ingress
+
+	return r.applyResource(cr, np)
+}
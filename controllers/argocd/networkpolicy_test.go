@@ -0,0 +1,92 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/common"
+	"github.com/argoproj-labs/argocd-operator/controllers/argoutil"
+)
+
+func TestReconcileArgoCD_reconcileNetworkPolicies(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	// No NetworkPolicies are created unless enabled
+	assert.NoError(t, r.reconcileNetworkPolicies(a))
+	nps := &networkingv1.NetworkPolicyList{}
+	assert.NoError(t, r.Client.List(context.TODO(), nps))
+	assert.Empty(t, nps.Items)
+
+	prometheus := networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"kubernetes.io/metadata.name": "monitoring"},
+		},
+	}
+	a.Spec.NetworkPolicy = &argoprojv1alpha1.ArgoCDNetworkPolicySpec{
+		Enabled:     true,
+		MetricsFrom: []networkingv1.NetworkPolicyPeer{prometheus},
+	}
+	assert.NoError(t, r.reconcileNetworkPolicies(a))
+
+	np := &networkingv1.NetworkPolicy{}
+	for _, suffix := range []string{"application-controller", "dex-server", "redis", "repo-server", "server"} {
+		assert.True(t, argoutil.IsObjectFound(r.Client, a.Namespace, nameWithSuffix(suffix, a), np), suffix)
+	}
+	for _, suffix := range []string{"grafana", "redis-ha-haproxy", "redis-ha-server"} {
+		assert.False(t, argoutil.IsObjectFound(r.Client, a.Namespace, nameWithSuffix(suffix, a), np), suffix)
+	}
+
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, nameWithSuffix("repo-server", a), np))
+	assert.Equal(t, nameWithSuffix("repo-server", a), np.Spec.PodSelector.MatchLabels[common.ArgoCDKeyName])
+	assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, np.Spec.PolicyTypes)
+	assert.Equal(t, []networkingv1.NetworkPolicyIngressRule{
+		ingressRule([]networkingv1.NetworkPolicyPeer{
+			podPeer(nameWithSuffix("server", a)),
+			podPeer(nameWithSuffix("application-controller", a)),
+		}, common.ArgoCDDefaultRepoServerPort),
+		ingressRule([]networkingv1.NetworkPolicyPeer{prometheus}, common.ArgoCDDefaultRepoMetricsPort),
+	}, np.Spec.Ingress)
+
+	// All sources are allowed to reach the server when no sources are given
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, nameWithSuffix("server", a), np))
+	assert.Empty(t, np.Spec.Ingress[0].From)
+
+	// The NetworkPolicies follow the components that are installed
+	a.Spec.HA.Enabled = true
+	a.Spec.ApplicationSet = &argoprojv1alpha1.ArgoCDApplicationSet{}
+	assert.NoError(t, r.reconcileNetworkPolicies(a))
+	assert.False(t, argoutil.IsObjectFound(r.Client, a.Namespace, nameWithSuffix("redis", a), np))
+	assert.True(t, argoutil.IsObjectFound(r.Client, a.Namespace, nameWithSuffix("redis-ha-haproxy", a), np))
+	assert.True(t, argoutil.IsObjectFound(r.Client, a.Namespace, nameWithSuffix("redis-ha-server", a), np))
+
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, nameWithSuffix("repo-server", a), np))
+	assert.Contains(t, np.Spec.Ingress[0].From, podPeer(nameWithSuffix("applicationset-controller", a)))
+
+	// The NetworkPolicies are removed when disabled
+	a.Spec.NetworkPolicy.Enabled = false
+	assert.NoError(t, r.reconcileNetworkPolicies(a))
+	assert.NoError(t, r.Client.List(context.TODO(), nps))
+	assert.Empty(t, nps.Items)
+}
@@ -0,0 +1,50 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+)
+
+// applyPodTemplateOverride will strategically merge the given override into the given pod template, the same way
+// kubectl patch --type=strategic does. Containers, init containers, volumes and environment variables are merged by
+// their name, so the override only needs to list the fields that differ from the generated pod template.
+func applyPodTemplateOverride(template *corev1.PodTemplateSpec, override *runtime.RawExtension) error {
+	if override == nil || len(override.Raw) == 0 {
+		return nil
+	}
+
+	original, err := json.Marshal(template)
+	if err != nil {
+		return err
+	}
+
+	patched, err := strategicpatch.StrategicMergePatch(original, override.Raw, corev1.PodTemplateSpec{})
+	if err != nil {
+		return fmt.Errorf("failed to apply the pod template override: %w", err)
+	}
+
+	result := corev1.PodTemplateSpec{}
+	if err := json.Unmarshal(patched, &result); err != nil {
+		return fmt.Errorf("failed to decode the overridden pod template: %w", err)
+	}
+	*template = result
+	return nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/controllers/argoutil"
+)
+
+func TestApplyPodTemplateOverride(t *testing.T) {
+	template := corev1.PodTemplateSpec{}
+	template.Labels = map[string]string{"app.kubernetes.io/name": "argocd-server"}
+	template.Spec.Containers = []corev1.Container{{
+		Name:  "argocd-server",
+		Image: "argoproj/argocd:v2.2.2",
+		Env:   []corev1.EnvVar{{Name: "HTTP_PROXY", Value: "example.com:8888"}},
+	}}
+
+	override := &runtime.RawExtension{Raw: []byte(`{
+		"metadata": {"annotations": {"example.com/owner": "team-a"}},
+		"spec": {
+			"containers": [
+				{"name": "argocd-server", "env": [{"name": "FOO", "value": "bar"}]},
+				{"name": "sidecar", "image": "busybox"}
+			],
+			"nodeSelector": {"kubernetes.io/os": "linux"}
+		}
+	}`)}
+
+	assert.NoError(t, applyPodTemplateOverride(&template, override))
+	assert.Equal(t, "argocd-server", template.Labels["app.kubernetes.io/name"])
+	assert.Equal(t, "team-a", template.Annotations["example.com/owner"])
+	assert.Equal(t, map[string]string{"kubernetes.io/os": "linux"}, template.Spec.NodeSelector)
+	assert.Len(t, template.Spec.Containers, 2)
+
+	// Containers and their environment variables are merged by name
+	server := template.Spec.Containers[0]
+	assert.Equal(t, "argocd-server", server.Name)
+	assert.Equal(t, "argoproj/argocd:v2.2.2", server.Image)
+	assert.ElementsMatch(t, []corev1.EnvVar{
+		{Name: "HTTP_PROXY", Value: "example.com:8888"},
+		{Name: "FOO", Value: "bar"},
+	}, server.Env)
+	assert.Equal(t, "sidecar", template.Spec.Containers[1].Name)
+
+	// Fields can be removed with the $patch: delete directive
+	override = &runtime.RawExtension{Raw: []byte(`{"spec": {"containers": [{"name": "sidecar", "$patch": "delete"}]}}`)}
+	assert.NoError(t, applyPodTemplateOverride(&template, override))
+	assert.Len(t, template.Spec.Containers, 1)
+
+	assert.NoError(t, applyPodTemplateOverride(&template, nil))
+	assert.Len(t, template.Spec.Containers, 1)
+
+	override = &runtime.RawExtension{Raw: []byte(`{"spec": {"containers": "invalid"}}`)}
+	assert.Error(t, applyPodTemplateOverride(&template, override))
+}
+
+func TestReconcileArgoCD_reconcileServerDeployment_podTemplateOverride(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Server.PodTemplateOverride = &runtime.RawExtension{Raw: []byte(`{
+			"spec": {
+				"containers": [{"name": "argocd-server", "env": [{"name": "FOO", "value": "bar"}]}],
+				"priorityClassName": "system-cluster-critical"
+			}
+		}`)}
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NoError(t, r.reconcileServerDeployment(a))
+
+	deploy := &appsv1.Deployment{}
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, nameWithSuffix("server", a), deploy))
+	assert.Equal(t, "system-cluster-critical", deploy.Spec.Template.Spec.PriorityClassName)
+	assert.Len(t, deploy.Spec.Template.Spec.Containers, 1)
+	assert.Equal(t, getArgoContainerImage(a), deploy.Spec.Template.Spec.Containers[0].Image)
+	assert.Contains(t, deploy.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "FOO", Value: "bar"})
+}
+
+func TestReconcileArgoCD_newKeycloakInstance_podTemplateOverride(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.SSO = &argoprojv1alpha1.ArgoCDSSOSpec{
+			Provider:            argoprojv1alpha1.SSOProviderTypeKeycloak,
+			PodTemplateOverride: &runtime.RawExtension{Raw: []byte(`{"spec": {"priorityClassName": "system-cluster-critical"}}`)},
+		}
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NoError(t, r.newKeycloakInstance(a))
+
+	deploy := &appsv1.Deployment{}
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, defaultKeycloakIdentifier, deploy))
+	assert.Equal(t, "system-cluster-critical", deploy.Spec.Template.Spec.PriorityClassName)
+	assert.Equal(t, getKeycloakContainerImage(a), deploy.Spec.Template.Spec.Containers[0].Image)
+}
@@ -0,0 +1,104 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+)
+
+// applyPatches will apply the patches of the given ArgoCD that target the given generated object, in the order they
+// are listed.
+func (r *ReconcileArgoCD) applyPatches(cr *argoprojv1a1.ArgoCD, obj client.Object) error {
+	if len(cr.Spec.Patches) == 0 {
+		return nil
+	}
+
+	gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+	if err != nil {
+		return err
+	}
+
+	for i, patch := range cr.Spec.Patches {
+		if !isPatchTarget(cr, patch.Target, gvk.Kind, obj.GetName()) {
+			continue
+		}
+		if err := applyPatch(obj, patch); err != nil {
+			return fmt.Errorf("failed to apply patch %d to %s %s: %w", i, gvk.Kind, obj.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// isPatchTarget returns true if the given patch target selects the generated object with the given kind and name.
+func isPatchTarget(cr *argoprojv1a1.ArgoCD, target argoprojv1a1.ArgoCDPatchTarget, kind string, name string) bool {
+	if target.Kind != kind {
+		return false
+	}
+	return target.Name == "" || target.Name == name || nameWithSuffix(target.Name, cr) == name
+}
+
+// applyPatch will apply the given patch to the given object.
+func applyPatch(obj client.Object, patch argoprojv1a1.ArgoCDPatch) error {
+	original, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.ToJSON([]byte(patch.Patch))
+	if err != nil {
+		return fmt.Errorf("invalid patch: %w", err)
+	}
+
+	var patched []byte
+	switch patch.Type {
+	case argoprojv1a1.ArgoCDPatchTypeJSON:
+		p, err := jsonpatch.DecodePatch(data)
+		if err != nil {
+			return fmt.Errorf("invalid patch: %w", err)
+		}
+		if patched, err = p.Apply(original); err != nil {
+			return err
+		}
+	case argoprojv1a1.ArgoCDPatchTypeStrategic, "":
+		if patched, err = strategicpatch.StrategicMergePatch(original, data, obj); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown patch type %q", patch.Type)
+	}
+
+	name, namespace := obj.GetName(), obj.GetNamespace()
+
+	// Reset the object first, otherwise fields that were removed by the patch would be retained
+	reflect.ValueOf(obj).Elem().Set(reflect.Zero(reflect.TypeOf(obj).Elem()))
+	if err := json.Unmarshal(patched, obj); err != nil {
+		return err
+	}
+
+	if obj.GetName() != name || obj.GetNamespace() != namespace {
+		return fmt.Errorf("patches must not change the name or namespace")
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	v1 "k8s.io/api/rbac/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/common"
+	"github.com/argoproj-labs/argocd-operator/controllers/argoutil"
+)
+
+func TestIsPatchTarget(t *testing.T) {
+	a := makeTestArgoCD()
+
+	tests := []struct {
+		name   string
+		target argoprojv1alpha1.ArgoCDPatchTarget
+		kind   string
+		object string
+		want   bool
+	}{
+		{"suffix", argoprojv1alpha1.ArgoCDPatchTarget{Kind: "Service", Name: "server"}, "Service", "argocd-server", true},
+		{"full name", argoprojv1alpha1.ArgoCDPatchTarget{Kind: "ConfigMap", Name: "argocd-cm"}, "ConfigMap", "argocd-cm", true},
+		{"all of kind", argoprojv1alpha1.ArgoCDPatchTarget{Kind: "Service"}, "Service", "argocd-repo-server", true},
+		{"other kind", argoprojv1alpha1.ArgoCDPatchTarget{Kind: "Ingress", Name: "server"}, "Service", "argocd-server", false},
+		{"other suffix", argoprojv1alpha1.ArgoCDPatchTarget{Kind: "Service", Name: "server"}, "Service", "argocd-repo-server", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, isPatchTarget(a, test.target, test.kind, test.object))
+		})
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	a := makeTestArgoCD()
+	svc := newServiceWithSuffix("server", "server", a)
+	svc.Spec.Ports = []corev1.ServicePort{{Name: "http", Port: 80}}
+
+	// Strategic merge patches merge the ports by their port number
+	assert.NoError(t, applyPatch(svc, argoprojv1alpha1.ArgoCDPatch{
+		Patch: "metadata:\n  annotations:\n    example.com/team: platform\nspec:\n  ports:\n  - name: metrics\n    port: 8083\n",
+	}))
+	assert.Equal(t, "platform", svc.Annotations["example.com/team"])
+	assert.Equal(t, []corev1.ServicePort{{Name: "http", Port: 80}, {Name: "metrics", Port: 8083}}, svc.Spec.Ports)
+
+	assert.NoError(t, applyPatch(svc, argoprojv1alpha1.ArgoCDPatch{
+		Type:  argoprojv1alpha1.ArgoCDPatchTypeJSON,
+		Patch: `[{"op": "remove", "path": "/spec/ports/0"}, {"op": "add", "path": "/spec/type", "value": "NodePort"}]`,
+	}))
+	assert.Equal(t, []corev1.ServicePort{{Name: "metrics", Port: 8083}}, svc.Spec.Ports)
+	assert.Equal(t, corev1.ServiceTypeNodePort, svc.Spec.Type)
+
+	assert.Error(t, applyPatch(svc, argoprojv1alpha1.ArgoCDPatch{
+		Type:  argoprojv1alpha1.ArgoCDPatchTypeJSON,
+		Patch: `[{"op": "replace", "path": "/metadata/name", "value": "other"}]`,
+	}))
+	assert.Error(t, applyPatch(svc, argoprojv1alpha1.ArgoCDPatch{Type: "merge", Patch: "{}"}))
+}
+
+func TestReconcileArgoCD_reconcileServerService_patches(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Patches = []argoprojv1alpha1.ArgoCDPatch{{
+			Target: argoprojv1alpha1.ArgoCDPatchTarget{Kind: "Service", Name: "server"},
+			Patch:  `{"spec": {"ports": [{"name": "grpc", "port": 8443, "protocol": "TCP"}]}}`,
+		}, {
+			Target: argoprojv1alpha1.ArgoCDPatchTarget{Kind: "Service", Name: "repo-server"},
+			Patch:  `{"metadata": {"annotations": {"example.com/team": "platform"}}}`,
+		}}
+	})
+	r := makeTestReconciler(t, a)
+
+	svc := &corev1.Service{}
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, r.reconcileServerService(a))
+		assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, nameWithSuffix("server", a), svc))
+		assert.Len(t, svc.Spec.Ports, 3)
+		assert.Equal(t, "grpc", svc.Spec.Ports[2].Name)
+		assert.Empty(t, svc.Annotations["example.com/team"])
+	}
+
+	a.Spec.Patches[0].Patch = "invalid: ["
+	assert.Error(t, r.reconcileServerService(a))
+}
+
+func TestReconcileArgoCD_reconcileArgoServerIngress_patches(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Server.Ingress.Enabled = true
+		a.Spec.Patches = []argoprojv1alpha1.ArgoCDPatch{{
+			Target: argoprojv1alpha1.ArgoCDPatchTarget{Kind: "Ingress", Name: "server"},
+			Type:   argoprojv1alpha1.ArgoCDPatchTypeJSON,
+			Patch:  `[{"op": "add", "path": "/metadata/annotations/example.com~1team", "value": "platform"}]`,
+		}}
+	})
+	r := makeTestReconciler(t, a)
+	assert.NoError(t, r.reconcileArgoServerIngress(a))
+
+	ingress := &networkingv1.Ingress{}
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, nameWithSuffix("server", a), ingress))
+	assert.Equal(t, "platform", ingress.Annotations["example.com/team"])
+
+	// The patch is applied again when the Ingress has been modified
+	delete(ingress.Annotations, "example.com/team")
+	assert.NoError(t, r.Client.Update(context.TODO(), ingress))
+	assert.NoError(t, r.reconcileArgoServerIngress(a))
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, nameWithSuffix("server", a), ingress))
+	assert.Equal(t, "platform", ingress.Annotations["example.com/team"])
+}
+
+func TestReconcileArgoCD_reconcileRole_patches(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Patches = []argoprojv1alpha1.ArgoCDPatch{{
+			Target: argoprojv1alpha1.ArgoCDPatchTarget{Kind: "Role", Name: common.ArgoCDServerComponent},
+			Type:   argoprojv1alpha1.ArgoCDPatchTypeJSON,
+			Patch:  `[{"op": "add", "path": "/rules/-", "value": {"apiGroups": ["example.com"], "resources": ["widgets"], "verbs": ["get"]}}]`,
+		}}
+	})
+	r := makeTestReconciler(t, a)
+	assert.NoError(t, createNamespace(r, a.Namespace, ""))
+
+	rules := policyRuleForServer()
+	for i := 0; i < 2; i++ {
+		_, err := r.reconcileRole(common.ArgoCDServerComponent, rules, a)
+		assert.NoError(t, err)
+	}
+
+	role := &v1.Role{}
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, nameWithSuffix(common.ArgoCDServerComponent, a), role))
+	assert.Len(t, role.Rules, len(rules)+1)
+	assert.Equal(t, []string{"widgets"}, role.Rules[len(rules)].Resources)
+}
+
+func TestReconcileArgoCD_reconcileRole_patchesExisting(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+	assert.NoError(t, createNamespace(r, a.Namespace, ""))
+
+	// The Role and RoleBinding exist before the patches are added
+	rules := policyRuleForServer()
+	_, err := r.reconcileRole(common.ArgoCDServerComponent, rules, a)
+	assert.NoError(t, err)
+	assert.NoError(t, r.reconcileRoleBinding(common.ArgoCDServerComponent, rules, a))
+
+	a.Spec.Patches = []argoprojv1alpha1.ArgoCDPatch{
+		{
+			Target: argoprojv1alpha1.ArgoCDPatchTarget{Kind: "Role", Name: common.ArgoCDServerComponent},
+			Patch:  `{"metadata": {"annotations": {"example.com/owner": "team-a"}}}`,
+		},
+		{
+			Target: argoprojv1alpha1.ArgoCDPatchTarget{Kind: "Role", Name: common.ArgoCDServerComponent},
+			Type:   argoprojv1alpha1.ArgoCDPatchTypeJSON,
+			Patch:  `[{"op": "add", "path": "/rules/-", "value": {"apiGroups": ["example.com"], "resources": ["widgets"], "verbs": ["get"]}}]`,
+		},
+		{
+			Target: argoprojv1alpha1.ArgoCDPatchTarget{Kind: "RoleBinding", Name: common.ArgoCDServerComponent},
+			Patch:  `{"metadata": {"labels": {"example.com/team": "platform"}}}`,
+		},
+	}
+	for i := 0; i < 2; i++ {
+		_, err = r.reconcileRole(common.ArgoCDServerComponent, rules, a)
+		assert.NoError(t, err)
+		assert.NoError(t, r.reconcileRoleBinding(common.ArgoCDServerComponent, rules, a))
+	}
+
+	role := &v1.Role{}
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, nameWithSuffix(common.ArgoCDServerComponent, a), role))
+	assert.Equal(t, "team-a", role.Annotations["example.com/owner"])
+	assert.Len(t, role.Rules, len(rules)+1)
+	assert.Equal(t, []string{"widgets"}, role.Rules[len(rules)].Resources)
+
+	roleBinding := &v1.RoleBinding{}
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, nameWithSuffix(common.ArgoCDServerComponent, a), roleBinding))
+	assert.Equal(t, "platform", roleBinding.Labels["example.com/team"])
+	assert.Equal(t, nameWithSuffix(common.ArgoCDServerComponent, a), roleBinding.RoleRef.Name)
+}
@@ -0,0 +1,236 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/common"
+	"github.com/argoproj-labs/argocd-operator/controllers/argoutil"
+)
+
+// policyV1APIFound is true if the policy/v1 API is served, which is the case since Kubernetes 1.21. It is assumed until
+// the cluster has been inspected.
+var policyV1APIFound = true
+
+// IsPolicyV1APIAvailable returns true if the policy/v1 API is present, otherwise PodDisruptionBudgets are managed
+// using the policy/v1beta1 API.
+func IsPolicyV1APIAvailable() bool {
+	return policyV1APIFound
+}
+
+// verifyPolicyV1API will verify that the policy/v1 API is present.
+func verifyPolicyV1API() error {
+	found, err := argoutil.VerifyAPI(policyv1.GroupName, policyv1.SchemeGroupVersion.Version)
+	if err != nil {
+		return err
+	}
+	policyV1APIFound = found
+	return nil
+}
+
+// podDisruptionBudgetTarget describes the pods of a component that are protected by a PodDisruptionBudget.
+type podDisruptionBudgetTarget struct {
+	// suffix is the suffix of the name of the PodDisruptionBudget, which is the same as the name of the workload.
+	suffix string
+	// component is the value of the component label of the PodDisruptionBudget.
+	component string
+	// selector is the value of the name label of the pods.
+	selector string
+	// installed is true if the workload of the component is installed.
+	installed bool
+	// replicas is the number of replicas of the workload.
+	replicas int32
+	spec     *argoprojv1a1.ArgoCDPodDisruptionBudgetSpec
+}
+
+// enabled returns true if the PodDisruptionBudget should be present for the given ArgoCD.
+func (t podDisruptionBudgetTarget) enabled(cr *argoprojv1a1.ArgoCD) bool {
+	if !t.installed {
+		return false
+	}
+	if t.spec != nil && t.spec.Enabled != nil {
+		return *t.spec.Enabled
+	}
+	return cr.Spec.HA.Enabled || t.replicas > 1
+}
+
+// getPodDisruptionBudgetTargets returns the components of the given ArgoCD that can have a PodDisruptionBudget.
+func getPodDisruptionBudgetTargets(cr *argoprojv1a1.ArgoCD) []podDisruptionBudgetTarget {
+	controllerReplicas := int32(common.ArgocdApplicationControllerDefaultReplicas)
+	if cr.Spec.Controller.Sharding.Enabled && cr.Spec.Controller.Sharding.Replicas != 0 {
+		controllerReplicas = cr.Spec.Controller.Sharding.Replicas
+	}
+
+	repoReplicas := int32(1)
+	if replicas := getArgoCDRepoServerReplicas(cr); replicas != nil {
+		repoReplicas = *replicas
+	}
+
+	// The number of server replicas is managed by the HorizontalPodAutoscaler when autoscaling is enabled
+	serverReplicas := int32(1)
+	if cr.Spec.Server.Autoscale.Enabled {
+		serverReplicas = 2
+	} else if replicas := getArgoCDServerReplicas(cr); replicas != nil {
+		serverReplicas = *replicas
+	}
+
+	var applicationSet *argoprojv1a1.ArgoCDPodDisruptionBudgetSpec
+	if cr.Spec.ApplicationSet != nil {
+		applicationSet = cr.Spec.ApplicationSet.PodDisruptionBudget
+	}
+
+	return []podDisruptionBudgetTarget{
+		{
+			suffix:    "application-controller",
+			component: "application-controller",
+			selector:  nameWithSuffix("application-controller", cr),
+			installed: true,
+			replicas:  controllerReplicas,
+			spec:      cr.Spec.Controller.PodDisruptionBudget,
+		},
+		{
+			suffix:    "applicationset-controller",
+			component: "controller",
+			selector:  nameWithSuffix("applicationset-controller", cr),
+			installed: cr.Spec.ApplicationSet != nil,
+			replicas:  1,
+			spec:      applicationSet,
+		},
+		{
+			suffix:    "dex-server",
+			component: "dex-server",
+			selector:  nameWithSuffix("dex-server", cr),
+			installed: !isDexDisabled(),
+			replicas:  1,
+			spec:      cr.Spec.Dex.PodDisruptionBudget,
+		},
+		{
+			suffix:    "redis-ha-haproxy",
+			component: "redis",
+			selector:  nameWithSuffix("redis-ha-haproxy", cr),
+			installed: cr.Spec.HA.Enabled,
+			replicas:  1,
+			spec:      cr.Spec.HA.PodDisruptionBudget,
+		},
+		{
+			suffix:    "redis-ha-server",
+			component: "redis",
+			selector:  nameWithSuffix("redis-ha", cr),
+			installed: cr.Spec.HA.Enabled,
+			replicas:  *getRedisHAReplicas(cr),
+			spec:      cr.Spec.Redis.PodDisruptionBudget,
+		},
+		{
+			suffix:    "repo-server",
+			component: "repo-server",
+			selector:  nameWithSuffix("repo-server", cr),
+			installed: true,
+			replicas:  repoReplicas,
+			spec:      cr.Spec.Repo.PodDisruptionBudget,
+		},
+		{
+			suffix:    "server",
+			component: "server",
+			selector:  nameWithSuffix("server", cr),
+			installed: true,
+			replicas:  serverReplicas,
+			spec:      cr.Spec.Server.PodDisruptionBudget,
+		},
+	}
+}
+
+// newPodDisruptionBudgetWithSuffix returns a new PodDisruptionBudget instance for the given ArgoCD using the given
+// suffix.
+func newPodDisruptionBudgetWithSuffix(suffix string, component string, cr *argoprojv1a1.ArgoCD) *policyv1.PodDisruptionBudget {
+	name := nameWithSuffix(suffix, cr)
+
+	lbls := argoutil.LabelsForCluster(cr)
+	lbls[common.ArgoCDKeyName] = name
+	lbls[common.ArgoCDKeyComponent] = component
+
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cr.Namespace,
+			Labels:    lbls,
+		},
+	}
+}
+
+// reconcilePodDisruptionBudgets will ensure that the PodDisruptionBudgets of the components are present for the given
+// ArgoCD, and that the PodDisruptionBudgets of the components that do not need one are removed.
+func (r *ReconcileArgoCD) reconcilePodDisruptionBudgets(cr *argoprojv1a1.ArgoCD) error {
+	for _, target := range getPodDisruptionBudgetTargets(cr) {
+		if err := r.reconcilePodDisruptionBudget(cr, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcilePodDisruptionBudget will ensure that the PodDisruptionBudget for the given target is present, if enabled.
+func (r *ReconcileArgoCD) reconcilePodDisruptionBudget(cr *argoprojv1a1.ArgoCD, target podDisruptionBudgetTarget) error {
+	pdb := newPodDisruptionBudgetWithSuffix(target.suffix, target.component, cr)
+	if !target.enabled(cr) {
+		existing := podDisruptionBudgetForCluster(pdb)
+		if argoutil.IsObjectFound(r.Client, cr.Namespace, existing.GetName(), existing) {
+			// PodDisruptionBudget exists but is not needed anymore, delete the PodDisruptionBudget
+			return r.Client.Delete(context.TODO(), existing)
+		}
+		return nil // PodDisruptionBudget not enabled, move along...
+	}
+
+	pdb.Spec.Selector = &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			common.ArgoCDKeyName: target.selector,
+		},
+	}
+
+	// minAvailable and maxUnavailable are mutually exclusive, which is enforced by the admission webhook
+	if spec := target.spec; spec != nil && spec.MinAvailable != nil {
+		pdb.Spec.MinAvailable = spec.MinAvailable
+	} else if spec != nil && spec.MaxUnavailable != nil {
+		pdb.Spec.MaxUnavailable = spec.MaxUnavailable
+	} else {
+		maxUnavailable := intstr.FromInt(1)
+		pdb.Spec.MaxUnavailable = &maxUnavailable
+	}
+
+	return r.applyResource(cr, podDisruptionBudgetForCluster(pdb))
+}
+
+// podDisruptionBudgetForCluster returns the given PodDisruptionBudget in the policy API version that is served by the
+// cluster.
+func podDisruptionBudgetForCluster(pdb *policyv1.PodDisruptionBudget) client.Object {
+	if IsPolicyV1APIAvailable() {
+		return pdb
+	}
+	return &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: pdb.ObjectMeta,
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			MinAvailable:   pdb.Spec.MinAvailable,
+			Selector:       pdb.Spec.Selector,
+			MaxUnavailable: pdb.Spec.MaxUnavailable,
+		},
+	}
+}
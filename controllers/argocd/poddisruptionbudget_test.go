@@ -0,0 +1,107 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/common"
+	"github.com/argoproj-labs/argocd-operator/controllers/argoutil"
+)
+
+func TestReconcileArgoCD_reconcilePodDisruptionBudgets(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	// No PodDisruptionBudgets are needed for a single replica of every component
+	assert.NoError(t, r.reconcilePodDisruptionBudgets(a))
+	pdbs := &policyv1.PodDisruptionBudgetList{}
+	assert.NoError(t, r.Client.List(context.TODO(), pdbs))
+	assert.Empty(t, pdbs.Items)
+
+	replicas := int32(3)
+	a.Spec.Repo.Replicas = &replicas
+	assert.NoError(t, r.reconcilePodDisruptionBudgets(a))
+
+	pdb := &policyv1.PodDisruptionBudget{}
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, nameWithSuffix("repo-server", a), pdb))
+	assert.Equal(t, nameWithSuffix("repo-server", a), pdb.Spec.Selector.MatchLabels[common.ArgoCDKeyName])
+	maxUnavailable := intstr.FromInt(1)
+	assert.Equal(t, &maxUnavailable, pdb.Spec.MaxUnavailable)
+	assert.Nil(t, pdb.Spec.MinAvailable)
+
+	// The PodDisruptionBudget is removed when it is not needed anymore
+	a.Spec.Repo.Replicas = nil
+	assert.NoError(t, r.reconcilePodDisruptionBudgets(a))
+	assert.False(t, argoutil.IsObjectFound(r.Client, a.Namespace, nameWithSuffix("repo-server", a), pdb))
+}
+
+func TestReconcileArgoCD_reconcilePodDisruptionBudgets_HA(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	disabled := false
+	minAvailable := intstr.FromString("50%")
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.HA.Enabled = true
+		a.Spec.Server.PodDisruptionBudget = &argoprojv1alpha1.ArgoCDPodDisruptionBudgetSpec{MinAvailable: &minAvailable}
+		a.Spec.Dex.PodDisruptionBudget = &argoprojv1alpha1.ArgoCDPodDisruptionBudgetSpec{Enabled: &disabled}
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NoError(t, r.reconcilePodDisruptionBudgets(a))
+
+	pdb := &policyv1.PodDisruptionBudget{}
+	for _, suffix := range []string{"application-controller", "redis-ha-haproxy", "redis-ha-server", "repo-server"} {
+		assert.True(t, argoutil.IsObjectFound(r.Client, a.Namespace, nameWithSuffix(suffix, a), pdb), suffix)
+	}
+	assert.False(t, argoutil.IsObjectFound(r.Client, a.Namespace, nameWithSuffix("dex-server", a), pdb))
+	assert.False(t, argoutil.IsObjectFound(r.Client, a.Namespace, nameWithSuffix("applicationset-controller", a), pdb))
+
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, nameWithSuffix("redis-ha-server", a), pdb))
+	assert.Equal(t, nameWithSuffix("redis-ha", a), pdb.Spec.Selector.MatchLabels[common.ArgoCDKeyName])
+
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, nameWithSuffix("server", a), pdb))
+	assert.Equal(t, &minAvailable, pdb.Spec.MinAvailable)
+	assert.Nil(t, pdb.Spec.MaxUnavailable)
+}
+
+func TestReconcileArgoCD_reconcilePodDisruptionBudgets_policyV1beta1(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.HA.Enabled = true
+	})
+	r := makeTestReconciler(t, a)
+
+	policyV1APIFound = false
+	defer func() { policyV1APIFound = true }()
+
+	assert.NoError(t, r.reconcilePodDisruptionBudgets(a))
+	pdb := &policyv1beta1.PodDisruptionBudget{}
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, nameWithSuffix("server", a), pdb))
+	assert.Equal(t, nameWithSuffix("server", a), pdb.Spec.Selector.MatchLabels[common.ArgoCDKeyName])
+	assert.False(t, argoutil.IsObjectFound(r.Client, a.Namespace, nameWithSuffix("server", a), &policyv1.PodDisruptionBudget{}))
+
+	a.Spec.HA.Enabled = false
+	assert.NoError(t, r.reconcilePodDisruptionBudgets(a))
+	assert.False(t, argoutil.IsObjectFound(r.Client, a.Namespace, nameWithSuffix("server", a), pdb))
+}
@@ -126,15 +126,11 @@ func newServiceMonitorWithSuffix(suffix string, cr *argoprojv1a1.ArgoCD) *monito
 // reconcileMetricsServiceMonitor will ensure that the ServiceMonitor is present for the ArgoCD metrics Service.
 func (r *ReconcileArgoCD) reconcileMetricsServiceMonitor(cr *argoprojv1a1.ArgoCD) error {
 	sm := newServiceMonitorWithSuffix(common.ArgoCDKeyMetrics, cr)
-	if argoutil.IsObjectFound(r.Client, cr.Namespace, sm.Name, sm) {
-		if !cr.Spec.Prometheus.Enabled {
+	if !cr.Spec.Prometheus.Enabled {
+		if argoutil.IsObjectFound(r.Client, cr.Namespace, sm.Name, sm) {
 			// ServiceMonitor exists but enabled flag has been set to false, delete the ServiceMonitor
 			return r.Client.Delete(context.TODO(), sm)
 		}
-		return nil // ServiceMonitor found, do nothing
-	}
-
-	if !cr.Spec.Prometheus.Enabled {
 		return nil // Prometheus not enabled, do nothing.
 	}
 
@@ -149,10 +145,7 @@ func (r *ReconcileArgoCD) reconcileMetricsServiceMonitor(cr *argoprojv1a1.ArgoCD
 		},
 	}
 
-	if err := controllerutil.SetControllerReference(cr, sm, r.Scheme); err != nil {
-		return err
-	}
-	return r.Client.Create(context.TODO(), sm)
+	return r.applyResource(cr, sm)
 }
 
 // reconcilePrometheus will ensure that Prometheus is present for ArgoCD metrics.
@@ -163,8 +156,12 @@ func (r *ReconcileArgoCD) reconcilePrometheus(cr *argoprojv1a1.ArgoCD) error {
 			// Prometheus exists but enabled flag has been set to false, delete the Prometheus
 			return r.Client.Delete(context.TODO(), prometheus)
 		}
+		changed := syncCommonMetadata(cr, prometheus)
 		if hasPrometheusSpecChanged(prometheus, cr) {
 			prometheus.Spec.Replicas = cr.Spec.Prometheus.Size
+			changed = true
+		}
+		if changed {
 			return r.Client.Update(context.TODO(), prometheus)
 		}
 		return nil // Prometheus found, do nothing
@@ -178,6 +175,7 @@ func (r *ReconcileArgoCD) reconcilePrometheus(cr *argoprojv1a1.ArgoCD) error {
 	prometheus.Spec.ServiceAccountName = "prometheus-k8s"
 	prometheus.Spec.ServiceMonitorSelector = &metav1.LabelSelector{}
 
+	syncCommonMetadata(cr, prometheus)
 	if err := controllerutil.SetControllerReference(cr, prometheus, r.Scheme); err != nil {
 		return err
 	}
@@ -187,15 +185,11 @@ func (r *ReconcileArgoCD) reconcilePrometheus(cr *argoprojv1a1.ArgoCD) error {
 // reconcileRepoServerServiceMonitor will ensure that the ServiceMonitor is present for the Repo Server metrics Service.
 func (r *ReconcileArgoCD) reconcileRepoServerServiceMonitor(cr *argoprojv1a1.ArgoCD) error {
 	sm := newServiceMonitorWithSuffix("repo-server-metrics", cr)
-	if argoutil.IsObjectFound(r.Client, cr.Namespace, sm.Name, sm) {
-		if !cr.Spec.Prometheus.Enabled {
+	if !cr.Spec.Prometheus.Enabled {
+		if argoutil.IsObjectFound(r.Client, cr.Namespace, sm.Name, sm) {
 			// ServiceMonitor exists but enabled flag has been set to false, delete the ServiceMonitor
 			return r.Client.Delete(context.TODO(), sm)
 		}
-		return nil // ServiceMonitor found, do nothing
-	}
-
-	if !cr.Spec.Prometheus.Enabled {
 		return nil // Prometheus not enabled, do nothing.
 	}
 
@@ -210,24 +204,17 @@ func (r *ReconcileArgoCD) reconcileRepoServerServiceMonitor(cr *argoprojv1a1.Arg
 		},
 	}
 
-	if err := controllerutil.SetControllerReference(cr, sm, r.Scheme); err != nil {
-		return err
-	}
-	return r.Client.Create(context.TODO(), sm)
+	return r.applyResource(cr, sm)
 }
 
 // reconcileServerMetricsServiceMonitor will ensure that the ServiceMonitor is present for the ArgoCD Server metrics Service.
 func (r *ReconcileArgoCD) reconcileServerMetricsServiceMonitor(cr *argoprojv1a1.ArgoCD) error {
 	sm := newServiceMonitorWithSuffix("server-metrics", cr)
-	if argoutil.IsObjectFound(r.Client, cr.Namespace, sm.Name, sm) {
-		if !cr.Spec.Prometheus.Enabled {
+	if !cr.Spec.Prometheus.Enabled {
+		if argoutil.IsObjectFound(r.Client, cr.Namespace, sm.Name, sm) {
 			// ServiceMonitor exists but enabled flag has been set to false, delete the ServiceMonitor
 			return r.Client.Delete(context.TODO(), sm)
 		}
-		return nil // ServiceMonitor found, do nothing
-	}
-
-	if !cr.Spec.Prometheus.Enabled {
 		return nil // Prometheus not enabled, do nothing.
 	}
 
@@ -242,8 +229,5 @@ func (r *ReconcileArgoCD) reconcileServerMetricsServiceMonitor(cr *argoprojv1a1.
 		},
 	}
 
-	if err := controllerutil.SetControllerReference(cr, sm, r.Scheme); err != nil {
-		return err
-	}
-	return r.Client.Create(context.TODO(), sm)
+	return r.applyResource(cr, sm)
 }
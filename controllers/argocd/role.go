@@ -88,6 +88,11 @@ func (r *ReconcileArgoCD) reconcileRole(name string, policyRules []v1.PolicyRule
 			return nil, err
 		}
 		role.Namespace = namespace.Name
+		syncCommonMetadata(cr, role)
+		rules := role.Rules
+		if err := r.applyPatches(cr, role); err != nil {
+			return nil, err
+		}
 		existingRole := v1.Role{}
 		err := r.Client.Get(context.TODO(), types.NamespacedName{Name: role.Name, Namespace: role.Namespace}, &existingRole)
 		if err != nil {
@@ -123,14 +128,20 @@ func (r *ReconcileArgoCD) reconcileRole(name string, policyRules []v1.PolicyRule
 			continue
 		}
 
-		// if the Rules differ, update the Role
-		if !reflect.DeepEqual(existingRole.Rules, role.Rules) {
-			existingRole.Rules = role.Rules
-			if err := r.Client.Update(context.TODO(), &existingRole); err != nil {
+		// if the Rules or the common metadata differ, update the Role. The patches are applied to the existing Role as
+		// well, so that they also change the fields that are not generated.
+		updatedRole := existingRole.DeepCopy()
+		updatedRole.Rules = rules
+		syncCommonMetadata(cr, updatedRole)
+		if err := r.applyPatches(cr, updatedRole); err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(&existingRole, updatedRole) {
+			if err := r.Client.Update(context.TODO(), updatedRole); err != nil {
 				return nil, err
 			}
 		}
-		roles = append(roles, &existingRole)
+		roles = append(roles, updatedRole)
 	}
 	return roles, nil
 }
@@ -144,6 +155,11 @@ func (r *ReconcileArgoCD) reconcileClusterRole(name string, policyRules []v1.Pol
 	if err := applyReconcilerHook(cr, clusterRole, ""); err != nil {
 		return nil, err
 	}
+	syncCommonMetadata(cr, clusterRole)
+	rules := clusterRole.Rules
+	if err := r.applyPatches(cr, clusterRole); err != nil {
+		return nil, err
+	}
 
 	existingClusterRole := &v1.ClusterRole{}
 	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: clusterRole.Name}, existingClusterRole)
@@ -162,14 +178,20 @@ func (r *ReconcileArgoCD) reconcileClusterRole(name string, policyRules []v1.Pol
 		return nil, r.Client.Delete(context.TODO(), existingClusterRole)
 	}
 
-	// if the Rules differ, update the Role
-	if !reflect.DeepEqual(existingClusterRole.Rules, clusterRole.Rules) {
-		existingClusterRole.Rules = clusterRole.Rules
-		if err := r.Client.Update(context.TODO(), existingClusterRole); err != nil {
+	// if the Rules or the common metadata differ, update the ClusterRole. The patches are applied to the existing
+	// ClusterRole as well, so that they also change the fields that are not generated.
+	updatedClusterRole := existingClusterRole.DeepCopy()
+	updatedClusterRole.Rules = rules
+	syncCommonMetadata(cr, updatedClusterRole)
+	if err := r.applyPatches(cr, updatedClusterRole); err != nil {
+		return nil, err
+	}
+	if !reflect.DeepEqual(existingClusterRole, updatedClusterRole) {
+		if err := r.Client.Update(context.TODO(), updatedClusterRole); err != nil {
 			return nil, err
 		}
 	}
-	return existingClusterRole, nil
+	return updatedClusterRole, nil
 }
 
 func deleteClusterRoles(c client.Client, clusterRoleList *v1.ClusterRoleList) error {
@@ -140,6 +140,12 @@ func (r *ReconcileArgoCD) reconcileRoleBinding(name string, rules []v1.PolicyRul
 			}
 		}
 
+		syncCommonMetadata(cr, roleBinding)
+		subjects := roleBinding.Subjects
+		if err := r.applyPatches(cr, roleBinding); err != nil {
+			return err
+		}
+
 		if roleBindingExists {
 			if name == common.ArgoCDDexServerComponent && isDexDisabled() {
 				// Delete any existing RoleBinding created for Dex
@@ -155,10 +161,16 @@ func (r *ReconcileArgoCD) reconcileRoleBinding(name string, rules []v1.PolicyRul
 					return err
 				}
 			} else {
-				// if the Subjects differ, update the role bindings
-				if !reflect.DeepEqual(roleBinding.Subjects, existingRoleBinding.Subjects) {
-					existingRoleBinding.Subjects = roleBinding.Subjects
-					if err = r.Client.Update(context.TODO(), existingRoleBinding); err != nil {
+				// if the Subjects or the common metadata differ, update the role bindings. The patches are applied to
+				// the existing role binding as well, so that they also change the fields that are not generated.
+				updatedRoleBinding := existingRoleBinding.DeepCopy()
+				updatedRoleBinding.Subjects = subjects
+				syncCommonMetadata(cr, updatedRoleBinding)
+				if err := r.applyPatches(cr, updatedRoleBinding); err != nil {
+					return err
+				}
+				if !reflect.DeepEqual(existingRoleBinding, updatedRoleBinding) {
+					if err = r.Client.Update(context.TODO(), updatedRoleBinding); err != nil {
 						return err
 					}
 				}
@@ -226,6 +238,11 @@ func (r *ReconcileArgoCD) reconcileClusterRoleBinding(name string, role *v1.Clus
 		Name:     GenerateUniqueResourceName(name, cr),
 	}
 
+	syncCommonMetadata(cr, roleBinding)
+	if err := r.applyPatches(cr, roleBinding); err != nil {
+		return err
+	}
+
 	if cr.Namespace == roleBinding.Namespace {
 		if err = controllerutil.SetControllerReference(cr, roleBinding, r.Scheme); err != nil {
 			return fmt.Errorf("failed to set ArgoCD CR \"%s\" as owner for roleBinding \"%s\": %s", cr.Name, roleBinding.Name, err)
@@ -21,7 +21,6 @@ import (
 	routev1 "github.com/openshift/api/route/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
 	"github.com/argoproj-labs/argocd-operator/common"
@@ -92,15 +91,11 @@ func (r *ReconcileArgoCD) reconcileRoutes(cr *argoprojv1a1.ArgoCD) error {
 // reconcileGrafanaRoute will ensure that the ArgoCD Grafana Route is present.
 func (r *ReconcileArgoCD) reconcileGrafanaRoute(cr *argoprojv1a1.ArgoCD) error {
 	route := newRouteWithSuffix("grafana", cr)
-	if argoutil.IsObjectFound(r.Client, cr.Namespace, route.Name, route) {
-		if !cr.Spec.Grafana.Enabled || !cr.Spec.Grafana.Route.Enabled {
+	if !cr.Spec.Grafana.Enabled || !cr.Spec.Grafana.Route.Enabled {
+		if argoutil.IsObjectFound(r.Client, cr.Namespace, route.Name, route) {
 			// Route exists but enabled flag has been set to false, delete the Route
 			return r.Client.Delete(context.TODO(), route)
 		}
-		return nil // Route found, do nothing
-	}
-
-	if !cr.Spec.Grafana.Enabled || !cr.Spec.Grafana.Route.Enabled {
 		return nil // Grafana itself or Route not enabled, do nothing.
 	}
 
@@ -145,24 +140,17 @@ func (r *ReconcileArgoCD) reconcileGrafanaRoute(cr *argoprojv1a1.ArgoCD) error {
 		route.Spec.WildcardPolicy = *cr.Spec.Grafana.Route.WildcardPolicy
 	}
 
-	if err := controllerutil.SetControllerReference(cr, route, r.Scheme); err != nil {
-		return err
-	}
-	return r.Client.Create(context.TODO(), route)
+	return r.applyResource(cr, route)
 }
 
 // reconcilePrometheusRoute will ensure that the ArgoCD Prometheus Route is present.
 func (r *ReconcileArgoCD) reconcilePrometheusRoute(cr *argoprojv1a1.ArgoCD) error {
 	route := newRouteWithSuffix("prometheus", cr)
-	if argoutil.IsObjectFound(r.Client, cr.Namespace, route.Name, route) {
-		if !cr.Spec.Prometheus.Enabled || !cr.Spec.Prometheus.Route.Enabled {
+	if !cr.Spec.Prometheus.Enabled || !cr.Spec.Prometheus.Route.Enabled {
+		if argoutil.IsObjectFound(r.Client, cr.Namespace, route.Name, route) {
 			// Route exists but enabled flag has been set to false, delete the Route
 			return r.Client.Delete(context.TODO(), route)
 		}
-		return nil // Route found, do nothing
-	}
-
-	if !cr.Spec.Prometheus.Enabled || !cr.Spec.Prometheus.Route.Enabled {
 		return nil // Prometheus itself or Route not enabled, do nothing.
 	}
 
@@ -202,25 +190,17 @@ func (r *ReconcileArgoCD) reconcilePrometheusRoute(cr *argoprojv1a1.ArgoCD) erro
 		route.Spec.WildcardPolicy = *cr.Spec.Prometheus.Route.WildcardPolicy
 	}
 
-	if err := controllerutil.SetControllerReference(cr, route, r.Scheme); err != nil {
-		return err
-	}
-	return r.Client.Create(context.TODO(), route)
+	return r.applyResource(cr, route)
 }
 
 // reconcileServerRoute will ensure that the ArgoCD Server Route is present.
 func (r *ReconcileArgoCD) reconcileServerRoute(cr *argoprojv1a1.ArgoCD) error {
-
 	route := newRouteWithSuffix("server", cr)
-	found := argoutil.IsObjectFound(r.Client, cr.Namespace, route.Name, route)
-	if found {
-		if !cr.Spec.Server.Route.Enabled {
+	if !cr.Spec.Server.Route.Enabled {
+		if argoutil.IsObjectFound(r.Client, cr.Namespace, route.Name, route) {
 			// Route exists but enabled flag has been set to false, delete the Route
 			return r.Client.Delete(context.TODO(), route)
 		}
-	}
-
-	if !cr.Spec.Server.Route.Enabled {
 		return nil // Route not enabled, move along...
 	}
 
@@ -276,11 +256,5 @@ func (r *ReconcileArgoCD) reconcileServerRoute(cr *argoprojv1a1.ArgoCD) error {
 		route.Spec.WildcardPolicy = *cr.Spec.Server.Route.WildcardPolicy
 	}
 
-	if err := controllerutil.SetControllerReference(cr, route, r.Scheme); err != nil {
-		return err
-	}
-	if !found {
-		return r.Client.Create(context.TODO(), route)
-	}
-	return r.Client.Update(context.TODO(), route)
+	return r.applyResource(cr, route)
 }
@@ -207,7 +207,7 @@ func makeReconciler(t *testing.T, acd *argov1alpha1.ArgoCD, objs ...runtime.Obje
 	routev1.Install(s)
 	cl := fake.NewFakeClient(objs...)
 	return &ReconcileArgoCD{
-		Client: cl,
+		Client: newApplyClient(cl),
 		Scheme: s,
 	}
 }
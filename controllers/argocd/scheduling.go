@@ -0,0 +1,60 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/common"
+)
+
+// applyScheduling will set the given scheduling options of a component on the given pod template. The affinity of
+// the component replaces the default affinity of the pod template, if any.
+func applyScheduling(template *corev1.PodTemplateSpec, scheduling *argoprojv1a1.ArgoCDSchedulingSpec) {
+	if scheduling == nil {
+		return
+	}
+
+	if scheduling.Affinity != nil {
+		template.Spec.Affinity = scheduling.Affinity.DeepCopy()
+	}
+	if scheduling.PriorityClassName != "" {
+		template.Spec.PriorityClassName = scheduling.PriorityClassName
+	}
+	if scheduling.RuntimeClassName != nil {
+		template.Spec.RuntimeClassName = scheduling.RuntimeClassName
+	}
+	if len(scheduling.TopologySpreadConstraints) > 0 {
+		template.Spec.TopologySpreadConstraints = scheduling.TopologySpreadConstraints
+	}
+}
+
+// zoneAntiAffinityTerm returns a preferred pod anti-affinity term that spreads the pods with the given name across
+// zones.
+func zoneAntiAffinityTerm(name string) corev1.WeightedPodAffinityTerm {
+	return corev1.WeightedPodAffinityTerm{
+		PodAffinityTerm: corev1.PodAffinityTerm{
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					common.ArgoCDKeyName: name,
+				},
+			},
+			TopologyKey: common.ArgoCDKeyTopologyZone,
+		},
+		Weight: int32(100),
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/common"
+	"github.com/argoproj-labs/argocd-operator/controllers/argoutil"
+)
+
+func TestApplyScheduling(t *testing.T) {
+	defaultAffinity := &corev1.Affinity{
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{zoneAntiAffinityTerm("argocd-server")},
+		},
+	}
+
+	template := corev1.PodTemplateSpec{}
+	template.Spec.Affinity = defaultAffinity
+	applyScheduling(&template, nil)
+	assert.Equal(t, defaultAffinity, template.Spec.Affinity)
+
+	// The default affinity is retained unless the component specifies one
+	applyScheduling(&template, &argoprojv1alpha1.ArgoCDSchedulingSpec{PriorityClassName: "system-cluster-critical"})
+	assert.Equal(t, defaultAffinity, template.Spec.Affinity)
+	assert.Equal(t, "system-cluster-critical", template.Spec.PriorityClassName)
+
+	runtimeClassName := "gvisor"
+	scheduling := &argoprojv1alpha1.ArgoCDSchedulingSpec{
+		Affinity: &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+						MatchExpressions: []corev1.NodeSelectorRequirement{{
+							Key:      "node-role.kubernetes.io/infra",
+							Operator: corev1.NodeSelectorOpExists,
+						}},
+					}},
+				},
+			},
+		},
+		RuntimeClassName: &runtimeClassName,
+		TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{
+			MaxSkew:           1,
+			TopologyKey:       common.ArgoCDKeyTopologyZone,
+			WhenUnsatisfiable: corev1.DoNotSchedule,
+		}},
+	}
+	applyScheduling(&template, scheduling)
+	assert.Equal(t, scheduling.Affinity, template.Spec.Affinity)
+	assert.Equal(t, &runtimeClassName, template.Spec.RuntimeClassName)
+	assert.Equal(t, scheduling.TopologySpreadConstraints, template.Spec.TopologySpreadConstraints)
+	assert.Equal(t, "system-cluster-critical", template.Spec.PriorityClassName)
+}
+
+func TestReconcileArgoCD_reconcileRepoDeployment_scheduling(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.HA.Enabled = true
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NoError(t, r.reconcileRepoDeployment(a))
+
+	deploy := &appsv1.Deployment{}
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, nameWithSuffix("repo-server", a), deploy))
+	assert.Contains(t, deploy.Spec.Template.Spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+		zoneAntiAffinityTerm(nameWithSuffix("repo-server", a)))
+
+	a.Spec.Repo.Scheduling = &argoprojv1alpha1.ArgoCDSchedulingSpec{
+		PriorityClassName: "system-cluster-critical",
+		TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{
+			MaxSkew:           1,
+			TopologyKey:       common.ArgoCDKeyTopologyZone,
+			WhenUnsatisfiable: corev1.ScheduleAnyway,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{common.ArgoCDKeyName: nameWithSuffix("repo-server", a)},
+			},
+		}},
+	}
+	assert.NoError(t, r.reconcileRepoDeployment(a))
+
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, nameWithSuffix("repo-server", a), deploy))
+	assert.Equal(t, "system-cluster-critical", deploy.Spec.Template.Spec.PriorityClassName)
+	assert.Equal(t, a.Spec.Repo.Scheduling.TopologySpreadConstraints, deploy.Spec.Template.Spec.TopologySpreadConstraints)
+	assert.NotNil(t, deploy.Spec.Template.Spec.Affinity)
+}
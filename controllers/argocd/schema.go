@@ -0,0 +1,224 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/common"
+	"github.com/argoproj-labs/argocd-operator/controllers/argoutil"
+)
+
+// schemaMigration migrates the resources that earlier versions of the operator generated for an ArgoCD, e.g. by
+// deleting resources that were renamed or are no longer needed. Migrations must be idempotent, as a migration is run
+// again if the status of the ArgoCD could not be updated after it ran.
+type schemaMigration struct {
+	name    string
+	migrate func(r *ReconcileArgoCD, cr *argoprojv1a1.ArgoCD) error
+}
+
+// schemaMigrations lists the migrations of the generated resources in order, the schema version of an ArgoCD is the
+// number of migrations that were run for it. New migrations must only be appended.
+var schemaMigrations = []schemaMigration{
+	{name: "DeleteApplicationControllerDeployment", migrate: (*ReconcileArgoCD).deleteApplicationControllerDeployment},
+	{name: "DeleteOrphanedClusterRBAC", migrate: (*ReconcileArgoCD).deleteOrphanedClusterRBAC},
+	{name: "DeleteDuplicateServices", migrate: (*ReconcileArgoCD).deleteDuplicateServices},
+	{name: "LabelClusterRBAC", migrate: (*ReconcileArgoCD).labelClusterRBAC},
+}
+
+// getSchemaVersion returns the schema version of the resources generated by the running operator.
+func getSchemaVersion() int32 {
+	return int32(len(schemaMigrations))
+}
+
+// reconcileSchemaMigrations will run the migrations that are newer than the schema version of the given ArgoCD in
+// order, and stamp the ArgoCD with the schema version of the last migration that succeeded. A new ArgoCD has no
+// resources to migrate, so it is stamped with the latest schema version directly.
+func (r *ReconcileArgoCD) reconcileSchemaMigrations(cr *argoprojv1a1.ArgoCD) error {
+	if cr.Status.SchemaVersion == 0 && cr.Status.Phase == "" {
+		cr.Status.SchemaVersion = getSchemaVersion()
+		return nil
+	}
+
+	if cr.Status.SchemaVersion > getSchemaVersion() {
+		log.Info(fmt.Sprintf("the resources were generated by a newer version of the operator with schema version %d, not migrating",
+			cr.Status.SchemaVersion), "namespace", cr.Namespace, "name", cr.Name)
+		return nil
+	}
+
+	for i := cr.Status.SchemaVersion; i < getSchemaVersion(); i++ {
+		migration := schemaMigrations[i]
+		log.Info(fmt.Sprintf("running migration %d: %s", i+1, migration.name), "namespace", cr.Namespace, "name", cr.Name)
+		if err := migration.migrate(r, cr); err != nil {
+			return fmt.Errorf("failed to run migration %d (%s): %w", i+1, migration.name, err)
+		}
+		cr.Status.SchemaVersion = i + 1
+	}
+	return nil
+}
+
+// deleteApplicationControllerDeployment will delete the Deployment that ran the application controller before it was
+// replaced by a StatefulSet.
+func (r *ReconcileArgoCD) deleteApplicationControllerDeployment(cr *argoprojv1a1.ArgoCD) error {
+	deploy := newDeploymentWithSuffix("application-controller", "application-controller", cr)
+	if !argoutil.IsObjectFound(r.Client, deploy.Namespace, deploy.Name, deploy) {
+		return nil
+	}
+	return client.IgnoreNotFound(r.Client.Delete(context.TODO(), deploy))
+}
+
+// deleteOrphanedClusterRBAC will delete the ClusterRoles and ClusterRoleBindings of the given ArgoCD whose names are no
+// longer generated, e.g. after the naming scheme changed.
+func (r *ReconcileArgoCD) deleteOrphanedClusterRBAC(cr *argoprojv1a1.ArgoCD) error {
+	names := map[string]bool{}
+	for _, param := range getPolicyRuleClusterRoleList() {
+		names[GenerateUniqueResourceName(param.name, cr)] = true
+	}
+
+	selector, err := argocdInstanceSelector(cr.Name)
+	if err != nil {
+		return err
+	}
+
+	clusterRoles := &v1.ClusterRoleList{}
+	if err := filterObjectsBySelector(r.Client, clusterRoles, selector); err != nil {
+		return fmt.Errorf("failed to filter ClusterRoles for %s: %w", cr.Name, err)
+	}
+	for i := range clusterRoles.Items {
+		if err := r.deleteOrphanedClusterObject(cr, &clusterRoles.Items[i], names); err != nil {
+			return err
+		}
+	}
+
+	clusterRoleBindings := &v1.ClusterRoleBindingList{}
+	if err := filterObjectsBySelector(r.Client, clusterRoleBindings, selector); err != nil {
+		return fmt.Errorf("failed to filter ClusterRoleBindings for %s: %w", cr.Name, err)
+	}
+	for i := range clusterRoleBindings.Items {
+		if err := r.deleteOrphanedClusterObject(cr, &clusterRoleBindings.Items[i], names); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteOrphanedClusterObject will delete the given cluster-scoped object if it belongs to the given ArgoCD and its
+// name is not one of the given names. Instances with the same name in other namespaces share the labels, so the
+// namespace annotation is used to tell them apart.
+func (r *ReconcileArgoCD) deleteOrphanedClusterObject(cr *argoprojv1a1.ArgoCD, obj client.Object, names map[string]bool) error {
+	if names[obj.GetName()] || obj.GetAnnotations()[common.AnnotationNamespace] != cr.Namespace {
+		return nil
+	}
+
+	log.Info(fmt.Sprintf("deleting orphaned %T %s", obj, obj.GetName()), "namespace", cr.Namespace, "name", cr.Name)
+	return client.IgnoreNotFound(r.Client.Delete(context.TODO(), obj))
+}
+
+// getServiceNames returns the names of the Services that are generated for the given ArgoCD.
+func getServiceNames(cr *argoprojv1a1.ArgoCD) map[string]bool {
+	suffixes := []string{"dex-server", "grafana", "metrics", "redis-ha", "redis-ha-haproxy", "redis", "repo-server",
+		"server-metrics", "server"}
+	for i := int32(0); i < common.ArgoCDDefaultRedisHAReplicas; i++ {
+		suffixes = append(suffixes, fmt.Sprintf("redis-ha-announce-%d", i))
+	}
+
+	names := map[string]bool{}
+	for _, suffix := range suffixes {
+		names[nameWithSuffix(suffix, cr)] = true
+	}
+	return names
+}
+
+// deleteDuplicateServices will delete the Services that are controlled by the given ArgoCD whose names are no longer
+// generated, as they would otherwise select the same pods as the Services that replaced them.
+func (r *ReconcileArgoCD) deleteDuplicateServices(cr *argoprojv1a1.ArgoCD) error {
+	names := getServiceNames(cr)
+
+	selector, err := argocdInstanceSelector(cr.Name)
+	if err != nil {
+		return err
+	}
+
+	services := &corev1.ServiceList{}
+	if err := r.Client.List(context.TODO(), services, client.InNamespace(cr.Namespace),
+		client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return fmt.Errorf("failed to list Services for %s: %w", cr.Name, err)
+	}
+	for i := range services.Items {
+		svc := &services.Items[i]
+		if names[svc.Name] || !metav1.IsControlledBy(svc, cr) {
+			continue
+		}
+
+		log.Info(fmt.Sprintf("deleting duplicate Service %s", svc.Name), "namespace", cr.Namespace, "name", cr.Name)
+		if err := client.IgnoreNotFound(r.Client.Delete(context.TODO(), svc)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// labelClusterRBAC will add the default labels and annotations to the ClusterRoles and ClusterRoleBindings of the
+// given ArgoCD that were generated before they were set, as they are only set when the objects are created and the
+// objects are otherwise not found by the cleanup of the ArgoCD.
+func (r *ReconcileArgoCD) labelClusterRBAC(cr *argoprojv1a1.ArgoCD) error {
+	for _, param := range getPolicyRuleClusterRoleList() {
+		name := GenerateUniqueResourceName(param.name, cr)
+		for _, obj := range []client.Object{&v1.ClusterRole{}, &v1.ClusterRoleBinding{}} {
+			if !argoutil.IsObjectFound(r.Client, "", name, obj) {
+				continue
+			}
+			labeled := mergeMissing(obj.GetLabels, obj.SetLabels, argoutil.LabelsForCluster(cr))
+			annotated := mergeMissing(obj.GetAnnotations, obj.SetAnnotations, argoutil.AnnotationsForCluster(cr))
+			if !labeled && !annotated {
+				continue
+			}
+
+			log.Info(fmt.Sprintf("labeling %T %s", obj, name), "namespace", cr.Namespace, "name", cr.Name)
+			if err := r.Client.Update(context.TODO(), obj); err != nil {
+				return fmt.Errorf("failed to label %T %s: %w", obj, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// mergeMissing will set the given values whose keys are missing from the map returned by get, and returns true if the
+// map was changed.
+func mergeMissing(get func() map[string]string, set func(map[string]string), values map[string]string) bool {
+	existing := get()
+	if existing == nil {
+		existing = map[string]string{}
+	}
+
+	changed := false
+	for key, val := range values {
+		if _, ok := existing[key]; !ok {
+			existing[key] = val
+			changed = true
+		}
+	}
+	if changed {
+		set(existing)
+	}
+	return changed
+}
@@ -0,0 +1,149 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/common"
+	"github.com/argoproj-labs/argocd-operator/controllers/argoutil"
+)
+
+func TestReconcileArgoCD_reconcileSchemaMigrations(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD()
+	a.Status.Phase = "Available"
+	r := makeTestReconciler(t, a)
+
+	clusterRole := func(name string, namespace string) *v1.ClusterRole {
+		return &v1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Labels:      argoutil.LabelsForCluster(a),
+				Annotations: common.DefaultAnnotations(a.Name, namespace),
+			},
+		}
+	}
+	current := clusterRole(GenerateUniqueResourceName(common.ArgoCDServerComponent, a), a.Namespace)
+	orphaned := clusterRole(nameWithSuffix(common.ArgoCDServerComponent, a), a.Namespace)
+	otherInstance := clusterRole(nameWithSuffix(common.ArgoCDServerComponent, a), "other-namespace")
+	otherInstance.Name = "other-" + otherInstance.Name
+	for _, obj := range []*v1.ClusterRole{current, orphaned, otherInstance} {
+		assert.NoError(t, r.Client.Create(context.TODO(), obj))
+	}
+
+	assert.NoError(t, r.reconcileSchemaMigrations(a))
+	assert.Equal(t, getSchemaVersion(), a.Status.SchemaVersion)
+
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: current.Name}, &v1.ClusterRole{}))
+	assert.Error(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: orphaned.Name}, &v1.ClusterRole{}))
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: otherInstance.Name}, &v1.ClusterRole{}))
+
+	// Migrations are only run once
+	orphaned.ResourceVersion = ""
+	assert.NoError(t, r.Client.Create(context.TODO(), orphaned))
+	assert.NoError(t, r.reconcileSchemaMigrations(a))
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: orphaned.Name}, &v1.ClusterRole{}))
+}
+
+func TestReconcileArgoCD_reconcileSchemaMigrations_newerSchemaVersion(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD()
+	a.Status.SchemaVersion = getSchemaVersion() + 1
+	r := makeTestReconciler(t, a)
+
+	deploy := newDeploymentWithSuffix("application-controller", "application-controller", a)
+	assert.NoError(t, r.Client.Create(context.TODO(), deploy))
+
+	// The resources of a newer version of the operator are not migrated
+	assert.NoError(t, r.reconcileSchemaMigrations(a))
+	assert.Equal(t, getSchemaVersion()+1, a.Status.SchemaVersion)
+	assert.True(t, argoutil.IsObjectFound(r.Client, deploy.Namespace, deploy.Name, deploy))
+}
+
+func TestReconcileArgoCD_reconcileSchemaMigrations_newInstance(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+
+	deploy := newDeploymentWithSuffix("application-controller", "application-controller", a)
+	assert.NoError(t, r.Client.Create(context.TODO(), deploy))
+
+	// A new instance is stamped with the latest schema version without running the migrations
+	assert.NoError(t, r.reconcileSchemaMigrations(a))
+	assert.Equal(t, getSchemaVersion(), a.Status.SchemaVersion)
+	assert.True(t, argoutil.IsObjectFound(r.Client, deploy.Namespace, deploy.Name, deploy))
+}
+
+func TestReconcileArgoCD_deleteDuplicateServices(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD()
+	a.UID = "test-uid"
+	r := makeTestReconciler(t, a)
+
+	service := func(name string, owned bool) *corev1.Service {
+		svc := newServiceWithName(name, "server", a)
+		if owned {
+			assert.NoError(t, controllerutil.SetControllerReference(a, svc, r.Scheme))
+		}
+		assert.NoError(t, r.Client.Create(context.TODO(), svc))
+		return svc
+	}
+	current := service(nameWithSuffix("server", a), true)
+	duplicate := service(nameWithSuffix("argocd-server", a), true)
+	unowned := service(nameWithSuffix("custom", a), false)
+
+	assert.NoError(t, r.deleteDuplicateServices(a))
+
+	assert.True(t, argoutil.IsObjectFound(r.Client, a.Namespace, current.Name, &corev1.Service{}))
+	assert.False(t, argoutil.IsObjectFound(r.Client, a.Namespace, duplicate.Name, &corev1.Service{}))
+	assert.True(t, argoutil.IsObjectFound(r.Client, a.Namespace, unowned.Name, &corev1.Service{}))
+}
+
+func TestReconcileArgoCD_labelClusterRBAC(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1a1.ArgoCD) {
+		a.Annotations = map[string]string{"example.com/owner": "team"}
+	})
+	r := makeTestReconciler(t, a)
+
+	name := GenerateUniqueResourceName(common.ArgoCDServerComponent, a)
+	clusterRole := &v1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"custom": "label"}}}
+	clusterRoleBinding := &v1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	assert.NoError(t, r.Client.Create(context.TODO(), clusterRole))
+	assert.NoError(t, r.Client.Create(context.TODO(), clusterRoleBinding))
+
+	assert.NoError(t, r.labelClusterRBAC(a))
+
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: name}, clusterRole))
+	assert.Equal(t, "label", clusterRole.Labels["custom"])
+	assert.Equal(t, a.Name, clusterRole.Labels[common.ArgoCDKeyManagedBy])
+	assert.Equal(t, a.Namespace, clusterRole.Annotations[common.AnnotationNamespace])
+	assert.Equal(t, "team", clusterRole.Annotations["example.com/owner"])
+
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: name}, clusterRoleBinding))
+	assert.Equal(t, a.Name, clusterRoleBinding.Labels[common.ArgoCDKeyManagedBy])
+	assert.Equal(t, a.Namespace, clusterRoleBinding.Annotations[common.AnnotationNamespace])
+}
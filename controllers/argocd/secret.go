@@ -73,11 +73,6 @@ func nowBytes() []byte {
 	return []byte(time.Now().UTC().Format(time.RFC3339))
 }
 
-// nowNano returns a string with the current UTC time as epoch in nanoseconds
-func nowNano() string {
-	return fmt.Sprintf("%d", time.Now().UTC().UnixNano())
-}
-
 // newCASecret creates a new CA secret with the given suffix for the given ArgoCD.
 func newCASecret(cr *argoprojv1a1.ArgoCD) (*corev1.Secret, error) {
 	secret := argoutil.NewTLSSecret(cr, "ca")
@@ -144,7 +139,7 @@ func newCertificateSecret(suffix string, caCert *x509.Certificate, caKey *rsa.Pr
 	return secret, nil
 }
 
-// reconcileArgoSecret will ensure that the Argo CD Secret is present.
+// reconcileArgoSecret will ensure that the Argo CD Secret is present and up to date.
 func (r *ReconcileArgoCD) reconcileArgoSecret(cr *argoprojv1a1.ArgoCD) error {
 	clusterSecret := argoutil.NewSecretWithSuffix(cr, "cluster")
 	secret := argoutil.NewSecretWithName(cr, common.ArgoCDSecretName)
@@ -160,39 +155,67 @@ func (r *ReconcileArgoCD) reconcileArgoSecret(cr *argoprojv1a1.ArgoCD) error {
 		return nil
 	}
 
-	if argoutil.IsObjectFound(r.Client, cr.Namespace, secret.Name, secret) {
-		return r.reconcileExistingArgoSecret(secret, clusterSecret, tlsSecret)
+	existing := argoutil.NewSecretWithName(cr, common.ArgoCDSecretName)
+	found := argoutil.IsObjectFound(r.Client, cr.Namespace, existing.Name, existing)
+
+	secret.Data = map[string][]byte{
+		common.ArgoCDKeyTLSCert:       tlsSecret.Data[common.ArgoCDKeyTLSCert],
+		common.ArgoCDKeyTLSPrivateKey: tlsSecret.Data[common.ArgoCDKeyTLSPrivateKey],
 	}
 
-	// Secret not found, create it...
-	hashedPassword, err := argopass.HashPassword(string(clusterSecret.Data[common.ArgoCDKeyAdminPassword]))
-	if err != nil {
-		return err
+	// Keep the password hash and modification time unless the admin password has changed.
+	if found && !hasArgoAdminPasswordChanged(existing, clusterSecret) {
+		secret.Data[common.ArgoCDKeyAdminPassword] = existing.Data[common.ArgoCDKeyAdminPassword]
+		secret.Data[common.ArgoCDKeyAdminPasswordMTime] = existing.Data[common.ArgoCDKeyAdminPasswordMTime]
+	} else {
+		pwBytes := clusterSecret.Data[common.ArgoCDKeyAdminPassword]
+		hashedPassword, err := argopass.HashPassword(strings.TrimRight(string(pwBytes), "\n"))
+		if err != nil {
+			return err
+		}
+		secret.Data[common.ArgoCDKeyAdminPassword] = []byte(hashedPassword)
+		secret.Data[common.ArgoCDKeyAdminPasswordMTime] = nowBytes()
 	}
 
-	sessionKey, err := generateArgoServerSessionKey()
-	if err != nil {
-		return err
+	if sessionKey, ok := existing.Data[common.ArgoCDKeyServerSecretKey]; ok {
+		secret.Data[common.ArgoCDKeyServerSecretKey] = sessionKey
+	} else {
+		sessionKey, err := generateArgoServerSessionKey()
+		if err != nil {
+			return err
+		}
+		secret.Data[common.ArgoCDKeyServerSecretKey] = sessionKey
 	}
 
-	secret.Data = map[string][]byte{
-		common.ArgoCDKeyAdminPassword:      []byte(hashedPassword),
-		common.ArgoCDKeyAdminPasswordMTime: nowBytes(),
-		common.ArgoCDKeyServerSecretKey:    sessionKey,
-		common.ArgoCDKeyTLSCert:            tlsSecret.Data[common.ArgoCDKeyTLSCert],
-		common.ArgoCDKeyTLSPrivateKey:      tlsSecret.Data[common.ArgoCDKeyTLSPrivateKey],
+	// The OIDC client secret of Keycloak is written by the SSO reconciliation, keep it while Keycloak is used.
+	keycloak := cr.Spec.SSO != nil && cr.Spec.SSO.Provider == argoprojv1a1.SSOProviderTypeKeycloak
+	clientSecret, hasClientSecret := existing.Data[common.ArgoCDKeyKeycloakClientSecret]
+	if keycloak && hasClientSecret {
+		secret.Data[common.ArgoCDKeyKeycloakClientSecret] = clientSecret
 	}
 
-	if err := controllerutil.SetControllerReference(cr, secret, r.Scheme); err != nil {
+	if err := r.applyResource(cr, secret); err != nil {
 		return err
 	}
-	return r.Client.Create(context.TODO(), secret)
+
+	// The client secret is written with an update, so it is not removed by the apply once Keycloak is no longer used.
+	if !keycloak && hasClientSecret {
+		if err := argoutil.FetchObject(r.Client, cr.Namespace, existing.Name, existing); err != nil {
+			return err
+		}
+		delete(existing.Data, common.ArgoCDKeyKeycloakClientSecret)
+		return r.Client.Update(context.TODO(), existing)
+	}
+	return nil
 }
 
 // reconcileClusterMainSecret will ensure that the main Secret is present for the Argo CD cluster.
 func (r *ReconcileArgoCD) reconcileClusterMainSecret(cr *argoprojv1a1.ArgoCD) error {
 	secret := argoutil.NewSecretWithSuffix(cr, "cluster")
 	if argoutil.IsObjectFound(r.Client, cr.Namespace, secret.Name, secret) {
+		if syncCommonMetadata(cr, secret) {
+			return r.Client.Update(context.TODO(), secret)
+		}
 		return nil // Secret found, do nothing
 	}
 
@@ -205,6 +228,7 @@ func (r *ReconcileArgoCD) reconcileClusterMainSecret(cr *argoprojv1a1.ArgoCD) er
 		common.ArgoCDKeyAdminPassword: adminPassword,
 	}
 
+	syncCommonMetadata(cr, secret)
 	if err := controllerutil.SetControllerReference(cr, secret, r.Scheme); err != nil {
 		return err
 	}
@@ -215,6 +239,9 @@ func (r *ReconcileArgoCD) reconcileClusterMainSecret(cr *argoprojv1a1.ArgoCD) er
 func (r *ReconcileArgoCD) reconcileClusterTLSSecret(cr *argoprojv1a1.ArgoCD) error {
 	secret := argoutil.NewTLSSecret(cr, "tls")
 	if argoutil.IsObjectFound(r.Client, cr.Namespace, secret.Name, secret) {
+		if syncCommonMetadata(cr, secret) {
+			return r.Client.Update(context.TODO(), secret)
+		}
 		return nil // Secret found, do nothing
 	}
 
@@ -239,6 +266,7 @@ func (r *ReconcileArgoCD) reconcileClusterTLSSecret(cr *argoprojv1a1.ArgoCD) err
 		return err
 	}
 
+	syncCommonMetadata(cr, secret)
 	if err := controllerutil.SetControllerReference(cr, secret, r.Scheme); err != nil {
 		return err
 	}
@@ -250,6 +278,9 @@ func (r *ReconcileArgoCD) reconcileClusterTLSSecret(cr *argoprojv1a1.ArgoCD) err
 func (r *ReconcileArgoCD) reconcileClusterCASecret(cr *argoprojv1a1.ArgoCD) error {
 	secret := argoutil.NewSecretWithSuffix(cr, "ca")
 	if argoutil.IsObjectFound(r.Client, cr.Namespace, secret.Name, secret) {
+		if syncCommonMetadata(cr, secret) {
+			return r.Client.Update(context.TODO(), secret)
+		}
 		return nil // Secret found, do nothing
 	}
 
@@ -258,6 +289,7 @@ func (r *ReconcileArgoCD) reconcileClusterCASecret(cr *argoprojv1a1.ArgoCD) erro
 		return err
 	}
 
+	syncCommonMetadata(cr, secret)
 	if err := controllerutil.SetControllerReference(cr, secret, r.Scheme); err != nil {
 		return err
 	}
@@ -289,45 +321,7 @@ func (r *ReconcileArgoCD) reconcileClusterSecrets(cr *argoprojv1a1.ArgoCD) error
 	return nil
 }
 
-// reconcileExistingArgoSecret will ensure that the Argo CD Secret is up to date.
-func (r *ReconcileArgoCD) reconcileExistingArgoSecret(secret *corev1.Secret, clusterSecret *corev1.Secret, tlsSecret *corev1.Secret) error {
-	changed := false
-
-	if hasArgoAdminPasswordChanged(secret, clusterSecret) {
-		pwBytes, ok := clusterSecret.Data[common.ArgoCDKeyAdminPassword]
-		if ok {
-			hashedPassword, err := argopass.HashPassword(strings.TrimRight(string(pwBytes), "\n"))
-			if err != nil {
-				return err
-			}
-
-			if secret.Data == nil {
-				secret.Data = make(map[string][]byte)
-			}
-
-			secret.Data[common.ArgoCDKeyAdminPassword] = []byte(hashedPassword)
-			secret.Data[common.ArgoCDKeyAdminPasswordMTime] = nowBytes()
-			changed = true
-		}
-	}
-
-	if hasArgoTLSChanged(secret, tlsSecret) {
-		secret.Data[common.ArgoCDKeyTLSCert] = tlsSecret.Data[common.ArgoCDKeyTLSCert]
-		secret.Data[common.ArgoCDKeyTLSPrivateKey] = tlsSecret.Data[common.ArgoCDKeyTLSPrivateKey]
-		changed = true
-	}
-
-	if changed {
-		log.Info("updating argo secret")
-		if err := r.Client.Update(context.TODO(), secret); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-// reconcileGrafanaSecret will ensure that the Grafana Secret is present.
+// reconcileGrafanaSecret will ensure that the Grafana Secret is present and up to date.
 func (r *ReconcileArgoCD) reconcileGrafanaSecret(cr *argoprojv1a1.ArgoCD) error {
 	if !cr.Spec.Grafana.Enabled {
 		return nil // Grafana not enabled, do nothing.
@@ -341,40 +335,16 @@ func (r *ReconcileArgoCD) reconcileGrafanaSecret(cr *argoprojv1a1.ArgoCD) error
 		return nil
 	}
 
-	if argoutil.IsObjectFound(r.Client, cr.Namespace, secret.Name, secret) {
-		actual := string(secret.Data[common.ArgoCDKeyGrafanaAdminPassword])
-		expected := string(clusterSecret.Data[common.ArgoCDKeyAdminPassword])
-
-		if actual != expected {
-			log.Info("cluster secret changed, updating and reloading grafana")
-			secret.Data[common.ArgoCDKeyGrafanaAdminPassword] = clusterSecret.Data[common.ArgoCDKeyAdminPassword]
-			if err := r.Client.Update(context.TODO(), secret); err != nil {
-				return err
-			}
-
-			// Regenerate the Grafana configuration
-			cm := newConfigMapWithSuffix("grafana-config", cr)
-			if !argoutil.IsObjectFound(r.Client, cm.Namespace, cm.Name, cm) {
-				log.Info("unable to locate grafana-config")
-				return nil
-			}
-
-			if err := r.Client.Delete(context.TODO(), cm); err != nil {
-				return err
-			}
+	existing := argoutil.NewSecretWithSuffix(cr, "grafana")
+	found := argoutil.IsObjectFound(r.Client, cr.Namespace, existing.Name, existing)
 
-			// Trigger rollout of Grafana Deployment
-			deploy := newDeploymentWithSuffix("grafana", "grafana", cr)
-			return r.triggerRollout(deploy, "admin.password.changed")
+	secretKey, ok := existing.Data[common.ArgoCDKeyGrafanaSecretKey]
+	if !found || !ok {
+		key, err := generateGrafanaSecretKey()
+		if err != nil {
+			return err
 		}
-		return nil // Nothing has changed, move along...
-	}
-
-	// Secret not found, create it...
-
-	secretKey, err := generateGrafanaSecretKey()
-	if err != nil {
-		return err
+		secretKey = key
 	}
 
 	secret.Data = map[string][]byte{
@@ -383,10 +353,7 @@ func (r *ReconcileArgoCD) reconcileGrafanaSecret(cr *argoprojv1a1.ArgoCD) error
 		common.ArgoCDKeyGrafanaSecretKey:     secretKey,
 	}
 
-	if err := controllerutil.SetControllerReference(cr, secret, r.Scheme); err != nil {
-		return err
-	}
-	return r.Client.Create(context.TODO(), secret)
+	return r.applyResource(cr, secret)
 }
 
 // reconcileClusterPermissionsSecret ensures ArgoCD instance is namespace-scoped
@@ -458,6 +425,7 @@ func (r *ReconcileArgoCD) reconcileClusterPermissionsSecret(cr *argoprojv1a1.Arg
 				sort.Strings(ns)
 				s.Data["namespaces"] = []byte(strings.Join(ns, ","))
 			}
+			syncCommonMetadata(cr, &s)
 			return r.Client.Update(context.TODO(), &s)
 		}
 	}
@@ -467,16 +435,15 @@ func (r *ReconcileArgoCD) reconcileClusterPermissionsSecret(cr *argoprojv1a1.Arg
 		return nil
 	}
 
+	syncCommonMetadata(cr, secret)
 	if err := controllerutil.SetControllerReference(cr, secret, r.Scheme); err != nil {
 		return err
 	}
 	return r.Client.Create(context.TODO(), secret)
 }
 
-// reconcileRepoServerTLSSecret checks whether the argocd-repo-server-tls secret
-// has changed since our last reconciliation loop. It does so by comparing the
-// checksum of tls.crt and tls.key in the status of the ArgoCD CR against the
-// values calculated from the live state in the cluster.
+// reconcileRepoServerTLSSecret records the checksum of tls.crt and tls.key of
+// the argocd-repo-server-tls secret in the status of the ArgoCD CR.
 func (r *ReconcileArgoCD) reconcileRepoServerTLSSecret(cr *argoprojv1a1.ArgoCD) error {
 	var tlsSecretObj corev1.Secret
 	var sha256sum string
@@ -504,39 +471,10 @@ func (r *ReconcileArgoCD) reconcileRepoServerTLSSecret(cr *argoprojv1a1.ArgoCD)
 		}
 	}
 
-	// The content of the TLS secret has changed since we last looked if the
-	// calculated checksum doesn't match the one stored in the status.
-	if cr.Status.RepoTLSChecksum != sha256sum {
-		// We store the value early to prevent a possible restart loop, for the
-		// cost of a possibly missed restart when we cannot update the status
-		// field of the resource.
-		cr.Status.RepoTLSChecksum = sha256sum
-		err = r.Client.Status().Update(context.TODO(), cr)
-		if err != nil {
-			return err
-		}
-
-		// Trigger rollout of API server
-		apiDepl := newDeploymentWithSuffix("server", "server", cr)
-		err = r.triggerRollout(apiDepl, "repo.tls.cert.changed")
-		if err != nil {
-			return err
-		}
-
-		// Trigger rollout of repository server
-		repoDepl := newDeploymentWithSuffix("repo-server", "repo-server", cr)
-		err = r.triggerRollout(repoDepl, "repo.tls.cert.changed")
-		if err != nil {
-			return err
-		}
-
-		// Trigger rollout of application controller
-		controllerSts := newStatefulSetWithSuffix("application-controller", "application-controller", cr)
-		err = r.triggerRollout(controllerSts, "repo.tls.cert.changed")
-		if err != nil {
-			return err
-		}
-	}
+	// The workloads that mount the TLS secret are rolled out by the config
+	// checksum of their pod templates, the checksum in the status is only
+	// informational.
+	cr.Status.RepoTLSChecksum = sha256sum
 
 	return nil
 }
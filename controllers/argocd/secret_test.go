@@ -20,6 +20,7 @@ import (
 
 	"github.com/argoproj-labs/argocd-operator/api/v1alpha1"
 	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/common"
 	"github.com/argoproj-labs/argocd-operator/controllers/argoutil"
 )
 
@@ -103,16 +104,10 @@ func Test_ReconcileArgoCD_ReconcileRepoTLSSecret(t *testing.T) {
 		sumOver = append(sumOver, crt...)
 		sumOver = append(sumOver, key...)
 		shasum := fmt.Sprintf("%x", sha256.Sum256(sumOver))
-		serverDepl := newDeploymentWithSuffix("server", "server", argocd)
-		repoDepl := newDeploymentWithSuffix("repo-server", "repo-server", argocd)
-		ctrlSts := newStatefulSetWithSuffix("application-controller", "application-controller", argocd)
 		objs := []runtime.Object{
 			argocd,
 			secret,
 			service,
-			serverDepl,
-			repoDepl,
-			ctrlSts,
 		}
 
 		r := makeReconciler(t, argocd, objs...)
@@ -125,23 +120,6 @@ func Test_ReconcileArgoCD_ReconcileRepoTLSSecret(t *testing.T) {
 			t.Errorf("Error in SHA256 sum of secret, want=%s got=%s", shasum, argocd.Status.RepoTLSChecksum)
 		}
 
-		// Workloads should have been requested to re-rollout on a change
-		r.Client.Get(context.TODO(), types.NamespacedName{Name: "argocd-server", Namespace: "argocd-operator"}, serverDepl)
-		deplRollout, ok := serverDepl.Spec.Template.ObjectMeta.Labels["repo.tls.cert.changed"]
-		if !ok {
-			t.Errorf("Expected rollout of argocd-server, but it didn't happen: %v", serverDepl.Spec.Template.ObjectMeta.Labels)
-		}
-		r.Client.Get(context.TODO(), types.NamespacedName{Name: "argocd-repo-server", Namespace: "argocd-operator"}, repoDepl)
-		repoRollout, ok := serverDepl.Spec.Template.ObjectMeta.Labels["repo.tls.cert.changed"]
-		if !ok {
-			t.Errorf("Expected rollout of argocd-repo-server, but it didn't happen: %v", repoDepl.Spec.Template.ObjectMeta.Labels)
-		}
-		r.Client.Get(context.TODO(), types.NamespacedName{Name: "argocd-application-controller", Namespace: "argocd-operator"}, ctrlSts)
-		ctrlRollout, ok := ctrlSts.Spec.Template.ObjectMeta.Labels["repo.tls.cert.changed"]
-		if !ok {
-			t.Errorf("Expected rollout of argocd-application-server, but it didn't happen: %v", ctrlSts.Spec.Template.ObjectMeta.Labels)
-		}
-
 		// Second run - no change
 		err = r.reconcileRepoServerTLSSecret(argocd)
 		if err != nil {
@@ -151,24 +129,7 @@ func Test_ReconcileArgoCD_ReconcileRepoTLSSecret(t *testing.T) {
 			t.Errorf("Error in SHA256 sum of secret, want=%s got=%s", shasum, argocd.Status.RepoTLSChecksum)
 		}
 
-		// This time, label should not have changed
-		r.Client.Get(context.TODO(), types.NamespacedName{Name: "argocd-server", Namespace: "argocd-operator"}, serverDepl)
-		deplRolloutNew, ok := serverDepl.Spec.Template.ObjectMeta.Labels["repo.tls.cert.changed"]
-		if !ok || deplRollout != deplRolloutNew {
-			t.Errorf("Did not expect rollout of argocd-server, but it did happen: %v", serverDepl.Spec.Template.ObjectMeta.Labels)
-		}
-		r.Client.Get(context.TODO(), types.NamespacedName{Name: "argocd-repo-server", Namespace: "argocd-operator"}, repoDepl)
-		repoRolloutNew, ok := serverDepl.Spec.Template.ObjectMeta.Labels["repo.tls.cert.changed"]
-		if !ok || repoRollout != repoRolloutNew {
-			t.Errorf("Did not expect rollout of argocd-repo-server, but it did happen: %v", serverDepl.Spec.Template.ObjectMeta.Labels)
-		}
-		r.Client.Get(context.TODO(), types.NamespacedName{Name: "argocd-application-controller", Namespace: "argocd-operator"}, ctrlSts)
-		ctrlRolloutNew, ok := ctrlSts.Spec.Template.ObjectMeta.Labels["repo.tls.cert.changed"]
-		if !ok || ctrlRollout != ctrlRolloutNew {
-			t.Errorf("Did not expect rollout of argocd-application-server, but it did happen: %v", serverDepl.Spec.Template.ObjectMeta.Labels)
-		}
-
-		// Update certificate in the secret must trigger new rollout
+		// Update certificate in the secret must update the checksum
 		r.Client.Get(context.TODO(), types.NamespacedName{Name: "argocd-repo-server-tls", Namespace: "argocd-operator"}, secret)
 		secret.Data["tls.crt"] = []byte("bar")
 		r.Client.Update(context.TODO(), secret)
@@ -178,7 +139,6 @@ func Test_ReconcileArgoCD_ReconcileRepoTLSSecret(t *testing.T) {
 		sumOver = append(sumOver, key...)
 		shasum = fmt.Sprintf("%x", sha256.Sum256(sumOver))
 
-		// Second run - no change
 		err = r.reconcileRepoServerTLSSecret(argocd)
 		if err != nil {
 			t.Errorf("Error should be nil, but is %v", err)
@@ -186,24 +146,6 @@ func Test_ReconcileArgoCD_ReconcileRepoTLSSecret(t *testing.T) {
 		if shasum != argocd.Status.RepoTLSChecksum {
 			t.Errorf("Error in SHA256 sum of secret, want=%s got=%s", shasum, argocd.Status.RepoTLSChecksum)
 		}
-
-		// This time, label should not have changed
-		r.Client.Get(context.TODO(), types.NamespacedName{Name: "argocd-server", Namespace: "argocd-operator"}, serverDepl)
-		deplRolloutNew, ok = serverDepl.Spec.Template.ObjectMeta.Labels["repo.tls.cert.changed"]
-		if !ok || deplRollout == deplRolloutNew {
-			t.Errorf("Expected rollout of argocd-server, but it didn't happen: %v", serverDepl.Spec.Template.ObjectMeta.Labels)
-		}
-		r.Client.Get(context.TODO(), types.NamespacedName{Name: "argocd-repo-server", Namespace: "argocd-operator"}, repoDepl)
-		repoRolloutNew, ok = serverDepl.Spec.Template.ObjectMeta.Labels["repo.tls.cert.changed"]
-		if !ok || repoRollout == repoRolloutNew {
-			t.Errorf("Expected rollout of argocd-repo-server, but it didn't happen: %v", repoDepl.Spec.Template.ObjectMeta.Labels)
-		}
-		r.Client.Get(context.TODO(), types.NamespacedName{Name: "argocd-application-controller", Namespace: "argocd-operator"}, ctrlSts)
-		ctrlRolloutNew, ok = ctrlSts.Spec.Template.ObjectMeta.Labels["repo.tls.cert.changed"]
-		if !ok || ctrlRollout == ctrlRolloutNew {
-			t.Errorf("Expected rollout of argocd-application-controller, but it didn't happen: %v", ctrlSts.Spec.Template.ObjectMeta.Labels)
-		}
-
 	})
 
 }
@@ -249,3 +191,53 @@ func Test_ReconcileArgoCD_ClusterPermissionsSecret(t *testing.T) {
 	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: testSecret.Name, Namespace: testSecret.Namespace}, testSecret))
 	assert.Nil(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: testSecret.Name, Namespace: testSecret.Namespace}, testSecret))
 }
+
+func TestReconcileArgoCD_reconcileArgoSecret(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+	assert.NoError(t, createNamespace(r, a.Namespace, ""))
+	assert.NoError(t, r.reconcileClusterSecrets(a))
+	assert.NoError(t, r.reconcileArgoSecret(a))
+
+	secret := argoutil.NewSecretWithName(a, common.ArgoCDSecretName)
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, secret.Name, secret))
+	sessionKey := secret.Data[common.ArgoCDKeyServerSecretKey]
+	passwordHash := secret.Data[common.ArgoCDKeyAdminPassword]
+	assert.NotEmpty(t, sessionKey)
+
+	// Drift of the operator keys is corrected, generated values are kept
+	secret.Data[common.ArgoCDKeyTLSCert] = []byte("drift")
+	assert.NoError(t, r.Client.Update(context.TODO(), secret))
+	assert.NoError(t, r.reconcileArgoSecret(a))
+
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, secret.Name, secret))
+	assert.NotEqual(t, "drift", string(secret.Data[common.ArgoCDKeyTLSCert]))
+	assert.Equal(t, sessionKey, secret.Data[common.ArgoCDKeyServerSecretKey])
+	assert.Equal(t, passwordHash, secret.Data[common.ArgoCDKeyAdminPassword])
+
+	// The admin password is rehashed when it is changed in the cluster secret
+	clusterSecret := argoutil.NewSecretWithSuffix(a, "cluster")
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, clusterSecret.Name, clusterSecret))
+	clusterSecret.Data[common.ArgoCDKeyAdminPassword] = []byte("newpassword2021")
+	assert.NoError(t, r.Client.Update(context.TODO(), clusterSecret))
+	assert.NoError(t, r.reconcileArgoSecret(a))
+
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, secret.Name, secret))
+	assert.NotEqual(t, passwordHash, secret.Data[common.ArgoCDKeyAdminPassword])
+	assert.Equal(t, sessionKey, secret.Data[common.ArgoCDKeyServerSecretKey])
+
+	// The Keycloak client secret is kept while Keycloak is used, and removed once it is not
+	secret.Data[common.ArgoCDKeyKeycloakClientSecret] = []byte("client-secret")
+	assert.NoError(t, r.Client.Update(context.TODO(), secret))
+	a.Spec.SSO = &argoprojv1alpha1.ArgoCDSSOSpec{Provider: argoprojv1alpha1.SSOProviderTypeKeycloak}
+	assert.NoError(t, r.reconcileArgoSecret(a))
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, secret.Name, secret))
+	assert.Equal(t, "client-secret", string(secret.Data[common.ArgoCDKeyKeycloakClientSecret]))
+
+	a.Spec.SSO = nil
+	assert.NoError(t, r.reconcileArgoSecret(a))
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, secret.Name, secret))
+	assert.NotContains(t, secret.Data, common.ArgoCDKeyKeycloakClientSecret)
+	assert.Equal(t, sessionKey, secret.Data[common.ArgoCDKeyServerSecretKey])
+}
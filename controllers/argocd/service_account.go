@@ -156,9 +156,15 @@ func (r *ReconcileArgoCD) reconcileServiceAccount(name string, cr *argoprojv1a1.
 			// Delete any existing Service Account created for Dex
 			return sa, r.Client.Delete(context.TODO(), sa)
 		}
+		changed := syncCommonMetadata(cr, sa)
+		if syncImagePullSecrets(cr, sa) || changed {
+			return sa, r.Client.Update(context.TODO(), sa)
+		}
 		return sa, nil
 	}
 
+	syncCommonMetadata(cr, sa)
+	syncImagePullSecrets(cr, sa)
 	if err := controllerutil.SetControllerReference(cr, sa, r.Scheme); err != nil {
 		return nil, err
 	}
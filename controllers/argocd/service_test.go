@@ -7,6 +7,7 @@ import (
 
 	"github.com/argoproj-labs/argocd-operator/common"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -95,3 +96,17 @@ func TestEnsureAutoTLSAnnotation(t *testing.T) {
 		assert.Equal(t, ok, false)
 	})
 }
+
+func TestReconcileArgoCD_reconcileServerService_update(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+	assert.NoError(t, r.reconcileServerService(a))
+
+	a.Spec.Server.Service.Type = corev1.ServiceTypeLoadBalancer
+	assert.NoError(t, r.reconcileServerService(a))
+
+	s := newServiceWithSuffix("server", "server", a)
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Namespace: s.Namespace, Name: s.Name}, s))
+	assert.Equal(t, corev1.ServiceTypeLoadBalancer, s.Spec.Type)
+}
@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	argov1alpha1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/controllers/argoutil"
 	oappsv1 "github.com/openshift/api/apps/v1"
 	routev1 "github.com/openshift/api/route/v1"
 	templatev1 "github.com/openshift/api/template/v1"
@@ -47,7 +48,7 @@ func makeFakeReconciler(t *testing.T, acd *argov1alpha1.ArgoCD, objs ...runtime.
 
 	cl := fake.NewFakeClientWithScheme(s, objs...)
 	return &ReconcileArgoCD{
-		Client: cl,
+		Client: newApplyClient(cl),
 		Scheme: s,
 	}
 }
@@ -139,6 +140,8 @@ func TestReconcile_testKeycloakInstanceResources(t *testing.T) {
 	}
 	assert.Equal(t, deployment.Spec.Template.Spec.Containers[0].Env,
 		testEnv)
+	assert.Equal(t, argoutil.RestrictedSecurityContext(false), deployment.Spec.Template.Spec.Containers[0].SecurityContext)
+	assert.True(t, *deployment.Spec.Template.Spec.SecurityContext.RunAsNonRoot)
 
 	// Keycloak Service
 	svc := &corev1.Service{}
@@ -17,15 +17,12 @@ package argocd
 import (
 	"context"
 	"fmt"
-	"reflect"
-	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
 	"github.com/argoproj-labs/argocd-operator/common"
@@ -90,32 +87,12 @@ func newStatefulSetWithSuffix(suffix string, component string, cr *argoprojv1a1.
 func (r *ReconcileArgoCD) reconcileRedisStatefulSet(cr *argoprojv1a1.ArgoCD) error {
 	ss := newStatefulSetWithSuffix("redis-ha-server", "redis", cr)
 
-	existing := newStatefulSetWithSuffix("redis-ha-server", "redis", cr)
-	if argoutil.IsObjectFound(r.Client, cr.Namespace, existing.Name, existing) {
-		if !cr.Spec.HA.Enabled {
+	if !cr.Spec.HA.Enabled {
+		existing := newStatefulSetWithSuffix("redis-ha-server", "redis", cr)
+		if argoutil.IsObjectFound(r.Client, cr.Namespace, existing.Name, existing) {
 			// StatefulSet exists but HA enabled flag has been set to false, delete the StatefulSet
 			return r.Client.Delete(context.TODO(), existing)
 		}
-
-		desiredImage := getRedisHAContainerImage(cr)
-		changed := false
-		updateNodePlacementStateful(existing, ss, &changed)
-		for i, container := range existing.Spec.Template.Spec.Containers {
-			if container.Image != desiredImage {
-				existing.Spec.Template.Spec.Containers[i].Image = getRedisHAContainerImage(cr)
-				existing.Spec.Template.ObjectMeta.Labels["image.upgraded"] = time.Now().UTC().Format("01022006-150406-MST")
-				changed = true
-			}
-		}
-
-		if changed {
-			return r.Client.Update(context.TODO(), existing)
-		}
-
-		return nil // StatefulSet found, do nothing
-	}
-
-	if !cr.Spec.HA.Enabled {
 		return nil // HA not enabled, do nothing.
 	}
 
@@ -140,6 +117,9 @@ func (r *ReconcileArgoCD) reconcileRedisStatefulSet(cr *argoprojv1a1.ArgoCD) err
 
 	ss.Spec.Template.Spec.Affinity = &corev1.Affinity{
 		PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				zoneAntiAffinityTerm(nameWithSuffix("redis-ha", cr)),
+			},
 			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
 				LabelSelector: &metav1.LabelSelector{
 					MatchLabels: map[string]string{
@@ -163,7 +143,7 @@ func (r *ReconcileArgoCD) reconcileRedisStatefulSet(cr *argoprojv1a1.ArgoCD) err
 				"redis-server",
 			},
 			Image:           getRedisHAContainerImage(cr),
-			ImagePullPolicy: corev1.PullIfNotPresent,
+			ImagePullPolicy: getImagePullPolicy(cr, cr.Spec.Redis.ImagePullPolicy, getRedisHAContainerImage(cr), corev1.PullIfNotPresent),
 			LivenessProbe: &corev1.Probe{
 				Handler: corev1.Handler{
 					Exec: &corev1.ExecAction{
@@ -221,7 +201,7 @@ func (r *ReconcileArgoCD) reconcileRedisStatefulSet(cr *argoprojv1a1.ArgoCD) err
 				"redis-sentinel",
 			},
 			Image:           getRedisHAContainerImage(cr),
-			ImagePullPolicy: corev1.PullIfNotPresent,
+			ImagePullPolicy: getImagePullPolicy(cr, cr.Spec.Redis.ImagePullPolicy, getRedisHAContainerImage(cr), corev1.PullIfNotPresent),
 			LivenessProbe: &corev1.Probe{
 				Handler: corev1.Handler{
 					Exec: &corev1.ExecAction{
@@ -295,7 +275,7 @@ func (r *ReconcileArgoCD) reconcileRedisStatefulSet(cr *argoprojv1a1.ArgoCD) err
 			},
 		},
 		Image:           getRedisHAContainerImage(cr),
-		ImagePullPolicy: corev1.PullIfNotPresent,
+		ImagePullPolicy: getImagePullPolicy(cr, cr.Spec.Redis.ImagePullPolicy, getRedisHAContainerImage(cr), corev1.PullIfNotPresent),
 		Name:            "config-init",
 		Resources:       getRedisResources(cr),
 		VolumeMounts: []corev1.VolumeMount{
@@ -365,10 +345,21 @@ func (r *ReconcileArgoCD) reconcileRedisStatefulSet(cr *argoprojv1a1.ArgoCD) err
 		return err
 	}
 
-	if err := controllerutil.SetControllerReference(cr, ss, r.Scheme); err != nil {
+	applyScheduling(&ss.Spec.Template, cr.Spec.Redis.Scheduling)
+
+	if err := argoutil.ApplySecurityContext(&ss.Spec.Template.Spec, true, cr.Spec.Redis.SecurityContext); err != nil {
+		return err
+	}
+
+	if err := applyPodTemplateOverride(&ss.Spec.Template, cr.Spec.Redis.PodTemplateOverride); err != nil {
+		return err
+	}
+
+	if err := r.setConfigChecksum(cr, &ss.Spec.Template, getRedisHAConfigInputs(cr)); err != nil {
 		return err
 	}
-	return r.Client.Create(context.TODO(), ss)
+
+	return r.applyResource(cr, ss)
 }
 
 func getArgoControllerContainerEnv(cr *argoprojv1a1.ArgoCD) []corev1.EnvVar {
@@ -398,11 +389,16 @@ func (r *ReconcileArgoCD) reconcileApplicationControllerStatefulSet(cr *argoproj
 	controllerEnv = argoutil.EnvMerge(controllerEnv, getArgoControllerContainerEnv(cr), true)
 	// Let user specify their own environment first
 	controllerEnv = argoutil.EnvMerge(controllerEnv, proxyEnvVars(), false)
+	controllerCommand := getArgoApplicationControllerCommand(cr)
+	if isRepoServerTLSVerificationRequested(cr) {
+		controllerCommand = append(controllerCommand, "--repo-server-strict-tls")
+	}
+	controllerImage := getArgoComponentContainerImage(cr, "application-controller")
 	podSpec := &ss.Spec.Template.Spec
 	podSpec.Containers = []corev1.Container{{
-		Command:         getArgoApplicationControllerCommand(cr),
-		Image:           getArgoContainerImage(cr),
-		ImagePullPolicy: corev1.PullAlways,
+		Command:         controllerCommand,
+		Image:           controllerImage,
+		ImagePullPolicy: getImagePullPolicy(cr, cr.Spec.Controller.ImagePullPolicy, controllerImage),
 		Name:            "argocd-application-controller",
 		LivenessProbe: &corev1.Probe{
 			Handler: corev1.Handler{
@@ -477,6 +473,12 @@ func (r *ReconcileArgoCD) reconcileApplicationControllerStatefulSet(cr *argoproj
 				}},
 		},
 	}
+	if cr.Spec.HA.Enabled {
+		// Spread the application controllers across zones
+		podAntiAffinity := ss.Spec.Template.Spec.Affinity.PodAntiAffinity
+		podAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(podAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			zoneAntiAffinityTerm(ss.Name))
+	}
 
 	// Handle import/restore from ArgoCDExport
 	export := r.getArgoCDExport(cr)
@@ -487,9 +489,10 @@ func (r *ReconcileArgoCD) reconcileApplicationControllerStatefulSet(cr *argoproj
 			Command:         getArgoImportCommand(r.Client, cr),
 			Env:             proxyEnvVars(getArgoImportContainerEnv(export)...),
 			Resources:       getArgoApplicationControllerResources(cr),
-			Image:           getArgoImportContainerImage(export),
-			ImagePullPolicy: corev1.PullAlways,
+			Image:           getArgoImportContainerImage(cr, export),
+			ImagePullPolicy: getImagePullPolicy(cr, export.Spec.ImagePullPolicy, getArgoImportContainerImage(cr, export)),
 			Name:            "argocd-import",
+			SecurityContext: getArgoImportSecurityContext(),
 			VolumeMounts:    getArgoImportVolumeMounts(),
 		}}
 
@@ -503,101 +506,30 @@ func (r *ReconcileArgoCD) reconcileApplicationControllerStatefulSet(cr *argoproj
 		}
 	}
 
-	existing := newStatefulSetWithSuffix("application-controller", "application-controller", cr)
-	if argoutil.IsObjectFound(r.Client, cr.Namespace, existing.Name, existing) {
-		actualImage := existing.Spec.Template.Spec.Containers[0].Image
-		desiredImage := getArgoContainerImage(cr)
-		changed := false
-		if actualImage != desiredImage {
-			existing.Spec.Template.Spec.Containers[0].Image = desiredImage
-			existing.Spec.Template.ObjectMeta.Labels["image.upgraded"] = time.Now().UTC().Format("01022006-150406-MST")
-			changed = true
-		}
-		desiredCommand := getArgoApplicationControllerCommand(cr)
-		if isRepoServerTLSVerificationRequested(cr) {
-			desiredCommand = append(desiredCommand, "--repo-server-strict-tls")
-		}
-		updateNodePlacementStateful(existing, ss, &changed)
-		if !reflect.DeepEqual(desiredCommand, existing.Spec.Template.Spec.Containers[0].Command) {
-			existing.Spec.Template.Spec.Containers[0].Command = desiredCommand
-			changed = true
-		}
-
-		if !reflect.DeepEqual(existing.Spec.Template.Spec.Containers[0].Env,
-			ss.Spec.Template.Spec.Containers[0].Env) {
-			existing.Spec.Template.Spec.Containers[0].Env = ss.Spec.Template.Spec.Containers[0].Env
-			changed = true
-		}
-		if !reflect.DeepEqual(ss.Spec.Template.Spec.Volumes, existing.Spec.Template.Spec.Volumes) {
-			existing.Spec.Template.Spec.Volumes = ss.Spec.Template.Spec.Volumes
-			changed = true
-		}
-		if !reflect.DeepEqual(ss.Spec.Template.Spec.Containers[0].VolumeMounts,
-			existing.Spec.Template.Spec.Containers[0].VolumeMounts) {
-			existing.Spec.Template.Spec.Containers[0].VolumeMounts = ss.Spec.Template.Spec.Containers[0].VolumeMounts
-			changed = true
-		}
-		if !reflect.DeepEqual(ss.Spec.Template.Spec.Containers[0].Resources, existing.Spec.Template.Spec.Containers[0].Resources) {
-			existing.Spec.Template.Spec.Containers[0].Resources = ss.Spec.Template.Spec.Containers[0].Resources
-			changed = true
-		}
-		if !reflect.DeepEqual(ss.Spec.Replicas, existing.Spec.Replicas) {
-			existing.Spec.Replicas = ss.Spec.Replicas
-			changed = true
-		}
-
-		if changed {
-			return r.Client.Update(context.TODO(), existing)
-		}
-		return nil // StatefulSet found with nothing to do, move along...
-	}
-
-	// Delete existing deployment for Application Controller, if any ..
-	deploy := newDeploymentWithSuffix("application-controller", "application-controller", cr)
-	if argoutil.IsObjectFound(r.Client, deploy.Namespace, deploy.Name, deploy) {
-		if err := r.Client.Delete(context.TODO(), deploy); err != nil {
-			return err
-		}
-	}
+	applyScheduling(&ss.Spec.Template, cr.Spec.Controller.Scheduling)
 
-	if err := controllerutil.SetControllerReference(cr, ss, r.Scheme); err != nil {
+	if err := argoutil.ApplySecurityContext(&ss.Spec.Template.Spec, true, cr.Spec.Controller.SecurityContext); err != nil {
 		return err
 	}
-	return r.Client.Create(context.TODO(), ss)
-}
 
-// reconcileStatefulSets will ensure that all StatefulSets are present for the given ArgoCD.
-func (r *ReconcileArgoCD) reconcileStatefulSets(cr *argoprojv1a1.ArgoCD) error {
-	if err := r.reconcileApplicationControllerStatefulSet(cr); err != nil {
-		return err
-	}
-	if err := r.reconcileRedisStatefulSet(cr); err != nil {
+	if err := applyPodTemplateOverride(&ss.Spec.Template, cr.Spec.Controller.PodTemplateOverride); err != nil {
 		return err
 	}
-	return nil
-}
 
-// triggerStatefulSetRollout will update the label with the given key to trigger a new rollout of the StatefulSet.
-func (r *ReconcileArgoCD) triggerStatefulSetRollout(sts *appsv1.StatefulSet, key string) error {
-	if !argoutil.IsObjectFound(r.Client, sts.Namespace, sts.Name, sts) {
-		log.Info(fmt.Sprintf("unable to locate deployment with name: %s", sts.Name))
-		return nil
+	if err := r.setConfigChecksum(cr, &ss.Spec.Template, getApplicationControllerConfigInputs(cr)); err != nil {
+		return err
 	}
 
-	sts.Spec.Template.ObjectMeta.Labels[key] = nowNano()
-	return r.Client.Update(context.TODO(), sts)
+	return r.applyResource(cr, ss)
 }
 
-//to update nodeSelector and tolerations in reconciler
-func updateNodePlacementStateful(existing *appsv1.StatefulSet, ss *appsv1.StatefulSet, changed *bool) {
-	if !reflect.DeepEqual(existing.Spec.Template.Spec.NodeSelector, ss.Spec.Template.Spec.NodeSelector) {
-		existing.Spec.Template.Spec.NodeSelector = ss.Spec.Template.Spec.NodeSelector
-		*changed = true
-	}
-	if !reflect.DeepEqual(existing.Spec.Template.Spec.Tolerations, ss.Spec.Template.Spec.Tolerations) {
-		existing.Spec.Template.Spec.Tolerations = ss.Spec.Template.Spec.Tolerations
-		*changed = true
-	}
+// reconcileStatefulSets will ensure that all StatefulSets are present for the given ArgoCD.
+func (r *ReconcileArgoCD) reconcileStatefulSets(cr *argoprojv1a1.ArgoCD) error {
+	// Every StatefulSet is reconciled even if the other one failed.
+	return utilerrors.NewAggregate([]error{
+		r.reconcileApplicationControllerStatefulSet(cr),
+		r.reconcileRedisStatefulSet(cr),
+	})
 }
 
 // Returns true if a StatefulSet has pods in ErrImagePull or ImagePullBackoff state.
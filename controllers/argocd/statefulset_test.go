@@ -169,6 +169,7 @@ func TestReconcileArgoCD_reconcileApplicationController_withUpgrade(t *testing.T
 	deploy := newDeploymentWithSuffix("application-controller", "application-controller", a)
 	assert.NoError(t, r.Client.Create(context.TODO(), deploy))
 
+	assert.NoError(t, r.reconcileSchemaMigrations(a))
 	assert.NoError(t, r.reconcileApplicationControllerStatefulSet(a))
 	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: deploy.Name, Namespace: deploy.Namespace}, deploy)
 	assert.Errorf(t, err, "not found")
@@ -203,6 +204,10 @@ func TestReconcileArgoCD_reconcileApplicationController_withResources(t *testing
 		},
 		ss))
 
+	// The util image runs as the argocd user, which is not numeric
+	assert.Len(t, ss.Spec.Template.Spec.InitContainers, 1)
+	assert.Equal(t, int64(999), *ss.Spec.Template.Spec.InitContainers[0].SecurityContext.RunAsUser)
+
 	testResources := corev1.ResourceRequirements{
 		Requests: corev1.ResourceList{
 			corev1.ResourceMemory: resourcev1.MustParse("1024Mi"),
@@ -307,63 +312,43 @@ func TestReconcileArgoCD_reconcileApplicationController_withSharding(t *testing.
 	}
 }
 
-func Test_UpdateNodePlacementStateful(t *testing.T) {
+func TestReconcileArgoCD_reconcileApplicationController_nodePlacementUpdate(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD()
+	r := makeTestReconciler(t, a)
+	assert.NoError(t, r.reconcileApplicationControllerStatefulSet(a))
 
-	ss := &appsv1.StatefulSet{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "argocd-sample-server",
-			Namespace: testNamespace,
-		},
-		Spec: appsv1.StatefulSetSpec{
-			Template: corev1.PodTemplateSpec{
-				Spec: corev1.PodSpec{
-					NodeSelector: map[string]string{
-						"test_key1": "test_value1",
-						"test_key2": "test_value2",
-					},
-					Tolerations: []corev1.Toleration{
-						{
-							Key:    "test_key1",
-							Value:  "test_value1",
-							Effect: corev1.TaintEffectNoSchedule,
-						},
-					},
-				},
-			},
-		},
-	}
-	ss2 := &appsv1.StatefulSet{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      "argocd-sample-server",
-			Namespace: testNamespace,
-		},
-		Spec: appsv1.StatefulSetSpec{
-			Template: corev1.PodTemplateSpec{
-				Spec: corev1.PodSpec{
-					NodeSelector: map[string]string{
-						"test_key1": "test_value1",
-					},
-					Tolerations: []corev1.Toleration{
-						{
-							Key:    "test_key1",
-							Value:  "test_value1",
-							Effect: corev1.TaintEffectNoExecute,
-						},
-					},
-				},
-			},
-		},
-	}
-	expectedChange := false
-	actualChange := false
-	updateNodePlacementStateful(ss, ss, &actualChange)
-	if actualChange != expectedChange {
-		t.Fatalf("updateNodePlacement failed, value of changed: %t", actualChange)
-	}
-	updateNodePlacementStateful(ss, ss2, &actualChange)
-	if actualChange == expectedChange {
-		t.Fatalf("updateNodePlacement failed, value of changed: %t", actualChange)
+	a.Spec.NodePlacement = &argoprojv1alpha1.ArgoCDNodePlacementSpec{
+		NodeSelector: deploymentDefaultNodeSelector(),
+		Tolerations:  deploymentDefaultTolerations(),
 	}
+	assert.NoError(t, r.reconcileApplicationControllerStatefulSet(a))
+
+	ss := &appsv1.StatefulSet{}
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: "argocd-application-controller", Namespace: a.Namespace}, ss))
+	assert.Equal(t, deploymentDefaultNodeSelector(), ss.Spec.Template.Spec.NodeSelector)
+	assert.Equal(t, deploymentDefaultTolerations(), ss.Spec.Template.Spec.Tolerations)
+
+	a.Spec.NodePlacement = nil
+	assert.NoError(t, r.reconcileApplicationControllerStatefulSet(a))
+
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: "argocd-application-controller", Namespace: a.Namespace}, ss))
+	assert.Nil(t, ss.Spec.Template.Spec.NodeSelector)
+	assert.Nil(t, ss.Spec.Template.Spec.Tolerations)
+}
+
+func TestReconcileArgoCD_reconcileApplicationController_repoServerStrictTLS(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Repo.VerifyTLS = true
+	})
+	r := makeTestReconciler(t, a)
+
+	assert.NoError(t, r.reconcileApplicationControllerStatefulSet(a))
+
+	ss := &appsv1.StatefulSet{}
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: "argocd-application-controller", Namespace: a.Namespace}, ss))
+	assert.Contains(t, ss.Spec.Template.Spec.Containers[0].Command, "--repo-server-strict-tls")
 }
 
 func Test_ContainsValidImage(t *testing.T) {
@@ -16,18 +16,24 @@ package argocd
 
 import (
 	"context"
+	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 
 	routev1 "github.com/openshift/api/route/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
 	"github.com/argoproj-labs/argocd-operator/controllers/argoutil"
 )
 
-// reconcileStatus will ensure that all of the Status properties are updated for the given ArgoCD.
-func (r *ReconcileArgoCD) reconcileStatus(cr *argoprojv1a1.ArgoCD) error {
+// reconcileStatus will compute all of the Status properties for the given ArgoCD in memory, including the status
+// conditions based on the error returned by the last reconciliation, if any. The status is written by updateStatus.
+func (r *ReconcileArgoCD) reconcileStatus(cr *argoprojv1a1.ArgoCD, reconcileErr error) error {
 	if err := r.reconcileStatusApplicationController(cr); err != nil {
 		return err
 	}
@@ -60,9 +66,51 @@ func (r *ReconcileArgoCD) reconcileStatus(cr *argoprojv1a1.ArgoCD) error {
 		return err
 	}
 
+	r.reconcileStatusDriftedResources(cr)
+	reconcileStatusImages(cr)
+	setStatusConditions(cr, reconcileErr)
+	cr.Status.ObservedGeneration = cr.Generation
+
 	return nil
 }
 
+// reconcileStatusImages will set the container images of the enabled components of the given ArgoCD in its status.
+func reconcileStatusImages(cr *argoprojv1a1.ArgoCD) {
+	images := map[string]string{
+		"applicationController": getComponentMainContainerImage(cr, "application-controller"),
+		"repoServer":            getComponentMainContainerImage(cr, "repo-server"),
+		"server":                getComponentMainContainerImage(cr, "server"),
+	}
+	if !isDexDisabled() {
+		images["dex"] = getDexContainerImage(cr)
+	}
+	if cr.Spec.HA.Enabled {
+		images["redis"] = getRedisHAContainerImage(cr)
+		images["redisHAProxy"] = getRedisHAProxyContainerImage(cr)
+	} else {
+		images["redis"] = getRedisContainerImage(cr)
+	}
+	if cr.Spec.Grafana.Enabled {
+		images["grafana"] = getGrafanaContainerImage(cr)
+	}
+	if cr.Spec.ApplicationSet != nil {
+		images["applicationSet"] = getApplicationSetContainerImage(cr)
+	}
+	if cr.Spec.SSO != nil && cr.Spec.SSO.Provider == argoprojv1a1.SSOProviderTypeKeycloak {
+		images["keycloak"] = getKeycloakContainerImage(cr)
+	}
+	cr.Status.Images = images
+}
+
+// updateStatus will write the Status of the given ArgoCD with a single patch, if it differs from the Status of the
+// given original ArgoCD.
+func (r *ReconcileArgoCD) updateStatus(cr *argoprojv1a1.ArgoCD, original *argoprojv1a1.ArgoCD) error {
+	if reflect.DeepEqual(original.Status, cr.Status) {
+		return nil
+	}
+	return r.Client.Status().Patch(context.TODO(), cr, client.MergeFrom(original))
+}
+
 // reconcileStatusApplicationController will ensure that the ApplicationController Status is updated for the given ArgoCD.
 func (r *ReconcileArgoCD) reconcileStatusApplicationController(cr *argoprojv1a1.ArgoCD) error {
 	status := "Unknown"
@@ -78,10 +126,7 @@ func (r *ReconcileArgoCD) reconcileStatusApplicationController(cr *argoprojv1a1.
 		}
 	}
 
-	if cr.Status.ApplicationController != status {
-		cr.Status.ApplicationController = status
-		return r.Client.Status().Update(context.TODO(), cr)
-	}
+	cr.Status.ApplicationController = status
 	return nil
 }
 
@@ -100,10 +145,7 @@ func (r *ReconcileArgoCD) reconcileStatusDex(cr *argoprojv1a1.ArgoCD) error {
 		}
 	}
 
-	if cr.Status.Dex != status {
-		cr.Status.Dex = status
-		return r.Client.Status().Update(context.TODO(), cr)
-	}
+	cr.Status.Dex = status
 	return nil
 }
 
@@ -119,10 +161,7 @@ func (r *ReconcileArgoCD) reconcileStatusSSOConfig(cr *argoprojv1a1.ArgoCD) erro
 		status = "Success"
 	}
 
-	if cr.Status.SSOConfig != status {
-		cr.Status.SSOConfig = status
-		return r.Client.Status().Update(context.TODO(), cr)
-	}
+	cr.Status.SSOConfig = status
 	return nil
 }
 
@@ -136,10 +175,7 @@ func (r *ReconcileArgoCD) reconcileStatusPhase(cr *argoprojv1a1.ArgoCD) error {
 		phase = "Pending"
 	}
 
-	if cr.Status.Phase != phase {
-		cr.Status.Phase = phase
-		return r.Client.Status().Update(context.TODO(), cr)
-	}
+	cr.Status.Phase = phase
 	return nil
 }
 
@@ -170,10 +206,7 @@ func (r *ReconcileArgoCD) reconcileStatusRedis(cr *argoprojv1a1.ArgoCD) error {
 		// TODO: Add check for HA proxy deployment here as well?
 	}
 
-	if cr.Status.Redis != status {
-		cr.Status.Redis = status
-		return r.Client.Status().Update(context.TODO(), cr)
-	}
+	cr.Status.Redis = status
 	return nil
 }
 
@@ -192,10 +225,7 @@ func (r *ReconcileArgoCD) reconcileStatusRepo(cr *argoprojv1a1.ArgoCD) error {
 		}
 	}
 
-	if cr.Status.Repo != status {
-		cr.Status.Repo = status
-		return r.Client.Status().Update(context.TODO(), cr)
-	}
+	cr.Status.Repo = status
 	return nil
 }
 
@@ -215,10 +245,7 @@ func (r *ReconcileArgoCD) reconcileStatusServer(cr *argoprojv1a1.ArgoCD) error {
 		}
 	}
 
-	if cr.Status.Server != status {
-		cr.Status.Server = status
-		return r.Client.Status().Update(context.TODO(), cr)
-	}
+	cr.Status.Server = status
 	return nil
 }
 
@@ -286,5 +313,119 @@ func (r *ReconcileArgoCD) reconcileStatusHost(cr *argoprojv1a1.ArgoCD) error {
 			}
 		}
 	}
-	return r.Client.Status().Update(context.TODO(), cr)
+	return nil
+}
+
+// setStatusConditions will set the status conditions of the given ArgoCD.
+func setStatusConditions(cr *argoprojv1a1.ArgoCD, reconcileErr error) {
+	setComponentCondition(cr, argoprojv1a1.ArgoCDConditionApplicationControllerReady, "application controller", cr.Status.ApplicationController)
+	setComponentCondition(cr, argoprojv1a1.ArgoCDConditionRedisReady, "redis", cr.Status.Redis)
+	setComponentCondition(cr, argoprojv1a1.ArgoCDConditionRepoServerReady, "repo server", cr.Status.Repo)
+	setComponentCondition(cr, argoprojv1a1.ArgoCDConditionServerReady, "server", cr.Status.Server)
+
+	if isDexDisabled() {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, argoprojv1a1.ArgoCDConditionDexReady)
+	} else {
+		setComponentCondition(cr, argoprojv1a1.ArgoCDConditionDexReady, "dex server", cr.Status.Dex)
+	}
+
+	switch cr.Status.SSOConfig {
+	case "Success":
+		setCondition(cr, argoprojv1a1.ArgoCDConditionSSOConfigured, metav1.ConditionTrue,
+			argoprojv1a1.ArgoCDReasonSingleSSOProvider, "A single SSO provider is configured")
+	case "Failed":
+		setCondition(cr, argoprojv1a1.ArgoCDConditionSSOConfigured, metav1.ConditionFalse,
+			argoprojv1a1.ArgoCDReasonMultipleSSOProviders, "Both Keycloak and Dex are configured, only one SSO provider is allowed")
+	default:
+		setCondition(cr, argoprojv1a1.ArgoCDConditionSSOConfigured, metav1.ConditionFalse,
+			argoprojv1a1.ArgoCDReasonNoSSOProvider, "No SSO provider is configured")
+	}
+
+	if reconcileErr != nil {
+		setCondition(cr, argoprojv1a1.ArgoCDConditionReconcileError, metav1.ConditionTrue,
+			argoprojv1a1.ArgoCDReasonReconcileFailed, reconcileErr.Error())
+	} else {
+		setCondition(cr, argoprojv1a1.ArgoCDConditionReconcileError, metav1.ConditionFalse,
+			argoprojv1a1.ArgoCDReasonReconcileSucceeded, "")
+	}
+
+	notReady := []string{}
+	for component, status := range map[string]string{
+		"application controller": cr.Status.ApplicationController,
+		"redis":                  cr.Status.Redis,
+		"repo server":            cr.Status.Repo,
+		"server":                 cr.Status.Server,
+	} {
+		if status != "Running" {
+			notReady = append(notReady, component)
+		}
+	}
+	sort.Strings(notReady)
+
+	if len(notReady) == 0 {
+		setCondition(cr, argoprojv1a1.ArgoCDConditionAvailable, metav1.ConditionTrue,
+			argoprojv1a1.ArgoCDReasonComponentsReady, "All components are ready")
+		setCondition(cr, argoprojv1a1.ArgoCDConditionProgressing, metav1.ConditionFalse,
+			argoprojv1a1.ArgoCDReasonComponentsReady, "All components are ready")
+	} else {
+		msg := fmt.Sprintf("Waiting for components to become ready: %s", strings.Join(notReady, ", "))
+		setCondition(cr, argoprojv1a1.ArgoCDConditionAvailable, metav1.ConditionFalse,
+			argoprojv1a1.ArgoCDReasonComponentsNotReady, msg)
+		if reconcileErr != nil {
+			setCondition(cr, argoprojv1a1.ArgoCDConditionProgressing, metav1.ConditionFalse,
+				argoprojv1a1.ArgoCDReasonReconcileFailed, reconcileErr.Error())
+		} else {
+			setCondition(cr, argoprojv1a1.ArgoCDConditionProgressing, metav1.ConditionTrue,
+				argoprojv1a1.ArgoCDReasonComponentsNotReady, msg)
+		}
+	}
+
+	if isUpgradeInProgress(cr) && reconcileErr == nil {
+		setCondition(cr, argoprojv1a1.ArgoCDConditionProgressing, metav1.ConditionTrue,
+			argoprojv1a1.ArgoCDReasonUpgradeInProgress, cr.Status.Upgrade.Message)
+	}
+
+	switch {
+	case reconcileErr != nil:
+		setCondition(cr, argoprojv1a1.ArgoCDConditionDegraded, metav1.ConditionTrue,
+			argoprojv1a1.ArgoCDReasonReconcileFailed, reconcileErr.Error())
+	case isUpgradeManaged(cr) && cr.Status.Upgrade != nil && cr.Status.Upgrade.Phase == argoprojv1a1.ArgoCDUpgradePhaseRolledBack:
+		setCondition(cr, argoprojv1a1.ArgoCDConditionDegraded, metav1.ConditionTrue,
+			argoprojv1a1.ArgoCDReasonUpgradeRolledBack, cr.Status.Upgrade.Message)
+	case isUpgradeManaged(cr) && cr.Status.Upgrade != nil && cr.Status.Upgrade.Phase == argoprojv1a1.ArgoCDUpgradePhaseBlocked:
+		setCondition(cr, argoprojv1a1.ArgoCDConditionDegraded, metav1.ConditionTrue,
+			argoprojv1a1.ArgoCDReasonUpgradeBlocked, cr.Status.Upgrade.Message)
+	case cr.Status.SSOConfig == "Failed":
+		setCondition(cr, argoprojv1a1.ArgoCDConditionDegraded, metav1.ConditionTrue,
+			argoprojv1a1.ArgoCDReasonMultipleSSOProviders, "Both Keycloak and Dex are configured, only one SSO provider is allowed")
+	default:
+		setCondition(cr, argoprojv1a1.ArgoCDConditionDegraded, metav1.ConditionFalse,
+			argoprojv1a1.ArgoCDReasonAsExpected, "")
+	}
+}
+
+// setComponentCondition will set the readiness condition of the given type from the status of a component.
+func setComponentCondition(cr *argoprojv1a1.ArgoCD, conditionType string, component string, status string) {
+	switch status {
+	case "Running":
+		setCondition(cr, conditionType, metav1.ConditionTrue, argoprojv1a1.ArgoCDReasonComponentsReady,
+			fmt.Sprintf("The %s is ready", component))
+	case "Pending":
+		setCondition(cr, conditionType, metav1.ConditionFalse, argoprojv1a1.ArgoCDReasonComponentsNotReady,
+			fmt.Sprintf("The %s is not ready", component))
+	default:
+		setCondition(cr, conditionType, metav1.ConditionUnknown, argoprojv1a1.ArgoCDReasonComponentsNotReady,
+			fmt.Sprintf("The state of the %s could not be obtained", component))
+	}
+}
+
+// setCondition will set the condition of the given type for the current generation of the given ArgoCD.
+func setCondition(cr *argoprojv1a1.ArgoCD, conditionType string, status metav1.ConditionStatus, reason string, message string) {
+	meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: cr.Generation,
+	})
 }
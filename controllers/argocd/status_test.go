@@ -2,6 +2,8 @@ package argocd
 
 import (
 	"context"
+	"errors"
+	"os"
 	"testing"
 
 	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
@@ -10,8 +12,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -162,3 +166,119 @@ func TestReconcileArgoCD_reconcileStatusHost(t *testing.T) {
 		})
 	}
 }
+
+func TestSetStatusConditions(t *testing.T) {
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Generation = 3
+		a.Status.ApplicationController = "Running"
+		a.Status.Redis = "Running"
+		a.Status.Repo = "Pending"
+		a.Status.Server = "Running"
+	})
+
+	setStatusConditions(a, nil)
+
+	available := meta.FindStatusCondition(a.Status.Conditions, argoprojv1alpha1.ArgoCDConditionAvailable)
+	assert.Equal(t, metav1.ConditionFalse, available.Status)
+	assert.Equal(t, argoprojv1alpha1.ArgoCDReasonComponentsNotReady, available.Reason)
+	assert.Contains(t, available.Message, "repo server")
+	assert.Equal(t, int64(3), available.ObservedGeneration)
+
+	assert.True(t, meta.IsStatusConditionTrue(a.Status.Conditions, argoprojv1alpha1.ArgoCDConditionProgressing))
+	assert.True(t, meta.IsStatusConditionFalse(a.Status.Conditions, argoprojv1alpha1.ArgoCDConditionDegraded))
+	assert.True(t, meta.IsStatusConditionFalse(a.Status.Conditions, argoprojv1alpha1.ArgoCDConditionReconcileError))
+	assert.True(t, meta.IsStatusConditionFalse(a.Status.Conditions, argoprojv1alpha1.ArgoCDConditionRepoServerReady))
+	assert.True(t, meta.IsStatusConditionTrue(a.Status.Conditions, argoprojv1alpha1.ArgoCDConditionServerReady))
+	assert.True(t, meta.IsStatusConditionPresentAndEqual(a.Status.Conditions, argoprojv1alpha1.ArgoCDConditionDexReady, metav1.ConditionUnknown))
+
+	a.Status.Repo = "Running"
+	setStatusConditions(a, nil)
+	assert.True(t, meta.IsStatusConditionTrue(a.Status.Conditions, argoprojv1alpha1.ArgoCDConditionAvailable))
+	assert.True(t, meta.IsStatusConditionFalse(a.Status.Conditions, argoprojv1alpha1.ArgoCDConditionProgressing))
+}
+
+func TestSetStatusConditions_reconcileError(t *testing.T) {
+	a := makeTestArgoCD()
+
+	setStatusConditions(a, errors.New("failed to create deployment"))
+
+	reconcileError := meta.FindStatusCondition(a.Status.Conditions, argoprojv1alpha1.ArgoCDConditionReconcileError)
+	assert.Equal(t, metav1.ConditionTrue, reconcileError.Status)
+	assert.Equal(t, argoprojv1alpha1.ArgoCDReasonReconcileFailed, reconcileError.Reason)
+	assert.Equal(t, "failed to create deployment", reconcileError.Message)
+
+	assert.True(t, meta.IsStatusConditionTrue(a.Status.Conditions, argoprojv1alpha1.ArgoCDConditionDegraded))
+	assert.True(t, meta.IsStatusConditionFalse(a.Status.Conditions, argoprojv1alpha1.ArgoCDConditionProgressing))
+}
+
+func TestSetStatusConditions_sso(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCDForKeycloakWithDex()
+	r := makeTestReconciler(t, a)
+
+	assert.NoError(t, r.reconcileStatusSSOConfig(a))
+	setStatusConditions(a, nil)
+
+	sso := meta.FindStatusCondition(a.Status.Conditions, argoprojv1alpha1.ArgoCDConditionSSOConfigured)
+	assert.Equal(t, metav1.ConditionFalse, sso.Status)
+	assert.Equal(t, argoprojv1alpha1.ArgoCDReasonMultipleSSOProviders, sso.Reason)
+	assert.True(t, meta.IsStatusConditionTrue(a.Status.Conditions, argoprojv1alpha1.ArgoCDConditionDegraded))
+}
+
+func TestSetStatusConditions_dexDisabled(t *testing.T) {
+	restoreEnv(t)
+	os.Setenv("DISABLE_DEX", "true")
+
+	a := makeTestArgoCD()
+	setStatusConditions(a, nil)
+
+	assert.Nil(t, meta.FindStatusCondition(a.Status.Conditions, argoprojv1alpha1.ArgoCDConditionDexReady))
+}
+
+func TestReconcileStatusImages(t *testing.T) {
+	restoreEnv(t)
+	os.Setenv("DISABLE_DEX", "true")
+
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Repo.Version = "v2.3.0"
+		a.Spec.HA.Enabled = true
+	})
+	reconcileStatusImages(a)
+
+	assert.Equal(t, map[string]string{
+		"applicationController": getArgoContainerImage(a),
+		"repoServer":            getRepoServerContainerImage(a),
+		"server":                getArgoContainerImage(a),
+		"redis":                 getRedisHAContainerImage(a),
+		"redisHAProxy":          getRedisHAProxyContainerImage(a),
+	}, a.Status.Images)
+	assert.Contains(t, a.Status.Images["repoServer"], "v2.3.0")
+}
+
+func TestReconcileArgoCD_updateStatus(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Generation = 2
+	})
+	r := makeTestReconciler(t, a)
+
+	original := a.DeepCopy()
+	assert.NoError(t, r.reconcileStatus(a, nil))
+	assert.Equal(t, "Unknown", a.Status.Server)
+	assert.NoError(t, r.updateStatus(a, original))
+
+	got := &argoprojv1alpha1.ArgoCD{}
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: a.Name, Namespace: a.Namespace}, got))
+	assert.Equal(t, "Unknown", got.Status.Server)
+	assert.Equal(t, int64(2), got.Status.ObservedGeneration)
+	assert.True(t, meta.IsStatusConditionFalse(got.Status.Conditions, argoprojv1alpha1.ArgoCDConditionAvailable))
+
+	// nothing is written when the status did not change
+	original = got.DeepCopy()
+	assert.NoError(t, r.reconcileStatus(got, nil))
+	assert.NoError(t, r.updateStatus(got, original))
+
+	unchanged := &argoprojv1alpha1.ArgoCD{}
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: a.Name, Namespace: a.Namespace}, unchanged))
+	assert.Equal(t, original.ResourceVersion, unchanged.ResourceVersion)
+}
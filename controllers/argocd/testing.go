@@ -16,6 +16,8 @@ package argocd
 
 import (
 	"context"
+	"fmt"
+	"reflect"
 	"sort"
 	"testing"
 
@@ -27,10 +29,13 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	resourcev1 "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
@@ -55,11 +60,105 @@ func makeTestReconciler(t *testing.T, objs ...runtime.Object) *ReconcileArgoCD {
 
 	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objs...).Build()
 	return &ReconcileArgoCD{
-		Client: cl,
+		Client: newApplyClient(cl),
 		Scheme: s,
 	}
 }
 
+// applyClient emulates server-side apply on top of the fake client, which does not support apply patches. Like the API
+// server, the applied configuration is merged into the existing object: fields that were applied before but are no
+// longer in the applied configuration are removed and fields that were set by others are left alone. Lists are treated
+// as atomic and are replaced as a whole.
+type applyClient struct {
+	client.Client
+
+	// applied is the last applied configuration of each object, keyed by the GVK, namespace and name.
+	applied map[string]map[string]interface{}
+}
+
+func newApplyClient(cl client.Client) *applyClient {
+	return &applyClient{Client: cl, applied: make(map[string]map[string]interface{})}
+}
+
+func (c *applyClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if patch.Type() != types.ApplyPatchType {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	}
+
+	key := fmt.Sprintf("%s/%s/%s", obj.GetObjectKind().GroupVersionKind(), obj.GetNamespace(), obj.GetName())
+	config, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+
+	existing := obj.DeepCopyObject().(client.Object)
+	if err := c.Client.Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		if err := c.Client.Create(ctx, obj); err != nil {
+			return err
+		}
+		c.applied[key] = config
+		return nil
+	}
+
+	merged, err := runtime.DefaultUnstructuredConverter.ToUnstructured(existing)
+	if err != nil {
+		return err
+	}
+	pruneFields(merged, c.applied[key], config)
+	mergeFields(merged, config)
+
+	resourceVersion := existing.GetResourceVersion()
+	v := reflect.ValueOf(obj).Elem()
+	v.Set(reflect.Zero(v.Type()))
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(merged, obj); err != nil {
+		return err
+	}
+	obj.SetResourceVersion(resourceVersion)
+	if err := c.Client.Update(ctx, obj); err != nil {
+		return err
+	}
+	c.applied[key] = config
+	return nil
+}
+
+// pruneFields will remove the fields of the last applied configuration that are not in the given configuration from
+// the given object.
+func pruneFields(obj, last, config map[string]interface{}) {
+	for k, lv := range last {
+		cv, ok := config[k]
+		lm, lok := lv.(map[string]interface{})
+		om, ook := obj[k].(map[string]interface{})
+		if !lok || !ook {
+			if !ok {
+				delete(obj, k)
+			}
+			continue
+		}
+
+		cm, _ := cv.(map[string]interface{})
+		pruneFields(om, lm, cm)
+		if !ok && len(om) == 0 {
+			delete(obj, k)
+		}
+	}
+}
+
+// mergeFields will set the fields of the given configuration on the given object, maps are merged recursively.
+func mergeFields(obj, config map[string]interface{}) {
+	for k, cv := range config {
+		cm, cok := cv.(map[string]interface{})
+		om, ook := obj[k].(map[string]interface{})
+		if cok && ook {
+			mergeFields(om, cm)
+			continue
+		}
+		obj[k] = cv
+	}
+}
+
 type argoCDOpt func(*argoprojv1alpha1.ArgoCD)
 
 func makeTestArgoCD(opts ...argoCDOpt) *argoprojv1alpha1.ArgoCD {
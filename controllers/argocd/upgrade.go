@@ -0,0 +1,545 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/common"
+	"github.com/argoproj-labs/argocd-operator/controllers/argoutil"
+	"github.com/argoproj-labs/argocd-operator/version"
+)
+
+// argoCDCompatibility lists the minor versions of Argo CD that are supported by each minor release of the operator.
+var argoCDCompatibility = map[string][]string{
+	"0.4": {"v2.1", "v2.2", "v2.3"},
+}
+
+// latestOperatorRelease is the operator release whose supported versions are used when the version of the running
+// operator is unknown, e.g. for development builds.
+const latestOperatorRelease = "0.4"
+
+// deprecatedConfigKey describes a key of the argocd-cm ConfigMap that was deprecated or removed by Argo CD.
+type deprecatedConfigKey struct {
+	key string
+
+	// deprecated is the minor version that deprecated the key.
+	deprecated string
+
+	// removed is the minor version that removed the key, if any.
+	removed string
+
+	// replacement describes what to use instead of the key.
+	replacement string
+}
+
+// deprecatedConfigKeys lists the keys of the argocd-cm ConfigMap that were deprecated or removed by Argo CD.
+var deprecatedConfigKeys = []deprecatedConfigKey{
+	{
+		key:         "repositories",
+		deprecated:  "v2.1",
+		replacement: "Secrets labeled with argocd.argoproj.io/secret-type=repository",
+	},
+	{
+		key:         "repository.credentials",
+		deprecated:  "v2.1",
+		replacement: "Secrets labeled with argocd.argoproj.io/secret-type=repo-creds",
+	},
+	{
+		key:         "configManagementPlugins",
+		deprecated:  "v2.6",
+		removed:     "v2.8",
+		replacement: "sidecar config management plugins",
+	},
+}
+
+// upgradeOrder lists the components that the new version is rolled out to during a managed upgrade, in order.
+var upgradeOrder = []string{"repo-server", "application-controller", "server"}
+
+// argoCDImageDigests maps the digests of the default Argo CD images of the operator releases to the versions that they
+// refer to, so that the versions of the digests recorded by earlier releases can still be determined. Add the digest
+// of the new default image when it changes, and never remove an entry.
+var argoCDImageDigests = map[string]string{
+	"sha256:dd738f234fcdb0aac8631a0fd1aafbbcd86f936480b06e8377b033ef7a764f71": "v2.3.3",
+}
+
+// failedPodReasons are the reasons of waiting containers that cannot become ready without intervention. A container
+// in CrashLoopBackOff only counts as failed once it restarted common.ArgoCDUpgradeRestartThreshold times.
+var failedPodReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"CreateContainerConfigError": true,
+	"ErrImagePull":               true,
+	"ImagePullBackOff":           true,
+	"InvalidImageName":           true,
+}
+
+var versionRegexp = regexp.MustCompile(`^v?(\d+)\.(\d+)(?:\.\d+)?(?:[-+].*)?$`)
+
+// argoVersion is the major and minor version of Argo CD or the operator.
+type argoVersion struct {
+	major int
+	minor int
+}
+
+func (v argoVersion) String() string {
+	return fmt.Sprintf("v%d.%d", v.major, v.minor)
+}
+
+// parseArgoVersion will parse the major and minor version of the given version, e.g. v2.3.3. The digests of the
+// default container images are resolved to the versions they refer to.
+func parseArgoVersion(v string) (argoVersion, bool) {
+	if tag, ok := argoCDImageDigests[v]; ok {
+		v = tag
+	}
+
+	match := versionRegexp.FindStringSubmatch(v)
+	if match == nil {
+		return argoVersion{}, false
+	}
+	major, _ := strconv.Atoi(match[1])
+	minor, _ := strconv.Atoi(match[2])
+	return argoVersion{major: major, minor: minor}, true
+}
+
+// getArgoImageVersion will return the Argo CD version of the given container image, i.e. its tag, or the version that
+// its digest refers to if it is the digest of a default image. Any other digest is returned as is.
+func getArgoImageVersion(image string) string {
+	_, tag := argoutil.SplitImageTag(image)
+	if tag == "" {
+		return "latest"
+	}
+	if version, ok := argoCDImageDigests[tag]; ok {
+		return version
+	}
+	return tag
+}
+
+// supportedArgoVersions will return the minor versions of Argo CD that are supported by the running operator.
+func supportedArgoVersions() []string {
+	if v, ok := parseArgoVersion(version.Version); ok {
+		if versions, ok := argoCDCompatibility[fmt.Sprintf("%d.%d", v.major, v.minor)]; ok {
+			return versions
+		}
+	}
+	return argoCDCompatibility[latestOperatorRelease]
+}
+
+// getDesiredArgoImage will return the Argo CD container image requested by the given ArgoCD, from its spec, the
+// environment of the operator or the defaults, before the image registry of the ArgoCD is applied.
+func getDesiredArgoImage(cr *argoprojv1a1.ArgoCD) string {
+	return argoutil.ContainerImage(cr.Spec.Image, cr.Spec.Version,
+		common.ArgoCDDefaultArgoImage, common.ArgoCDDefaultArgoVersion,
+		common.ArgoCDImageEnvName, common.ArgoCDRelatedImageEnvName)
+}
+
+// getDesiredArgoVersion will return the Argo CD version of the container image requested by the given ArgoCD.
+func getDesiredArgoVersion(cr *argoprojv1a1.ArgoCD) string {
+	return getArgoImageVersion(getDesiredArgoImage(cr))
+}
+
+// isUpgradeManaged returns true if the given ArgoCD opted in to managed upgrades.
+func isUpgradeManaged(cr *argoprojv1a1.ArgoCD) bool {
+	return cr.Spec.Upgrade != nil && cr.Spec.Upgrade.Managed
+}
+
+// isUpgradeInProgress returns true if a managed upgrade of the given ArgoCD is rolling out a new version.
+func isUpgradeInProgress(cr *argoprojv1a1.ArgoCD) bool {
+	return isUpgradeManaged(cr) && cr.Status.Upgrade != nil &&
+		cr.Status.Upgrade.Phase == argoprojv1a1.ArgoCDUpgradePhaseProgressing
+}
+
+// getUpgradeProgressDeadline will return the time that a component of the given ArgoCD has to become ready with a new
+// version.
+func getUpgradeProgressDeadline(cr *argoprojv1a1.ArgoCD) time.Duration {
+	if cr.Spec.Upgrade != nil && cr.Spec.Upgrade.ProgressDeadline != nil {
+		return cr.Spec.Upgrade.ProgressDeadline.Duration
+	}
+	return common.ArgoCDUpgradeProgressDeadline
+}
+
+// getPreviousArgoImage will return the Argo CD container image that the given component of the given ArgoCD keeps
+// while a managed upgrade has not reached the component yet, or after it was blocked or rolled back. An empty string
+// means that the component is rolled out with the image requested by the ArgoCD.
+func getPreviousArgoImage(cr *argoprojv1a1.ArgoCD, component string) string {
+	upgrade := cr.Status.Upgrade
+	if !isUpgradeManaged(cr) || upgrade == nil || cr.Status.Image == "" {
+		return ""
+	}
+
+	switch upgrade.Phase {
+	case argoprojv1a1.ArgoCDUpgradePhaseProgressing:
+		if upgradeIndex(component) <= upgradeIndex(upgrade.Component) {
+			return ""
+		}
+	case argoprojv1a1.ArgoCDUpgradePhaseBlocked, argoprojv1a1.ArgoCDUpgradePhaseRolledBack:
+		// All components keep the previous image.
+	default:
+		return ""
+	}
+	return cr.Status.Image
+}
+
+// upgradeIndex will return the position of the given component in the rollout order of managed upgrades. Components
+// that run the Argo CD image but are not part of the order, e.g. Dex, are upgraded after the last one.
+func upgradeIndex(component string) int {
+	for i, c := range upgradeOrder {
+		if c == component {
+			return i
+		}
+	}
+	return len(upgradeOrder)
+}
+
+// getArgoComponentContainerImage will return the Argo CD container image for the given component, taking the progress
+// of a managed upgrade into account. Every container that runs the Argo CD image must use it, so that the image is
+// only changed by the ordered rollout and rolled back with it.
+func getArgoComponentContainerImage(cr *argoprojv1a1.ArgoCD, component string) string {
+	if image := getPreviousArgoImage(cr, component); image != "" {
+		return mirrorImage(cr, image)
+	}
+	return getArgoContainerImage(cr)
+}
+
+// getComponentMainContainerImage will return the container image of the main container of the given core Argo CD
+// component, i.e. the Argo CD image chosen by a managed upgrade, unless the repo server has an image of its own.
+func getComponentMainContainerImage(cr *argoprojv1a1.ArgoCD, component string) string {
+	if component == "repo-server" && (cr.Spec.Repo.Image != "" || cr.Spec.Repo.Version != "") {
+		return getRepoServerContainerImage(cr)
+	}
+	return getArgoComponentContainerImage(cr, component)
+}
+
+// reconcileUpgrade will drive a managed upgrade of the given ArgoCD to the version in its spec. The new version is
+// rolled out to one component at a time, the next component is only upgraded once the previous one is ready, and all
+// components are rolled back to the previous version if one fails to become ready.
+func (r *ReconcileArgoCD) reconcileUpgrade(cr *argoprojv1a1.ArgoCD) error {
+	desired := getDesiredArgoImage(cr)
+
+	if !isUpgradeManaged(cr) {
+		// Without managed upgrades the images are swapped in place.
+		setArgoImageStatus(cr, desired)
+		cr.Status.Upgrade = nil
+		return nil
+	}
+
+	if cr.Status.Image == "" {
+		setArgoImageStatus(cr, desired)
+		return nil
+	}
+
+	upgrade := cr.Status.Upgrade
+	if upgrade != nil && upgrade.ToImage == desired {
+		switch upgrade.Phase {
+		case argoprojv1a1.ArgoCDUpgradePhaseProgressing:
+			return r.progressUpgrade(cr)
+		case argoprojv1a1.ArgoCDUpgradePhaseRolledBack:
+			// The image is not retried until it is changed.
+			return nil
+		}
+	}
+
+	if desired == cr.Status.Image {
+		// A failed upgrade was reverted, or an upgrade in progress was cancelled.
+		if upgrade != nil && upgrade.Phase != argoprojv1a1.ArgoCDUpgradePhaseSucceeded {
+			cr.Status.Upgrade = nil
+		}
+		return nil
+	}
+
+	return r.startUpgrade(cr, desired)
+}
+
+// setArgoImageStatus will record the given Argo CD container image and its version in the status of the given ArgoCD,
+// as the image that all of the core components were rolled out with.
+func setArgoImageStatus(cr *argoprojv1a1.ArgoCD, image string) {
+	cr.Status.Image = image
+	cr.Status.Version = getArgoImageVersion(image)
+}
+
+// startUpgrade will run the pre-flight checks for the upgrade of the given ArgoCD to the given image, and start rolling
+// it out if they pass.
+func (r *ReconcileArgoCD) startUpgrade(cr *argoprojv1a1.ArgoCD, desired string) error {
+	from, to := cr.Status.Version, getArgoImageVersion(desired)
+
+	if !cr.Spec.Upgrade.SkipPreflightChecks {
+		failures, err := r.checkUpgradePreflight(cr, from, to)
+		if err != nil {
+			return err
+		}
+		if len(failures) > 0 {
+			setUpgradeStatus(cr, argoprojv1a1.ArgoCDUpgradePhaseBlocked, cr.Status.Image, desired, "",
+				fmt.Sprintf("The upgrade from %s to %s is blocked: %s", from, to, strings.Join(failures, "; ")))
+			return nil
+		}
+	}
+
+	msg := fmt.Sprintf("Upgrading from %s to %s", from, to)
+	if warnings := r.getDeprecatedConfigKeys(cr, to); len(warnings) > 0 {
+		msg = fmt.Sprintf("%s, %s", msg, strings.Join(warnings, "; "))
+	}
+	log.Info(msg, "namespace", cr.Namespace, "name", cr.Name)
+	setUpgradeStatus(cr, argoprojv1a1.ArgoCDUpgradePhaseProgressing, cr.Status.Image, desired, upgradeOrder[0], msg)
+	return nil
+}
+
+// progressUpgrade will move a managed upgrade of the given ArgoCD on to the next component once the current one is
+// ready, or roll it back if the current component failed to become ready.
+func (r *ReconcileArgoCD) progressUpgrade(cr *argoprojv1a1.ArgoCD) error {
+	upgrade := cr.Status.Upgrade
+
+	ready, failure, err := r.getUpgradedComponentState(cr, upgrade.Component)
+	if err != nil {
+		return err
+	}
+
+	if ready {
+		next := upgradeIndex(upgrade.Component) + 1
+		if next < len(upgradeOrder) {
+			setUpgradeStatus(cr, upgrade.Phase, upgrade.FromImage, upgrade.ToImage, upgradeOrder[next], upgrade.Message)
+			return nil
+		}
+
+		log.Info(fmt.Sprintf("upgraded to %s", upgrade.ToVersion), "namespace", cr.Namespace, "name", cr.Name)
+		setArgoImageStatus(cr, upgrade.ToImage)
+		setUpgradeStatus(cr, argoprojv1a1.ArgoCDUpgradePhaseSucceeded, upgrade.FromImage, upgrade.ToImage, "",
+			fmt.Sprintf("Upgraded from %s to %s", upgrade.FromVersion, upgrade.ToVersion))
+		return nil
+	}
+
+	if failure == "" && metav1.Now().Sub(upgrade.LastTransitionTime.Time) > getUpgradeProgressDeadline(cr) {
+		failure = fmt.Sprintf("the %s did not become ready within %s", upgrade.Component, getUpgradeProgressDeadline(cr))
+	}
+	if failure != "" {
+		msg := fmt.Sprintf("The upgrade from %s to %s was rolled back: %s", upgrade.FromVersion, upgrade.ToVersion, failure)
+		log.Info(msg, "namespace", cr.Namespace, "name", cr.Name)
+		setUpgradeStatus(cr, argoprojv1a1.ArgoCDUpgradePhaseRolledBack, upgrade.FromImage, upgrade.ToImage, "", msg)
+	}
+	return nil
+}
+
+// setUpgradeStatus will set the status of the managed upgrade of the given ArgoCD between the given images, the
+// transition time is only updated if the phase or the component changed.
+func setUpgradeStatus(cr *argoprojv1a1.ArgoCD, phase string, from string, to string, component string, msg string) {
+	upgrade := &argoprojv1a1.ArgoCDUpgradeStatus{
+		Phase:              phase,
+		FromImage:          from,
+		FromVersion:        getArgoImageVersion(from),
+		ToImage:            to,
+		ToVersion:          getArgoImageVersion(to),
+		Component:          component,
+		Message:            msg,
+		LastTransitionTime: metav1.Now(),
+	}
+
+	if existing := cr.Status.Upgrade; existing != nil && existing.Phase == phase && existing.Component == component &&
+		existing.ToImage == to {
+		upgrade.LastTransitionTime = existing.LastTransitionTime
+	}
+	cr.Status.Upgrade = upgrade
+}
+
+// checkUpgradePreflight will return the reasons that prevent the given ArgoCD from being upgraded between the given
+// versions, if any.
+func (r *ReconcileArgoCD) checkUpgradePreflight(cr *argoprojv1a1.ArgoCD, from string, to string) ([]string, error) {
+	fromVersion, ok := parseArgoVersion(from)
+	if !ok {
+		return []string{fmt.Sprintf("cannot determine the Argo CD version of %s", from)}, nil
+	}
+	toVersion, ok := parseArgoVersion(to)
+	if !ok {
+		return []string{fmt.Sprintf("cannot determine the Argo CD version of %s", to)}, nil
+	}
+
+	failures := []string{}
+
+	supported := supportedArgoVersions()
+	if !containsString(supported, toVersion.String()) {
+		failures = append(failures, fmt.Sprintf("%s is not supported by this version of the operator, supported versions are %s",
+			toVersion, strings.Join(supported, ", ")))
+	}
+
+	switch {
+	case toVersion.major != fromVersion.major:
+		failures = append(failures, fmt.Sprintf("upgrading from %s to %s across major versions is not supported", fromVersion, toVersion))
+	case toVersion.minor < fromVersion.minor:
+		failures = append(failures, fmt.Sprintf("downgrading from %s to %s is not supported", fromVersion, toVersion))
+	case toVersion.minor > fromVersion.minor+1:
+		failures = append(failures, fmt.Sprintf("upgrading from %s to %s skips a minor version, upgrade to v%d.%d first",
+			fromVersion, toVersion, fromVersion.major, fromVersion.minor+1))
+	}
+
+	data, err := r.getArgoConfigData(cr)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range deprecatedConfigKeys {
+		removed, ok := parseArgoVersion(key.removed)
+		if !ok || data[key.key] == "" || !isVersionReached(toVersion, removed) {
+			continue
+		}
+		failures = append(failures, fmt.Sprintf("the argocd-cm key %s was removed in %s, use %s instead", key.key, key.removed, key.replacement))
+	}
+
+	return failures, nil
+}
+
+// getDeprecatedConfigKeys will return a warning for every key of the argocd-cm ConfigMap of the given ArgoCD that is
+// deprecated in the given version.
+func (r *ReconcileArgoCD) getDeprecatedConfigKeys(cr *argoprojv1a1.ArgoCD, to string) []string {
+	toVersion, ok := parseArgoVersion(to)
+	if !ok {
+		return nil
+	}
+
+	data, err := r.getArgoConfigData(cr)
+	if err != nil {
+		log.Error(err, "failed to get the argocd-cm ConfigMap")
+		return nil
+	}
+
+	warnings := []string{}
+	for _, key := range deprecatedConfigKeys {
+		deprecated, _ := parseArgoVersion(key.deprecated)
+		if data[key.key] == "" || !isVersionReached(toVersion, deprecated) {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("the argocd-cm key %s is deprecated since %s, use %s instead", key.key, key.deprecated, key.replacement))
+	}
+	return warnings
+}
+
+// isVersionReached returns true if the given version is the same as or later than the given milestone.
+func isVersionReached(v argoVersion, milestone argoVersion) bool {
+	return v.major > milestone.major || (v.major == milestone.major && v.minor >= milestone.minor)
+}
+
+// getArgoConfigData will return the data of the argocd-cm ConfigMap of the given ArgoCD, if it exists.
+func (r *ReconcileArgoCD) getArgoConfigData(cr *argoprojv1a1.ArgoCD) (map[string]string, error) {
+	cm := newConfigMapWithName(common.ArgoCDConfigMapName, cr)
+	if err := argoutil.FetchObject(r.Client, cr.Namespace, cm.Name, cm); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return cm.Data, nil
+}
+
+// getUpgradedComponentState will return whether the workload of the given component has been rolled out with the
+// version that the given ArgoCD is upgraded to and is ready, or why its pods failed to become ready.
+func (r *ReconcileArgoCD) getUpgradedComponentState(cr *argoprojv1a1.ArgoCD, component string) (bool, string, error) {
+	image := getComponentMainContainerImage(cr, component)
+
+	var obj client.Object
+	var ready bool
+	if component == "application-controller" {
+		ss := newStatefulSetWithSuffix(component, component, cr)
+		obj = ss
+		if err := argoutil.FetchObject(r.Client, cr.Namespace, ss.Name, ss); err != nil {
+			if errors.IsNotFound(err) {
+				return false, "", nil
+			}
+			return false, "", err
+		}
+		ready = isStatefulSetRolledOut(ss, image)
+	} else {
+		deploy := newDeploymentWithSuffix(component, component, cr)
+		obj = deploy
+		if err := argoutil.FetchObject(r.Client, cr.Namespace, deploy.Name, deploy); err != nil {
+			if errors.IsNotFound(err) {
+				return false, "", nil
+			}
+			return false, "", err
+		}
+		ready = isDeploymentRolledOut(deploy, image)
+	}
+	if ready {
+		return true, "", nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.Client.List(context.TODO(), pods, client.InNamespace(cr.Namespace),
+		client.MatchingLabels{common.ArgoCDKeyName: obj.GetName()}); err != nil {
+		return false, "", err
+	}
+	for _, pod := range pods.Items {
+		if reason := getFailedPodReason(&pod, image); reason != "" {
+			return false, fmt.Sprintf("the pod %s of the %s failed with %s", pod.Name, component, reason), nil
+		}
+	}
+	return false, "", nil
+}
+
+// isDeploymentRolledOut returns true if all replicas of the given Deployment are updated, ready and run the given
+// image.
+func isDeploymentRolledOut(deploy *appsv1.Deployment, image string) bool {
+	replicas := int32(1)
+	if deploy.Spec.Replicas != nil {
+		replicas = *deploy.Spec.Replicas
+	}
+	return len(deploy.Spec.Template.Spec.Containers) > 0 &&
+		deploy.Spec.Template.Spec.Containers[0].Image == image &&
+		deploy.Status.ObservedGeneration >= deploy.Generation &&
+		deploy.Status.UpdatedReplicas == replicas &&
+		deploy.Status.ReadyReplicas == replicas
+}
+
+// isStatefulSetRolledOut returns true if all replicas of the given StatefulSet are updated, ready and run the given
+// image.
+func isStatefulSetRolledOut(ss *appsv1.StatefulSet, image string) bool {
+	replicas := int32(1)
+	if ss.Spec.Replicas != nil {
+		replicas = *ss.Spec.Replicas
+	}
+	return len(ss.Spec.Template.Spec.Containers) > 0 &&
+		ss.Spec.Template.Spec.Containers[0].Image == image &&
+		ss.Status.ObservedGeneration >= ss.Generation &&
+		ss.Status.UpdatedReplicas == replicas &&
+		ss.Status.ReadyReplicas == replicas
+}
+
+// getFailedPodReason will return the reason that a container of the given pod, that runs the given image, cannot
+// become ready without intervention, if any.
+func getFailedPodReason(pod *corev1.Pod, image string) string {
+	if len(pod.Spec.Containers) == 0 || pod.Spec.Containers[0].Image != image {
+		// Pods of the previous version are not relevant to the upgrade.
+		return ""
+	}
+
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if status.State.Waiting == nil || !failedPodReasons[status.State.Waiting.Reason] {
+			continue
+		}
+		if status.State.Waiting.Reason == "CrashLoopBackOff" && status.RestartCount < common.ArgoCDUpgradeRestartThreshold {
+			// A container may crash a few times while e.g. the components it depends on restart.
+			continue
+		}
+		return status.State.Waiting.Reason
+	}
+	return ""
+}
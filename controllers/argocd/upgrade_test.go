@@ -0,0 +1,337 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/common"
+	"github.com/argoproj-labs/argocd-operator/controllers/argoutil"
+)
+
+func makeTestArgoCDWithManagedUpgrade(version string) *argoprojv1alpha1.ArgoCD {
+	return makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Version = version
+		a.Spec.Upgrade = &argoprojv1alpha1.ArgoCDUpgradeSpec{Managed: true}
+	})
+}
+
+// markDeploymentReady will report all replicas of the given Deployment as updated and ready.
+func markDeploymentReady(t *testing.T, r *ReconcileArgoCD, name string) {
+	deploy := &appsv1.Deployment{}
+	assert.NoError(t, argoutil.FetchObject(r.Client, testNamespace, name, deploy))
+	deploy.Status.UpdatedReplicas = 1
+	deploy.Status.ReadyReplicas = 1
+	assert.NoError(t, r.Client.Update(context.TODO(), deploy))
+}
+
+func TestReconcileArgoCD_reconcileUpgrade(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCDWithManagedUpgrade("v2.2.5")
+	r := makeTestReconciler(t, a)
+
+	// The version of a new instance is recorded without an upgrade
+	assert.NoError(t, r.reconcileUpgrade(a))
+	assert.Equal(t, "v2.2.5", a.Status.Version)
+	assert.Equal(t, "quay.io/argoproj/argocd:v2.2.5", a.Status.Image)
+	assert.Nil(t, a.Status.Upgrade)
+
+	a.Spec.Version = "v2.3.3"
+	assert.NoError(t, r.reconcileUpgrade(a))
+	assert.Equal(t, argoprojv1alpha1.ArgoCDUpgradePhaseProgressing, a.Status.Upgrade.Phase)
+	assert.Equal(t, "repo-server", a.Status.Upgrade.Component)
+	assert.Equal(t, "v2.2.5", a.Status.Upgrade.FromVersion)
+	assert.Equal(t, "v2.3.3", a.Status.Upgrade.ToVersion)
+	assert.Equal(t, "quay.io/argoproj/argocd:v2.3.3", a.Status.Upgrade.ToImage)
+	assert.True(t, isUpgradeInProgress(a))
+
+	// The components that the upgrade has not reached yet keep the previous version
+	assert.Equal(t, "quay.io/argoproj/argocd:v2.3.3", getArgoComponentContainerImage(a, "repo-server"))
+	assert.Equal(t, "quay.io/argoproj/argocd:v2.2.5", getArgoComponentContainerImage(a, "application-controller"))
+	assert.Equal(t, "quay.io/argoproj/argocd:v2.2.5", getArgoComponentContainerImage(a, "server"))
+	assert.Equal(t, "quay.io/argoproj/argocd:v2.2.5", getArgoComponentContainerImage(a, "dex-server"))
+
+	// The upgrade waits for the component to become ready
+	assert.NoError(t, r.reconcileRepoDeployment(a))
+	assert.NoError(t, r.reconcileUpgrade(a))
+	assert.Equal(t, "repo-server", a.Status.Upgrade.Component)
+
+	markDeploymentReady(t, r, nameWithSuffix("repo-server", a))
+	assert.NoError(t, r.reconcileUpgrade(a))
+	assert.Equal(t, "application-controller", a.Status.Upgrade.Component)
+
+	assert.NoError(t, r.reconcileApplicationControllerStatefulSet(a))
+	ss := &appsv1.StatefulSet{}
+	assert.NoError(t, argoutil.FetchObject(r.Client, a.Namespace, nameWithSuffix("application-controller", a), ss))
+	assert.Equal(t, "quay.io/argoproj/argocd:v2.3.3", ss.Spec.Template.Spec.Containers[0].Image)
+	ss.Status.UpdatedReplicas = 1
+	ss.Status.ReadyReplicas = 1
+	assert.NoError(t, r.Client.Update(context.TODO(), ss))
+	assert.NoError(t, r.reconcileUpgrade(a))
+	assert.Equal(t, "server", a.Status.Upgrade.Component)
+
+	assert.NoError(t, r.reconcileServerDeployment(a))
+	markDeploymentReady(t, r, nameWithSuffix("server", a))
+	assert.NoError(t, r.reconcileUpgrade(a))
+	assert.Equal(t, argoprojv1alpha1.ArgoCDUpgradePhaseSucceeded, a.Status.Upgrade.Phase)
+	assert.Equal(t, "v2.3.3", a.Status.Version)
+	assert.Equal(t, "quay.io/argoproj/argocd:v2.3.3", a.Status.Image)
+	assert.Equal(t, "quay.io/argoproj/argocd:v2.3.3", getArgoComponentContainerImage(a, "dex-server"))
+	assert.False(t, isUpgradeInProgress(a))
+}
+
+func TestReconcileArgoCD_reconcileUpgrade_image(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCDWithManagedUpgrade("")
+	r := makeTestReconciler(t, a)
+
+	// The default image is recorded with the version that its digest refers to
+	assert.NoError(t, r.reconcileUpgrade(a))
+	assert.Equal(t, "quay.io/argoproj/argocd@"+common.ArgoCDDefaultArgoVersion, a.Status.Image)
+	assert.Equal(t, argoCDImageDigests[common.ArgoCDDefaultArgoVersion], a.Status.Version)
+
+	// A different image is rolled out by an upgrade
+	a.Spec.Image = "registry.example.com/argocd"
+	a.Spec.Version = a.Status.Version
+	assert.NoError(t, r.reconcileUpgrade(a))
+	assert.Equal(t, argoprojv1alpha1.ArgoCDUpgradePhaseProgressing, a.Status.Upgrade.Phase)
+	assert.Equal(t, "quay.io/argoproj/argocd@"+common.ArgoCDDefaultArgoVersion, getArgoComponentContainerImage(a, "server"))
+
+	// So is an image from the environment of the operator
+	a.Spec.Image = ""
+	a.Spec.Version = ""
+	old := os.Getenv(common.ArgoCDImageEnvName)
+	t.Cleanup(func() {
+		os.Setenv(common.ArgoCDImageEnvName, old)
+	})
+	os.Setenv(common.ArgoCDImageEnvName, "registry.example.com/argocd:v2.3.4")
+	assert.NoError(t, r.reconcileUpgrade(a))
+	assert.Equal(t, argoprojv1alpha1.ArgoCDUpgradePhaseProgressing, a.Status.Upgrade.Phase)
+	assert.Equal(t, "registry.example.com/argocd:v2.3.4", a.Status.Upgrade.ToImage)
+	assert.Equal(t, "quay.io/argoproj/argocd@"+common.ArgoCDDefaultArgoVersion, getArgoComponentContainerImage(a, "server"))
+}
+
+func TestReconcileArgoCD_reconcileUpgrade_rollback(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCDWithManagedUpgrade("v2.3.3")
+	setArgoImageStatus(a, "quay.io/argoproj/argocd:v2.2.5")
+	r := makeTestReconciler(t, a)
+
+	assert.NoError(t, r.reconcileUpgrade(a))
+	assert.NoError(t, r.reconcileRepoDeployment(a))
+
+	// A pod of the new version that cannot start rolls the upgrade back
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "argocd-repo-server-abcde",
+			Namespace: a.Namespace,
+			Labels:    map[string]string{common.ArgoCDKeyName: nameWithSuffix("repo-server", a)},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "argocd-repo-server", Image: "quay.io/argoproj/argocd:v2.3.3"}},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "argocd-repo-server",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}},
+			}},
+		},
+	}
+	assert.NoError(t, r.Client.Create(context.TODO(), pod))
+
+	assert.NoError(t, r.reconcileUpgrade(a))
+	assert.Equal(t, argoprojv1alpha1.ArgoCDUpgradePhaseRolledBack, a.Status.Upgrade.Phase)
+	assert.Contains(t, a.Status.Upgrade.Message, "ImagePullBackOff")
+	assert.Equal(t, "v2.2.5", a.Status.Version)
+	assert.Equal(t, "quay.io/argoproj/argocd:v2.2.5", getArgoComponentContainerImage(a, "repo-server"))
+
+	// The version is not retried until it is changed
+	assert.NoError(t, r.reconcileUpgrade(a))
+	assert.Equal(t, argoprojv1alpha1.ArgoCDUpgradePhaseRolledBack, a.Status.Upgrade.Phase)
+
+	setStatusConditions(a, nil)
+	degraded := meta.FindStatusCondition(a.Status.Conditions, argoprojv1alpha1.ArgoCDConditionDegraded)
+	assert.Equal(t, metav1.ConditionTrue, degraded.Status)
+	assert.Equal(t, argoprojv1alpha1.ArgoCDReasonUpgradeRolledBack, degraded.Reason)
+
+	// Reverting the version clears the failed upgrade
+	a.Spec.Version = "v2.2.5"
+	assert.NoError(t, r.reconcileUpgrade(a))
+	assert.Nil(t, a.Status.Upgrade)
+}
+
+func TestReconcileArgoCD_reconcileUpgrade_crashLoop(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCDWithManagedUpgrade("v2.3.3")
+	setArgoImageStatus(a, "quay.io/argoproj/argocd:v2.2.5")
+	r := makeTestReconciler(t, a)
+
+	assert.NoError(t, r.reconcileUpgrade(a))
+	assert.NoError(t, r.reconcileRepoDeployment(a))
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "argocd-repo-server-abcde",
+			Namespace: a.Namespace,
+			Labels:    map[string]string{common.ArgoCDKeyName: nameWithSuffix("repo-server", a)},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "argocd-repo-server", Image: "quay.io/argoproj/argocd:v2.3.3"}},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "argocd-repo-server",
+				State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				RestartCount: 1,
+			}},
+		},
+	}
+	assert.NoError(t, r.Client.Create(context.TODO(), pod))
+
+	// A container that crashed fewer times than the threshold does not roll the upgrade back
+	assert.NoError(t, r.reconcileUpgrade(a))
+	assert.Equal(t, argoprojv1alpha1.ArgoCDUpgradePhaseProgressing, a.Status.Upgrade.Phase)
+
+	pod.Status.ContainerStatuses[0].RestartCount = common.ArgoCDUpgradeRestartThreshold
+	assert.NoError(t, r.Client.Update(context.TODO(), pod))
+	assert.NoError(t, r.reconcileUpgrade(a))
+	assert.Equal(t, argoprojv1alpha1.ArgoCDUpgradePhaseRolledBack, a.Status.Upgrade.Phase)
+	assert.Contains(t, a.Status.Upgrade.Message, "CrashLoopBackOff")
+}
+
+func TestReconcileArgoCD_reconcileUpgrade_progressDeadline(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCDWithManagedUpgrade("v2.3.3")
+	a.Spec.Upgrade.ProgressDeadline = &metav1.Duration{Duration: time.Minute}
+	setArgoImageStatus(a, "quay.io/argoproj/argocd:v2.2.5")
+	r := makeTestReconciler(t, a)
+
+	assert.NoError(t, r.reconcileUpgrade(a))
+	assert.NoError(t, r.reconcileUpgrade(a))
+	assert.Equal(t, argoprojv1alpha1.ArgoCDUpgradePhaseProgressing, a.Status.Upgrade.Phase)
+
+	a.Status.Upgrade.LastTransitionTime = metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	assert.NoError(t, r.reconcileUpgrade(a))
+	assert.Equal(t, argoprojv1alpha1.ArgoCDUpgradePhaseRolledBack, a.Status.Upgrade.Phase)
+	assert.Contains(t, a.Status.Upgrade.Message, "did not become ready within 1m0s")
+}
+
+func TestReconcileArgoCD_reconcileUpgrade_blocked(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCDWithManagedUpgrade("v2.3.3")
+	setArgoImageStatus(a, "quay.io/argoproj/argocd:v2.1.4")
+	r := makeTestReconciler(t, a)
+
+	assert.NoError(t, r.reconcileUpgrade(a))
+	assert.Equal(t, argoprojv1alpha1.ArgoCDUpgradePhaseBlocked, a.Status.Upgrade.Phase)
+	assert.Contains(t, a.Status.Upgrade.Message, "upgrade to v2.2 first")
+	assert.Equal(t, "quay.io/argoproj/argocd:v2.1.4", getArgoComponentContainerImage(a, "server"))
+
+	setStatusConditions(a, nil)
+	degraded := meta.FindStatusCondition(a.Status.Conditions, argoprojv1alpha1.ArgoCDConditionDegraded)
+	assert.Equal(t, metav1.ConditionTrue, degraded.Status)
+	assert.Equal(t, argoprojv1alpha1.ArgoCDReasonUpgradeBlocked, degraded.Reason)
+
+	// The pre-flight checks can be skipped
+	a.Spec.Upgrade.SkipPreflightChecks = true
+	assert.NoError(t, r.reconcileUpgrade(a))
+	assert.Equal(t, argoprojv1alpha1.ArgoCDUpgradePhaseProgressing, a.Status.Upgrade.Phase)
+}
+
+func TestReconcileArgoCD_reconcileUpgrade_unmanaged(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Version = "v2.3.3"
+	})
+	setArgoImageStatus(a, "quay.io/argoproj/argocd:v2.1.4")
+	r := makeTestReconciler(t, a)
+
+	assert.NoError(t, r.reconcileUpgrade(a))
+	assert.Equal(t, "v2.3.3", a.Status.Version)
+	assert.Nil(t, a.Status.Upgrade)
+	assert.Equal(t, "quay.io/argoproj/argocd:v2.3.3", getArgoComponentContainerImage(a, "server"))
+}
+
+func TestCheckUpgradePreflight(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		failures int
+	}{
+		{"next minor version", "v2.2.5", "v2.3.3", 0},
+		{"patch version", "v2.3.1", "v2.3.3", 0},
+		{"default version", "v2.2.5", common.ArgoCDDefaultArgoVersion, 0},
+		{"skipped minor version", "v2.1.4", "v2.3.3", 1},
+		{"downgrade", "v2.3.3", "v2.2.5", 1},
+		{"unsupported version", "v2.3.3", "v2.4.0", 1},
+		{"major version", "v1.8.7", "v2.3.3", 1},
+		{"unknown version", "v2.2.5", "latest", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := makeTestArgoCD()
+			r := makeTestReconciler(t, a)
+			failures, err := r.checkUpgradePreflight(a, tt.from, tt.to)
+			assert.NoError(t, err)
+			assert.Len(t, failures, tt.failures)
+		})
+	}
+}
+
+func TestCheckUpgradePreflight_removedConfigKeys(t *testing.T) {
+	keys := deprecatedConfigKeys
+	t.Cleanup(func() {
+		deprecatedConfigKeys = keys
+	})
+	deprecatedConfigKeys = append([]deprecatedConfigKey{}, keys...)
+	deprecatedConfigKeys = append(deprecatedConfigKeys, deprecatedConfigKey{
+		key: "test.key", deprecated: "v2.2", removed: "v2.3", replacement: "test.replacement",
+	})
+
+	a := makeTestArgoCD()
+	cm := newConfigMapWithName(common.ArgoCDConfigMapName, a)
+	cm.Data = map[string]string{"test.key": "value", "repositories": "- url: https://github.com/argoproj/argocd-example-apps"}
+	r := makeTestReconciler(t, a, cm)
+
+	failures, err := r.checkUpgradePreflight(a, "v2.2.5", "v2.3.3")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"the argocd-cm key test.key was removed in v2.3, use test.replacement instead"}, failures)
+
+	// Deprecated keys do not block the upgrade
+	assert.Len(t, r.getDeprecatedConfigKeys(a, "v2.2.5"), 2)
+}
+
+func TestGetArgoImageVersion(t *testing.T) {
+	assert.Equal(t, "v2.3.3", getArgoImageVersion("quay.io/argoproj/argocd:v2.3.3"))
+	assert.Equal(t, "latest", getArgoImageVersion("quay.io/argoproj/argocd"))
+	assert.Equal(t, "sha256:0123", getArgoImageVersion("quay.io/argoproj/argocd@sha256:0123"))
+
+	// The digest of the default image must be resolvable, add it to argoCDImageDigests when the default changes
+	v, ok := parseArgoVersion(getArgoImageVersion(getDesiredArgoImage(makeTestArgoCD())))
+	assert.True(t, ok)
+	assert.Contains(t, supportedArgoVersions(), v.String())
+}
@@ -45,15 +45,20 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	v1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -127,53 +132,24 @@ func getArgoApplicationControllerCommand(cr *argoprojv1a1.ArgoCD) []string {
 
 // getArgoContainerImage will return the container image for ArgoCD.
 func getArgoContainerImage(cr *argoprojv1a1.ArgoCD) string {
-	defaultTag, defaultImg := false, false
-	img := cr.Spec.Image
-	if img == "" {
-		img = common.ArgoCDDefaultArgoImage
-		defaultImg = true
-	}
-
-	tag := cr.Spec.Version
-	if tag == "" {
-		tag = common.ArgoCDDefaultArgoVersion
-		defaultTag = true
-	}
-	if e := os.Getenv(common.ArgoCDImageEnvName); e != "" && (defaultTag && defaultImg) {
-		return e
-	}
-
-	return argoutil.CombineImageTag(img, tag)
+	return mirrorImage(cr, getDesiredArgoImage(cr))
 }
 
 // getRepoServerContainerImage will return the container image for the Repo server.
 //
-// There are three possible options for configuring the image, and this is the
+// There are two possible options for configuring the image, and this is the
 // order of preference.
 //
 // 1. from the Spec, the spec.repo field has an image and version to use for
 // generating an image reference.
-// 2. from the Environment, this looks for the `ARGOCD_REPOSERVER_IMAGE` field and uses
-// that if the spec is not configured.
-// 3. the default is configured in common.ArgoCDDefaultRepoServerVersion and
-// common.ArgoCDDefaultRepoServerImage.
+// 2. the container image for ArgoCD, see getArgoContainerImage, if neither the
+// image nor the version is set in the spec.repo field.
 func getRepoServerContainerImage(cr *argoprojv1a1.ArgoCD) string {
-	defaultImg, defaultTag := false, false
-	img := cr.Spec.Repo.Image
-	if img == "" {
-		img = common.ArgoCDDefaultArgoImage
-		defaultImg = true
-	}
-
-	tag := cr.Spec.Repo.Version
-	if tag == "" {
-		tag = common.ArgoCDDefaultArgoVersion
-		defaultTag = true
-	}
-	if e := os.Getenv(common.ArgoCDImageEnvName); e != "" && (defaultTag && defaultImg) {
-		return e
+	if cr.Spec.Repo.Image == "" && cr.Spec.Repo.Version == "" {
+		return getArgoContainerImage(cr)
 	}
-	return argoutil.CombineImageTag(img, tag)
+	return mirrorImage(cr, argoutil.ContainerImage(cr.Spec.Repo.Image, cr.Spec.Repo.Version,
+		common.ArgoCDDefaultArgoImage, common.ArgoCDDefaultArgoVersion))
 }
 
 // getArgoRepoResources will return the ResourceRequirements for the Argo CD Repo server container.
@@ -298,32 +274,21 @@ func getArgoControllerParellismLimit(cr *argoprojv1a1.ArgoCD) int32 {
 
 // getDexContainerImage will return the container image for the Dex server.
 //
-// There are three possible options for configuring the image, and this is the
+// There are four possible options for configuring the image, and this is the
 // order of preference.
 //
 // 1. from the Spec, the spec.dex field has an image and version to use for
 // generating an image reference.
 // 2. from the Environment, this looks for the `ARGOCD_DEX_IMAGE` field and uses
 // that if the spec is not configured.
-// 3. the default is configured in common.ArgoCDDefaultDexVersion and
+// 3. from the Environment, this looks for the `RELATED_IMAGE_DEX` field set by
+// OLM and uses that if neither of the above is configured.
+// 4. the default is configured in common.ArgoCDDefaultDexVersion and
 // common.ArgoCDDefaultDexImage.
 func getDexContainerImage(cr *argoprojv1a1.ArgoCD) string {
-	defaultImg, defaultTag := false, false
-	img := cr.Spec.Dex.Image
-	if img == "" {
-		img = common.ArgoCDDefaultDexImage
-		defaultImg = true
-	}
-
-	tag := cr.Spec.Dex.Version
-	if tag == "" {
-		tag = common.ArgoCDDefaultDexVersion
-		defaultTag = true
-	}
-	if e := os.Getenv(common.ArgoCDDexImageEnvName); e != "" && (defaultTag && defaultImg) {
-		return e
-	}
-	return argoutil.CombineImageTag(img, tag)
+	return mirrorImage(cr, argoutil.ContainerImage(cr.Spec.Dex.Image, cr.Spec.Dex.Version,
+		common.ArgoCDDefaultDexImage, common.ArgoCDDefaultDexVersion,
+		common.ArgoCDDexImageEnvName, common.ArgoCDDexRelatedImageEnvName))
 }
 
 // getDexOAuthClientID will return the OAuth client ID for the given ArgoCD.
@@ -375,22 +340,9 @@ func getDexResources(cr *argoprojv1a1.ArgoCD) corev1.ResourceRequirements {
 
 // getGrafanaContainerImage will return the container image for the Grafana server.
 func getGrafanaContainerImage(cr *argoprojv1a1.ArgoCD) string {
-	defaultTag, defaultImg := false, false
-	img := cr.Spec.Grafana.Image
-	if img == "" {
-		img = common.ArgoCDDefaultGrafanaImage
-		defaultImg = true
-	}
-
-	tag := cr.Spec.Grafana.Version
-	if tag == "" {
-		tag = common.ArgoCDDefaultGrafanaVersion
-		defaultTag = true
-	}
-	if e := os.Getenv(common.ArgoCDGrafanaImageEnvName); e != "" && (defaultTag && defaultImg) {
-		return e
-	}
-	return argoutil.CombineImageTag(img, tag)
+	return mirrorImage(cr, argoutil.ContainerImage(cr.Spec.Grafana.Image, cr.Spec.Grafana.Version,
+		common.ArgoCDDefaultGrafanaImage, common.ArgoCDDefaultGrafanaVersion,
+		common.ArgoCDGrafanaImageEnvName, common.ArgoCDGrafanaRelatedImageEnvName))
 }
 
 // getGrafanaResources will return the ResourceRequirements for the Grafana container.
@@ -459,40 +411,16 @@ func getRedisConf() string {
 
 // getRedisContainerImage will return the container image for the Redis server.
 func getRedisContainerImage(cr *argoprojv1a1.ArgoCD) string {
-	defaultImg, defaultTag := false, false
-	img := cr.Spec.Redis.Image
-	if img == "" {
-		img = common.ArgoCDDefaultRedisImage
-		defaultImg = true
-	}
-	tag := cr.Spec.Redis.Version
-	if tag == "" {
-		tag = common.ArgoCDDefaultRedisVersion
-		defaultTag = true
-	}
-	if e := os.Getenv(common.ArgoCDRedisImageEnvName); e != "" && (defaultTag && defaultImg) {
-		return e
-	}
-	return argoutil.CombineImageTag(img, tag)
+	return mirrorImage(cr, argoutil.ContainerImage(cr.Spec.Redis.Image, cr.Spec.Redis.Version,
+		common.ArgoCDDefaultRedisImage, common.ArgoCDDefaultRedisVersion,
+		common.ArgoCDRedisImageEnvName, common.ArgoCDRedisRelatedImageEnvName))
 }
 
 // getRedisHAContainerImage will return the container image for the Redis server in HA mode.
 func getRedisHAContainerImage(cr *argoprojv1a1.ArgoCD) string {
-	defaultImg, defaultTag := false, false
-	img := cr.Spec.Redis.Image
-	if img == "" {
-		img = common.ArgoCDDefaultRedisImage
-		defaultImg = true
-	}
-	tag := cr.Spec.Redis.Version
-	if tag == "" {
-		tag = common.ArgoCDDefaultRedisVersionHA
-		defaultTag = true
-	}
-	if e := os.Getenv(common.ArgoCDRedisHAImageEnvName); e != "" && (defaultTag && defaultImg) {
-		return e
-	}
-	return argoutil.CombineImageTag(img, tag)
+	return mirrorImage(cr, argoutil.ContainerImage(cr.Spec.Redis.Image, cr.Spec.Redis.Version,
+		common.ArgoCDDefaultRedisImage, common.ArgoCDDefaultRedisVersionHA,
+		common.ArgoCDRedisHAImageEnvName, common.ArgoCDRedisHARelatedImageEnvName))
 }
 
 // getRedisHAProxyAddress will return the Redis HA Proxy service address for the given ArgoCD.
@@ -502,24 +430,9 @@ func getRedisHAProxyAddress(cr *argoprojv1a1.ArgoCD) string {
 
 // getRedisHAProxyContainerImage will return the container image for the Redis HA Proxy.
 func getRedisHAProxyContainerImage(cr *argoprojv1a1.ArgoCD) string {
-	defaultImg, defaultTag := false, false
-	img := cr.Spec.HA.RedisProxyImage
-	if len(img) <= 0 {
-		img = common.ArgoCDDefaultRedisHAProxyImage
-		defaultImg = true
-	}
-
-	tag := cr.Spec.HA.RedisProxyVersion
-	if len(tag) <= 0 {
-		tag = common.ArgoCDDefaultRedisHAProxyVersion
-		defaultTag = true
-	}
-
-	if e := os.Getenv(common.ArgoCDRedisHAProxyImageEnvName); e != "" && (defaultTag && defaultImg) {
-		return e
-	}
-
-	return argoutil.CombineImageTag(img, tag)
+	return mirrorImage(cr, argoutil.ContainerImage(cr.Spec.HA.RedisProxyImage, cr.Spec.HA.RedisProxyVersion,
+		common.ArgoCDDefaultRedisHAProxyImage, common.ArgoCDDefaultRedisHAProxyVersion,
+		common.ArgoCDRedisHAProxyImageEnvName, common.ArgoCDRedisHAProxyRelatedImageEnvName))
 }
 
 // getRedisInitScript will load the redis init script from a template on disk for the given ArgoCD.
@@ -694,6 +607,14 @@ func InspectCluster() error {
 	if err := verifyTemplateAPI(); err != nil {
 		return err
 	}
+
+	if err := verifyPolicyV1API(); err != nil {
+		return err
+	}
+
+	if err := argoutil.VerifyOpenShiftAPI(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -711,113 +632,98 @@ func (r *ReconcileArgoCD) reconcileCertificateAuthority(cr *argoprojv1a1.ArgoCD)
 	return nil
 }
 
-// reconcileResources will reconcile common ArgoCD resources.
-func (r *ReconcileArgoCD) reconcileResources(cr *argoprojv1a1.ArgoCD) error {
-	log.Info("reconciling status")
-	if err := r.reconcileStatus(cr); err != nil {
-		return err
-	}
+// reconcileStep is a single step of the reconciliation of an ArgoCD, that is run independently of the other steps.
+type reconcileStep struct {
+	// component is the CamelCase name of the component, used for the status condition of the step.
+	component string
 
-	log.Info("reconciling roles")
-	if err := r.reconcileRoles(cr); err != nil {
-		return err
-	}
+	// enabled is false if the step does not apply to the ArgoCD.
+	enabled bool
 
-	log.Info("reconciling rolebindings")
-	if err := r.reconcileRoleBindings(cr); err != nil {
-		return err
-	}
+	reconcile func(cr *argoprojv1a1.ArgoCD) error
+}
 
-	log.Info("reconciling service accounts")
-	if err := r.reconcileServiceAccounts(cr); err != nil {
-		return err
+// reconcileSteps returns the steps to reconcile the common resources of the given ArgoCD, in order.
+func (r *ReconcileArgoCD) reconcileSteps(cr *argoprojv1a1.ArgoCD) []reconcileStep {
+	return []reconcileStep{
+		{component: "Migrations", enabled: true, reconcile: r.reconcileSchemaMigrations},
+		{component: "Roles", enabled: true, reconcile: r.reconcileRoles},
+		{component: "RoleBindings", enabled: true, reconcile: r.reconcileRoleBindings},
+		{component: "ServiceAccounts", enabled: true, reconcile: r.reconcileServiceAccounts},
+		{component: "CertificateAuthority", enabled: true, reconcile: r.reconcileCertificateAuthority},
+		{component: "Secrets", enabled: true, reconcile: r.reconcileSecrets},
+		{component: "ConfigMaps", enabled: true, reconcile: r.reconcileConfigMaps},
+		{component: "Services", enabled: true, reconcile: r.reconcileServices},
+		{component: "Upgrade", enabled: true, reconcile: r.reconcileUpgrade},
+		{component: "Deployments", enabled: true, reconcile: r.reconcileDeployments},
+		{component: "StatefulSets", enabled: true, reconcile: r.reconcileStatefulSets},
+		{component: "Autoscalers", enabled: true, reconcile: r.reconcileAutoscalers},
+		{component: "PodDisruptionBudgets", enabled: true, reconcile: r.reconcilePodDisruptionBudgets},
+		{component: "NetworkPolicies", enabled: true, reconcile: r.reconcileNetworkPolicies},
+		{component: "Ingresses", enabled: true, reconcile: r.reconcileIngresses},
+		{component: "Routes", enabled: IsRouteAPIAvailable(), reconcile: r.reconcileRoutes},
+		{component: "Prometheus", enabled: IsPrometheusAPIAvailable(), reconcile: r.reconcilePrometheusResources},
+		{component: "ApplicationSet", enabled: cr.Spec.ApplicationSet != nil, reconcile: r.reconcileApplicationSetController},
+		{component: "RepoServerTLS", enabled: true, reconcile: r.reconcileRepoServerTLSSecret},
+		{component: "SSO", enabled: cr.Spec.SSO != nil, reconcile: r.reconcileSSO},
 	}
+}
 
-	log.Info("reconciling certificate authority")
-	if err := r.reconcileCertificateAuthority(cr); err != nil {
-		return err
-	}
+// reconcileResources will reconcile common ArgoCD resources.
+//
+// Every step is run, even if a previous step failed, so that a failing component does not prevent the others from
+// being reconciled. The failure of a step is recorded in a status condition for its component, and the errors of all
+// failed steps are returned as an aggregate, so that the request is requeued with backoff.
+func (r *ReconcileArgoCD) reconcileResources(cr *argoprojv1a1.ArgoCD) error {
+	return runReconcileSteps(cr, r.reconcileSteps(cr))
+}
 
-	log.Info("reconciling secrets")
-	if err := r.reconcileSecrets(cr); err != nil {
-		return err
-	}
+// runReconcileSteps will run all of the given enabled steps for the given ArgoCD and return the aggregated errors.
+func runReconcileSteps(cr *argoprojv1a1.ArgoCD, steps []reconcileStep) error {
+	var errs []error
 
-	log.Info("reconciling config maps")
-	if err := r.reconcileConfigMaps(cr); err != nil {
-		return err
-	}
+	for _, step := range steps {
+		conditionType := componentReconcileErrorCondition(step.component)
 
-	log.Info("reconciling services")
-	if err := r.reconcileServices(cr); err != nil {
-		return err
-	}
+		if !step.enabled {
+			meta.RemoveStatusCondition(&cr.Status.Conditions, conditionType)
+			continue
+		}
 
-	log.Info("reconciling deployments")
-	if err := r.reconcileDeployments(cr); err != nil {
-		return err
+		log.Info("reconciling " + strings.ToLower(step.component))
+		if err := step.reconcile(cr); err != nil {
+			log.Error(err, "failed to reconcile "+strings.ToLower(step.component))
+			setCondition(cr, conditionType, metav1.ConditionTrue, argoprojv1a1.ArgoCDReasonReconcileFailed, err.Error())
+			errs = append(errs, fmt.Errorf("failed to reconcile %s: %w", step.component, err))
+			continue
+		}
+		meta.RemoveStatusCondition(&cr.Status.Conditions, conditionType)
 	}
 
-	log.Info("reconciling statefulsets")
-	if err := r.reconcileStatefulSets(cr); err != nil {
-		return err
-	}
+	return utilerrors.NewAggregate(errs)
+}
 
-	log.Info("reconciling autoscalers")
-	if err := r.reconcileAutoscalers(cr); err != nil {
+// reconcilePrometheusResources will reconcile the Prometheus instance and the ServiceMonitors for the given ArgoCD.
+func (r *ReconcileArgoCD) reconcilePrometheusResources(cr *argoprojv1a1.ArgoCD) error {
+	if err := r.reconcilePrometheus(cr); err != nil {
 		return err
 	}
 
-	log.Info("reconciling ingresses")
-	if err := r.reconcileIngresses(cr); err != nil {
+	if err := r.reconcileMetricsServiceMonitor(cr); err != nil {
 		return err
 	}
 
-	if IsRouteAPIAvailable() {
-		log.Info("reconciling routes")
-		if err := r.reconcileRoutes(cr); err != nil {
-			return err
-		}
-	}
-
-	if IsPrometheusAPIAvailable() {
-		log.Info("reconciling prometheus")
-		if err := r.reconcilePrometheus(cr); err != nil {
-			return err
-		}
-
-		if err := r.reconcileMetricsServiceMonitor(cr); err != nil {
-			return err
-		}
-
-		if err := r.reconcileRepoServerServiceMonitor(cr); err != nil {
-			return err
-		}
-
-		if err := r.reconcileServerMetricsServiceMonitor(cr); err != nil {
-			return err
-		}
-	}
-
-	if cr.Spec.ApplicationSet != nil {
-		log.Info("reconciling ApplicationSet controller")
-		if err := r.reconcileApplicationSetController(cr); err != nil {
-			return err
-		}
-	}
-
-	if err := r.reconcileRepoServerTLSSecret(cr); err != nil {
+	if err := r.reconcileRepoServerServiceMonitor(cr); err != nil {
 		return err
 	}
 
-	if cr.Spec.SSO != nil {
-		log.Info("reconciling SSO")
-		if err := r.reconcileSSO(cr); err != nil {
-			return err
-		}
-	}
+	return r.reconcileServerMetricsServiceMonitor(cr)
+}
 
-	return nil
+// componentReconcileErrorCondition returns the type of the status condition that records a failure to reconcile the
+// given component.
+func componentReconcileErrorCondition(component string) string {
+	return component + argoprojv1a1.ArgoCDConditionReconcileError
 }
 
 func (r *ReconcileArgoCD) deleteClusterResources(cr *argoprojv1a1.ArgoCD) error {
@@ -992,6 +898,9 @@ func setResourceWatches(bldr *builder.Builder, clusterResourceMapper, tlsSecretM
 	// Watch for changes to Ingress sub-resources owned by ArgoCD instances.
 	bldr.Owns(&networkingv1.Ingress{})
 
+	// Watch for changes to NetworkPolicy sub-resources owned by ArgoCD instances.
+	bldr.Owns(&networkingv1.NetworkPolicy{})
+
 	bldr.Owns(&v1.Role{})
 
 	bldr.Owns(&v1.RoleBinding{})
@@ -1016,6 +925,13 @@ func setResourceWatches(bldr *builder.Builder, clusterResourceMapper, tlsSecretM
 		log.Info("unable to inspect cluster")
 	}
 
+	// Watch for changes to PodDisruptionBudget sub-resources owned by ArgoCD instances.
+	if IsPolicyV1APIAvailable() {
+		bldr.Owns(&policyv1.PodDisruptionBudget{})
+	} else {
+		bldr.Owns(&policyv1beta1.PodDisruptionBudget{})
+	}
+
 	if IsRouteAPIAvailable() {
 		// Watch OpenShift Route sub-resources owned by ArgoCD instances.
 		bldr.Owns(&routev1.Route{})
@@ -1050,17 +966,31 @@ func boolPtr(val bool) *bool {
 	return &val
 }
 
-// triggerRollout will trigger a rollout of a Kubernetes resource specified as
-// obj. It currently supports Deployment and StatefulSet resources.
-func (r *ReconcileArgoCD) triggerRollout(obj interface{}, key string) error {
-	switch res := obj.(type) {
-	case *appsv1.Deployment:
-		return r.triggerDeploymentRollout(res, key)
-	case *appsv1.StatefulSet:
-		return r.triggerStatefulSetRollout(res, key)
-	default:
-		return fmt.Errorf("resource of unknown type %T, cannot trigger rollout", res)
+// int32Ptr returns a pointer to val
+func int32Ptr(val int32) *int32 {
+	return &val
+}
+
+// applyResource will add the common metadata and apply the patches of the given ArgoCD to the given object, set the
+// ArgoCD as the owner of the object and server-side apply the object using the operator field manager, see
+// argoutil.ApplyObject. Fields of the object that were modified out-of-band are recorded as drift for the given ArgoCD.
+func (r *ReconcileArgoCD) applyResource(cr *argoprojv1a1.ArgoCD, obj client.Object) error {
+	setCommonMetadata(cr, obj)
+	setImagePullSecrets(cr, obj)
+	if err := r.applyPatches(cr, obj); err != nil {
+		return err
 	}
+	if err := controllerutil.SetControllerReference(cr, obj, r.Scheme); err != nil {
+		return err
+	}
+	conflicts, err := argoutil.ApplyObject(r.Client, obj)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		r.recordDrift(cr, obj, conflicts)
+	}
+	return nil
 }
 
 func allowedNamespace(current string, namespaces string) bool {
@@ -3,6 +3,7 @@ package argocd
 import (
 	"context"
 	b64 "encoding/base64"
+	"errors"
 	"os"
 	"reflect"
 	"strings"
@@ -16,8 +17,11 @@ import (
 	"github.com/argoproj-labs/argocd-operator/controllers/argoutil"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	testclient "k8s.io/client-go/kubernetes/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 const (
@@ -62,6 +66,18 @@ var imageTests = []struct {
 			os.Setenv(common.ArgoCDDexImageEnvName, dexTestImage)
 		},
 	},
+	{
+		name:      "dex related image configuration",
+		imageFunc: getDexContainerImage,
+		want:      dexTestImage,
+		pre: func(t *testing.T) {
+			old := os.Getenv(common.ArgoCDDexRelatedImageEnvName)
+			t.Cleanup(func() {
+				os.Setenv(common.ArgoCDDexRelatedImageEnvName, old)
+			})
+			os.Setenv(common.ArgoCDDexRelatedImageEnvName, dexTestImage)
+		},
+	},
 	{
 		name:      "argo default configuration",
 		imageFunc: getArgoContainerImage,
@@ -87,6 +103,47 @@ var imageTests = []struct {
 			os.Setenv(common.ArgoCDImageEnvName, argoTestImage)
 		},
 	},
+	{
+		name:      "argo related image configuration",
+		imageFunc: getArgoContainerImage,
+		want:      argoTestImage,
+		pre: func(t *testing.T) {
+			old := os.Getenv(common.ArgoCDRelatedImageEnvName)
+			t.Cleanup(func() {
+				os.Setenv(common.ArgoCDRelatedImageEnvName, old)
+			})
+			os.Setenv(common.ArgoCDRelatedImageEnvName, argoTestImage)
+		},
+	},
+	{
+		name:      "argo env configuration over related image",
+		imageFunc: getArgoContainerImage,
+		want:      argoTestImage,
+		pre: func(t *testing.T) {
+			oldImage, oldRelatedImage := os.Getenv(common.ArgoCDImageEnvName), os.Getenv(common.ArgoCDRelatedImageEnvName)
+			t.Cleanup(func() {
+				os.Setenv(common.ArgoCDImageEnvName, oldImage)
+				os.Setenv(common.ArgoCDRelatedImageEnvName, oldRelatedImage)
+			})
+			os.Setenv(common.ArgoCDImageEnvName, argoTestImage)
+			os.Setenv(common.ArgoCDRelatedImageEnvName, "testing/argocd:related")
+		},
+	},
+	{
+		name:      "argo spec configuration over related image",
+		imageFunc: getArgoContainerImage,
+		want:      argoutil.CombineImageTag(common.ArgoCDDefaultArgoImage, "latest"),
+		opts: []argoCDOpt{func(a *argoprojv1alpha1.ArgoCD) {
+			a.Spec.Version = "latest"
+		}},
+		pre: func(t *testing.T) {
+			old := os.Getenv(common.ArgoCDRelatedImageEnvName)
+			t.Cleanup(func() {
+				os.Setenv(common.ArgoCDRelatedImageEnvName, old)
+			})
+			os.Setenv(common.ArgoCDRelatedImageEnvName, argoTestImage)
+		},
+	},
 	{
 		name:      "grafana default configuration",
 		imageFunc: getGrafanaContainerImage,
@@ -113,6 +170,18 @@ var imageTests = []struct {
 			os.Setenv(common.ArgoCDGrafanaImageEnvName, grafanaTestImage)
 		},
 	},
+	{
+		name:      "grafana related image configuration",
+		imageFunc: getGrafanaContainerImage,
+		want:      grafanaTestImage,
+		pre: func(t *testing.T) {
+			old := os.Getenv(common.ArgoCDGrafanaRelatedImageEnvName)
+			t.Cleanup(func() {
+				os.Setenv(common.ArgoCDGrafanaRelatedImageEnvName, old)
+			})
+			os.Setenv(common.ArgoCDGrafanaRelatedImageEnvName, grafanaTestImage)
+		},
+	},
 	{
 		name:      "redis default configuration",
 		imageFunc: getRedisContainerImage,
@@ -139,6 +208,18 @@ var imageTests = []struct {
 			os.Setenv(common.ArgoCDRedisImageEnvName, redisTestImage)
 		},
 	},
+	{
+		name:      "redis related image configuration",
+		imageFunc: getRedisContainerImage,
+		want:      redisTestImage,
+		pre: func(t *testing.T) {
+			old := os.Getenv(common.ArgoCDRedisRelatedImageEnvName)
+			t.Cleanup(func() {
+				os.Setenv(common.ArgoCDRedisRelatedImageEnvName, old)
+			})
+			os.Setenv(common.ArgoCDRedisRelatedImageEnvName, redisTestImage)
+		},
+	},
 	{
 		name:      "redis ha default configuration",
 		imageFunc: getRedisHAContainerImage,
@@ -167,6 +248,18 @@ var imageTests = []struct {
 			os.Setenv(common.ArgoCDRedisHAImageEnvName, redisHATestImage)
 		},
 	},
+	{
+		name:      "redis ha related image configuration",
+		imageFunc: getRedisHAContainerImage,
+		want:      redisHATestImage,
+		pre: func(t *testing.T) {
+			old := os.Getenv(common.ArgoCDRedisHARelatedImageEnvName)
+			t.Cleanup(func() {
+				os.Setenv(common.ArgoCDRedisHARelatedImageEnvName, old)
+			})
+			os.Setenv(common.ArgoCDRedisHARelatedImageEnvName, redisHATestImage)
+		},
+	},
 	{
 		name:      "redis ha proxy default configuration",
 		imageFunc: getRedisHAProxyContainerImage,
@@ -195,6 +288,18 @@ var imageTests = []struct {
 			os.Setenv(common.ArgoCDRedisHAProxyImageEnvName, redisHAProxyTestImage)
 		},
 	},
+	{
+		name:      "redis ha proxy related image configuration",
+		imageFunc: getRedisHAProxyContainerImage,
+		want:      redisHAProxyTestImage,
+		pre: func(t *testing.T) {
+			old := os.Getenv(common.ArgoCDRedisHAProxyRelatedImageEnvName)
+			t.Cleanup(func() {
+				os.Setenv(common.ArgoCDRedisHAProxyRelatedImageEnvName, old)
+			})
+			os.Setenv(common.ArgoCDRedisHAProxyRelatedImageEnvName, redisHAProxyTestImage)
+		},
+	},
 }
 
 func TestContainerImages_configuration(t *testing.T) {
@@ -652,3 +757,52 @@ func generateEncodedPEM(t *testing.T, host string) []byte {
 	encoded := argoutil.EncodeCertificatePEM(cert)
 	return encoded
 }
+
+func TestRunReconcileSteps(t *testing.T) {
+	a := makeTestArgoCD()
+	a.Status.Conditions = []metav1.Condition{{
+		Type:   componentReconcileErrorCondition("Routes"),
+		Status: metav1.ConditionTrue,
+		Reason: argoprojv1alpha1.ArgoCDReasonReconcileFailed,
+	}}
+
+	var ran []string
+	step := func(component string, enabled bool, err error) reconcileStep {
+		return reconcileStep{component: component, enabled: enabled, reconcile: func(cr *argoprojv1alpha1.ArgoCD) error {
+			ran = append(ran, component)
+			return err
+		}}
+	}
+
+	err := runReconcileSteps(a, []reconcileStep{
+		step("Secrets", true, errors.New("secret error")),
+		step("Deployments", true, nil),
+		step("Routes", false, nil),
+		step("SSO", true, errors.New("sso error")),
+	})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to reconcile Secrets: secret error")
+	assert.Contains(t, err.Error(), "failed to reconcile SSO: sso error")
+	assert.Equal(t, []string{"Secrets", "Deployments", "SSO"}, ran)
+
+	secrets := meta.FindStatusCondition(a.Status.Conditions, componentReconcileErrorCondition("Secrets"))
+	assert.Equal(t, metav1.ConditionTrue, secrets.Status)
+	assert.Equal(t, "secret error", secrets.Message)
+	assert.True(t, meta.IsStatusConditionTrue(a.Status.Conditions, "SSOReconcileError"))
+	assert.Nil(t, meta.FindStatusCondition(a.Status.Conditions, componentReconcileErrorCondition("Deployments")))
+	assert.Nil(t, meta.FindStatusCondition(a.Status.Conditions, componentReconcileErrorCondition("Routes")))
+}
+
+func TestReconcileArgoCD_reconcileResources_continuesAfterFailure(t *testing.T) {
+	logf.SetLogger(ZapLogger(true))
+	a := makeTestArgoCDForKeycloakWithDex()
+	r := makeTestReconciler(t, a)
+
+	assert.Error(t, r.reconcileResources(a))
+	assert.True(t, meta.IsStatusConditionTrue(a.Status.Conditions, componentReconcileErrorCondition("SSO")))
+
+	// the failing SSO configuration must not prevent the other components from being reconciled
+	deploy := newDeploymentWithSuffix("repo-server", "repo-server", a)
+	assert.NoError(t, r.Client.Get(context.TODO(), types.NamespacedName{Name: deploy.Name, Namespace: a.Namespace}, deploy))
+}
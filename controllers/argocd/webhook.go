@@ -0,0 +1,141 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/common"
+)
+
+// defaultingWebhookPath is the path that the defaulting webhook for ArgoCD is served on.
+const defaultingWebhookPath = "/mutate-argoproj-io-v1alpha1-argocd"
+
+//+kubebuilder:webhook:path=/mutate-argoproj-io-v1alpha1-argocd,mutating=true,failurePolicy=fail,sideEffects=None,groups=argoproj.io,resources=argocds,verbs=create;update,versions=v1alpha1,name=margocd.kb.io,admissionReviewVersions=v1
+
+// ArgoCDDefaulter writes the effective defaults into the spec of ArgoCD resources that opt in using the
+// common.AnnotationMaterializeDefaults annotation.
+type ArgoCDDefaulter struct {
+	decoder *admission.Decoder
+}
+
+// SetupDefaultingWebhookWithManager registers the defaulting webhook for ArgoCD with the given manager.
+func SetupDefaultingWebhookWithManager(mgr manager.Manager) {
+	mgr.GetWebhookServer().Register(defaultingWebhookPath, &webhook.Admission{Handler: &ArgoCDDefaulter{}})
+}
+
+// Handle will fill in the effective defaults for the ArgoCD in the given admission request.
+func (d *ArgoCDDefaulter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	cr := &argoprojv1a1.ArgoCD{}
+	if err := d.decoder.Decode(req, cr); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if !wantsMaterializedDefaults(cr) {
+		return admission.Allowed("")
+	}
+
+	setEffectiveSpec(cr)
+
+	marshaled, err := json.Marshal(cr)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+// InjectDecoder implements admission.DecoderInjector.
+func (d *ArgoCDDefaulter) InjectDecoder(decoder *admission.Decoder) error {
+	d.decoder = decoder
+	return nil
+}
+
+// wantsMaterializedDefaults returns true if the given ArgoCD opted in to having its defaults written into the spec.
+func wantsMaterializedDefaults(cr *argoprojv1a1.ArgoCD) bool {
+	return strings.EqualFold(cr.Annotations[common.AnnotationMaterializeDefaults], "true")
+}
+
+// setEffectiveSpec will fill every unset replica count, processor count, log option and resource requirement in the
+// spec with the value the operator would otherwise use for the given ArgoCD. Only values that do not depend on the
+// environment of the operator or on other fields of the spec are materialized: the images and versions follow the
+// environment and the defaults of the operator release and are reported in the status instead, Dex conflicts with a
+// later SSO configuration and the server defaults depend on autoscaling.
+func setEffectiveSpec(cr *argoprojv1a1.ArgoCD) {
+	// Controller
+	cr.Spec.Controller.Processors.Operation = getArgoServerOperationProcessors(cr)
+	cr.Spec.Controller.Processors.Status = getArgoServerStatusProcessors(cr)
+	cr.Spec.Controller.ParallelismLimit = getArgoControllerParellismLimit(cr)
+	cr.Spec.Controller.LogLevel = getLogLevel(cr.Spec.Controller.LogLevel)
+	cr.Spec.Controller.LogFormat = getLogFormat(cr.Spec.Controller.LogFormat)
+	setResources(&cr.Spec.Controller.Resources, getArgoApplicationControllerResources(cr))
+
+	// SSO
+	if cr.Spec.SSO != nil {
+		setResources(&cr.Spec.SSO.Resources, getKeycloakResources(cr))
+	}
+
+	// Redis
+	if cr.Spec.HA.Enabled {
+		setResources(&cr.Spec.HA.Resources, getRedisHAProxyResources(cr))
+	}
+	setResources(&cr.Spec.Redis.Resources, getRedisResources(cr))
+
+	// Repo
+	if getArgoCDRepoServerReplicas(cr) == nil {
+		cr.Spec.Repo.Replicas = int32Ptr(1)
+	}
+	cr.Spec.Repo.LogLevel = getLogLevel(cr.Spec.Repo.LogLevel)
+	cr.Spec.Repo.LogFormat = getLogFormat(cr.Spec.Repo.LogFormat)
+	setResources(&cr.Spec.Repo.Resources, getArgoRepoResources(cr))
+
+	// Server
+	cr.Spec.Server.LogLevel = getLogLevel(cr.Spec.Server.LogLevel)
+	cr.Spec.Server.LogFormat = getLogFormat(cr.Spec.Server.LogFormat)
+
+	// Grafana
+	if cr.Spec.Grafana.Enabled {
+		cr.Spec.Grafana.Size = getGrafanaReplicas(cr)
+		setResources(&cr.Spec.Grafana.Resources, getGrafanaResources(cr))
+	}
+
+	// Prometheus
+	if cr.Spec.Prometheus.Enabled {
+		cr.Spec.Prometheus.Size = getPrometheusReplicas(cr)
+	}
+
+	// ApplicationSet
+	if cr.Spec.ApplicationSet != nil {
+		cr.Spec.ApplicationSet.LogLevel = getLogLevel(cr.Spec.ApplicationSet.LogLevel)
+		setResources(&cr.Spec.ApplicationSet.Resources, getApplicationSetResources(cr))
+	}
+}
+
+// setResources will set the resource requirements field if it is empty and the effective requirements are not.
+func setResources(resources **corev1.ResourceRequirements, effective corev1.ResourceRequirements) {
+	if *resources != nil || reflect.DeepEqual(effective, corev1.ResourceRequirements{}) {
+		return
+	}
+	*resources = &effective
+}
@@ -0,0 +1,144 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argocd
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	argoprojv1alpha1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/common"
+)
+
+func TestSetEffectiveSpec(t *testing.T) {
+	a := makeTestArgoCD()
+	setEffectiveSpec(a)
+
+	assert.Equal(t, common.ArgoCDDefaultServerOperationProcessors, a.Spec.Controller.Processors.Operation)
+	assert.Equal(t, common.ArgoCDDefaultServerStatusProcessors, a.Spec.Controller.Processors.Status)
+	assert.Equal(t, common.ArgoCDDefaultControllerParallelismLimit, a.Spec.Controller.ParallelismLimit)
+	assert.Equal(t, common.ArgoCDDefaultLogLevel, a.Spec.Controller.LogLevel)
+	assert.Equal(t, common.ArgoCDDefaultLogFormat, a.Spec.Controller.LogFormat)
+
+	// The images follow the environment and the defaults of the operator release
+	assert.Empty(t, a.Spec.Image)
+	assert.Empty(t, a.Spec.Version)
+	assert.Empty(t, a.Spec.Redis.Image)
+	assert.Empty(t, a.Spec.Repo.Version)
+
+	// Dex is not materialized, so that SSO can still be configured later
+	assert.Equal(t, argoprojv1alpha1.ArgoCDDexSpec{}, a.Spec.Dex)
+
+	assert.Equal(t, int32(1), *a.Spec.Repo.Replicas)
+	assert.Nil(t, a.Spec.Server.Replicas)
+	assert.Nil(t, a.Spec.Server.Resources)
+
+	assert.Nil(t, a.Spec.ApplicationSet)
+	assert.Nil(t, a.Spec.Grafana.Size)
+}
+
+func TestSetEffectiveSpec_keepsUserValues(t *testing.T) {
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.Version = "v2.1.0"
+		a.Spec.Repo.Image = "registry.example.com/argocd"
+		a.Spec.Controller.Processors.Operation = 50
+		a.Spec.Server.LogLevel = "debug"
+		a.Spec.Server.Autoscale.Enabled = true
+	})
+	setEffectiveSpec(a)
+
+	assert.Equal(t, "v2.1.0", a.Spec.Version)
+	assert.Equal(t, "registry.example.com/argocd", a.Spec.Repo.Image)
+	assert.Empty(t, a.Spec.Repo.Version)
+	assert.Equal(t, int32(50), a.Spec.Controller.Processors.Operation)
+	assert.Equal(t, "debug", a.Spec.Server.LogLevel)
+
+	// The server defaults depend on autoscaling, they are not materialized
+	assert.Nil(t, a.Spec.Server.Replicas)
+	assert.Nil(t, a.Spec.Server.Resources)
+}
+
+func TestSetEffectiveSpec_ha(t *testing.T) {
+	a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+		a.Spec.HA.Enabled = true
+		a.Spec.SSO = &argoprojv1alpha1.ArgoCDSSOSpec{Provider: argoprojv1alpha1.SSOProviderTypeKeycloak}
+	})
+	setEffectiveSpec(a)
+
+	// The HA images are not written into the spec, so that they do not remain when HA is disabled
+	assert.Empty(t, a.Spec.Redis.Image)
+	assert.Empty(t, a.Spec.Redis.Version)
+	assert.Empty(t, a.Spec.HA.RedisProxyImage)
+
+	assert.Equal(t, argoprojv1alpha1.ArgoCDDexSpec{}, a.Spec.Dex)
+	assert.Empty(t, a.Spec.SSO.Image)
+	assert.NotNil(t, a.Spec.SSO.Resources)
+}
+
+func TestSetEffectiveSpec_laterSSO(t *testing.T) {
+	a := makeTestArgoCD()
+	setEffectiveSpec(a)
+
+	// An instance with materialized defaults can still switch to SSO
+	a.Spec.SSO = &argoprojv1alpha1.ArgoCDSSOSpec{Provider: argoprojv1alpha1.SSOProviderTypeKeycloak}
+	assert.NoError(t, a.ValidateUpdate(makeTestArgoCD()))
+}
+
+func TestArgoCDDefaulter_Handle(t *testing.T) {
+	assert.NoError(t, argoprojv1alpha1.AddToScheme(scheme.Scheme))
+	decoder, err := admission.NewDecoder(scheme.Scheme)
+	assert.NoError(t, err)
+	d := &ArgoCDDefaulter{}
+	assert.NoError(t, d.InjectDecoder(decoder))
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantPatches bool
+	}{
+		{"not annotated", nil, false},
+		{"opted out", map[string]string{common.AnnotationMaterializeDefaults: "false"}, false},
+		{"opted in", map[string]string{common.AnnotationMaterializeDefaults: "true"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a := makeTestArgoCD(func(a *argoprojv1alpha1.ArgoCD) {
+				a.TypeMeta = metav1.TypeMeta{APIVersion: "argoproj.io/v1alpha1", Kind: "ArgoCD"}
+				a.Annotations = test.annotations
+			})
+			raw, err := json.Marshal(a)
+			assert.NoError(t, err)
+
+			resp := d.Handle(context.TODO(), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					Object:    runtime.RawExtension{Raw: raw},
+				},
+			})
+
+			assert.True(t, resp.Allowed)
+			assert.Equal(t, test.wantPatches, len(resp.Patches) > 0)
+		})
+	}
+}
@@ -74,19 +74,13 @@ func getArgoExportContainerEnv(cr *argoprojv1a1.ArgoCDExport) []corev1.EnvVar {
 	return env
 }
 
-// getArgoExportContainerImage will return the container image for ArgoCD.
-func getArgoExportContainerImage(cr *argoprojv1a1.ArgoCDExport) string {
-	img := cr.Spec.Image
-	if len(img) <= 0 {
-		img = common.ArgoCDDefaultExportJobImage
-	}
-
-	tag := cr.Spec.Version
-	if len(tag) <= 0 {
-		tag = common.ArgoCDDefaultExportJobVersion
-	}
-
-	return argoutil.CombineImageTag(img, tag)
+// getArgoExportContainerImage will return the container image for ArgoCD, pulled from the image registry of the given
+// ArgoCD instance, if any.
+func getArgoExportContainerImage(cr *argoprojv1a1.ArgoCDExport, argocd *argoprojv1a1.ArgoCD) string {
+	img := argoutil.ContainerImage(cr.Spec.Image, cr.Spec.Version,
+		common.ArgoCDDefaultExportJobImage, common.ArgoCDDefaultExportJobVersion,
+		common.ArgoCDExportRelatedImageEnvName)
+	return argoutil.RewriteImageRegistry(img, argoutil.ImageRegistry(argocd))
 }
 
 // getArgoExportVolumeMounts will return the VolumneMounts for the given ArgoCDExport.
@@ -164,46 +158,55 @@ func newCronJob(cr *argoprojv1a1.ArgoCDExport) *batchv1.CronJob {
 	}
 }
 
-func newExportPodSpec(cr *argoprojv1a1.ArgoCDExport, argocdName string) corev1.PodSpec {
+func newExportPodSpec(cr *argoprojv1a1.ArgoCDExport, argocd *argoprojv1a1.ArgoCD) (corev1.PodSpec, error) {
 	pod := corev1.PodSpec{}
 
 	pod.Containers = []corev1.Container{{
 		Command:         getArgoExportCommand(cr),
 		Env:             getArgoExportContainerEnv(cr),
-		Image:           getArgoExportContainerImage(cr),
-		ImagePullPolicy: corev1.PullAlways,
+		Image:           getArgoExportContainerImage(cr, argocd),
+		ImagePullPolicy: argoutil.ImagePullPolicy(getArgoExportContainerImage(cr, argocd), cr.Spec.ImagePullPolicy, argocd.Spec.ImagePullPolicy),
 		Name:            "argocd-export",
 		VolumeMounts:    getArgoExportVolumeMounts(),
 	}}
 
+	pod.ImagePullSecrets = argocd.Spec.ImagePullSecrets
 	pod.RestartPolicy = corev1.RestartPolicyOnFailure
-	pod.ServiceAccountName = fmt.Sprintf("%s-%s", argocdName, "argocd-application-controller")
+	pod.ServiceAccountName = fmt.Sprintf("%s-%s", argocd.Name, "argocd-application-controller")
 	pod.Volumes = []corev1.Volume{
 		getArgoStorageVolume("backup-storage", cr),
 		getArgoSecretVolume("secret-storage", cr),
 	}
 
-	// Configure runAsUser, runAsGroup and fsGroup so that the job can write to the PV
-	// 999 is the uid/gid of the argocd user that the container runs as
-	id := int64(999)
+	// Configure runAsUser and fsGroup so that the job can write to the PV, 999 is the uid and gid of the argocd user
+	// that the container runs as, which is not numeric in the image
 	pod.SecurityContext = &corev1.PodSecurityContext{
-		RunAsUser:  &id,
-		RunAsGroup: &id,
-		FSGroup:    &id,
+		RunAsUser: argoutil.FixedID(999),
+		FSGroup:   argoutil.FixedID(999),
 	}
 
-	return pod
+	// The export writes to /tmp before encrypting the backup, so the root filesystem is not read-only
+	if err := argoutil.ApplySecurityContext(&pod, false, cr.Spec.SecurityContext); err != nil {
+		return pod, err
+	}
+
+	return pod, nil
 }
 
-func newPodTemplateSpec(cr *argoprojv1a1.ArgoCDExport, argocdName string) corev1.PodTemplateSpec {
+func newPodTemplateSpec(cr *argoprojv1a1.ArgoCDExport, argocd *argoprojv1a1.ArgoCD) (corev1.PodTemplateSpec, error) {
+	spec, err := newExportPodSpec(cr, argocd)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+
 	return corev1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cr.Name,
 			Namespace: cr.Namespace,
 			Labels:    common.DefaultLabels(cr.Name),
 		},
-		Spec: newExportPodSpec(cr, argocdName),
-	}
+		Spec: spec,
+	}, nil
 }
 
 // reconcileCronJob will ensure that the CronJob for the ArgoCDExport is present.
@@ -223,15 +226,17 @@ func (r *ReconcileArgoCDExport) reconcileCronJob(cr *argoprojv1a1.ArgoCDExport)
 
 	cj.Spec.Schedule = *cr.Spec.Schedule
 
-	// To create the job, we need the name of the argocd instance.  Although the argocd export cr contains a field with
-	// the argocd instance name, it's never used anywhere, and so there may be existing argocd export resources with the
-	// wrong name. To avoid these breaking, we look up the name of the argocd instance in the namespace of the export cr.
-	argocdName, err := r.argocdName(cr.Namespace)
+	// To create the job, we need the argocd instance.  Although the argocd export cr contains a field with the argocd
+	// instance name, it's never used anywhere, and so there may be existing argocd export resources with the wrong
+	// name. To avoid these breaking, we look up the argocd instance in the namespace of the export cr.
+	argocd, err := r.getArgoCD(cr.Namespace)
 	if err != nil {
 		return err
 	}
 	job := newJob(cr)
-	job.Spec.Template = newPodTemplateSpec(cr, argocdName)
+	if job.Spec.Template, err = newPodTemplateSpec(cr, argocd); err != nil {
+		return err
+	}
 
 	cj.Spec.JobTemplate.Spec = job.Spec
 
@@ -257,14 +262,16 @@ func (r *ReconcileArgoCDExport) reconcileJob(cr *argoprojv1a1.ArgoCDExport) erro
 		return nil // Job not complete, move along...
 	}
 
-	// To create the job, we need the name of the argocd instance.  Although the argocd export cr contains a field with
-	// the argocd instance name, it's never used anywhere, and so there may be existing argocd export resources with the
-	// wrong name. To avoid these breaking, we look up the name of the argocd instance in the namespace of the export cr.
-	argocdName, err := r.argocdName(cr.Namespace)
+	// To create the job, we need the argocd instance.  Although the argocd export cr contains a field with the argocd
+	// instance name, it's never used anywhere, and so there may be existing argocd export resources with the wrong
+	// name. To avoid these breaking, we look up the argocd instance in the namespace of the export cr.
+	argocd, err := r.getArgoCD(cr.Namespace)
 	if err != nil {
 		return err
 	}
-	job.Spec.Template = newPodTemplateSpec(cr, argocdName)
+	if job.Spec.Template, err = newPodTemplateSpec(cr, argocd); err != nil {
+		return err
+	}
 
 	if err := controllerutil.SetControllerReference(cr, job, r.Scheme); err != nil {
 		return err
@@ -272,14 +279,13 @@ func (r *ReconcileArgoCDExport) reconcileJob(cr *argoprojv1a1.ArgoCDExport) erro
 	return r.Client.Create(context.TODO(), job)
 }
 
-func (r *ReconcileArgoCDExport) argocdName(namespace string) (string, error) {
+func (r *ReconcileArgoCDExport) getArgoCD(namespace string) (*argoprojv1a1.ArgoCD, error) {
 	argocds := &argoprojv1a1.ArgoCDList{}
 	if err := r.Client.List(context.TODO(), argocds, &client.ListOptions{Namespace: namespace}); err != nil {
-		return "", err
+		return nil, err
 	}
 	if len(argocds.Items) != 1 {
-		return "", fmt.Errorf("No Argo CD instance found in namespace %s", namespace)
+		return nil, fmt.Errorf("No Argo CD instance found in namespace %s", namespace)
 	}
-	argocd := argocds.Items[0]
-	return argocd.Name, nil
+	return &argocds.Items[0], nil
 }
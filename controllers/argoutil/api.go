@@ -50,3 +50,20 @@ func VerifyAPI(group string, version string) (bool, error) {
 	log.Info(fmt.Sprintf("%s/%s API verified", group, version))
 	return true, nil
 }
+
+var openShiftAPIFound = false
+
+// IsOpenShiftCluster returns true if the OpenShift security API is present, i.e. the cluster is OpenShift.
+func IsOpenShiftCluster() bool {
+	return openShiftAPIFound
+}
+
+// VerifyOpenShiftAPI will verify that the OpenShift security API is present.
+func VerifyOpenShiftAPI() error {
+	found, err := VerifyAPI("security.openshift.io", "v1")
+	if err != nil {
+		return err
+	}
+	openShiftAPIFound = found
+	return nil
+}
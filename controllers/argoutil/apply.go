@@ -0,0 +1,185 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argoutil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/argoproj-labs/argocd-operator/common"
+)
+
+// conflictManagerRegexp matches the field manager in the message of a field manager conflict, e.g.
+// conflict with "kubectl-edit" using v1
+var conflictManagerRegexp = regexp.MustCompile(`"([^"]*)"`)
+
+// FieldConflict is a field that the operator applies, but that was modified out-of-band by another field manager.
+type FieldConflict struct {
+	// Field is the path of the field, e.g. .data.admin.enabled
+	Field string
+
+	// Manager is the field manager that modified the field.
+	Manager string
+}
+
+// ApplyObject will create or update the given object using server-side apply with the operator field manager.
+//
+// The given object must contain every field that the operator wants to manage. Fields that are set are reset to the
+// given value when they drift, fields that the operator applied before but are no longer set are removed and fields
+// that are owned by other field managers are left alone. The result will be stored in the given object.
+//
+// The fields that were modified out-of-band, and have been reset, are returned as conflicts.
+func ApplyObject(c client.Client, obj client.Object) ([]FieldConflict, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return nil, err
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	obj.SetManagedFields(nil)
+	obj.SetResourceVersion("")
+
+	if err := upgradeManagedFields(c, obj); err != nil {
+		return nil, fmt.Errorf("failed to upgrade managed fields of %s %s: %w", gvk.Kind, obj.GetName(), err)
+	}
+
+	// Apply without forcing first, the API server will reject the apply if it would take over fields that another field
+	// manager has set to a different value. The given object is left untouched if the apply is rejected.
+	err = c.Patch(context.TODO(), obj, client.Apply, client.FieldOwner(common.ArgoCDFieldManager))
+	if err == nil {
+		return nil, nil
+	}
+
+	conflicts, ok := fieldConflicts(err)
+	if !ok {
+		return nil, err
+	}
+
+	if err := c.Patch(context.TODO(), obj, client.Apply, client.FieldOwner(common.ArgoCDFieldManager), client.ForceOwnership); err != nil {
+		return nil, err
+	}
+	return conflicts, nil
+}
+
+// fieldConflicts will return the field manager conflicts of the given apply error, if it is a conflict error.
+// Conflicts with the legacy field manager are writes of the operator itself and are not returned.
+func fieldConflicts(err error) ([]FieldConflict, bool) {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) || !apierrors.IsConflict(err) {
+		return nil, false
+	}
+
+	details := status.Status().Details
+	if details == nil {
+		return nil, false
+	}
+
+	var conflicts []FieldConflict
+	found := false
+	for _, cause := range details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		found = true
+
+		manager := ""
+		if m := conflictManagerRegexp.FindStringSubmatch(cause.Message); m != nil {
+			manager = m[1]
+		}
+		if manager == common.ArgoCDLegacyFieldManager {
+			continue
+		}
+		conflicts = append(conflicts, FieldConflict{Field: cause.Field, Manager: manager})
+	}
+	return conflicts, found
+}
+
+// upgradeManagedFields will hand the fields of the given object that earlier versions of the operator set using
+// client-side updates over to the operator field manager. Without this, fields that are no longer desired, e.g. a
+// removed ConfigMap key, would remain owned by the legacy field manager and would never be removed by an apply.
+//
+// The legacy field manager name is shared by every controller built from the default project layout, so only the
+// entry that owns the controller reference of the given object is handed over, as only the operator sets it.
+func upgradeManagedFields(c client.Client, obj client.Object) error {
+	owner := metav1.GetControllerOf(obj)
+	if owner == nil {
+		return nil // The legacy entry cannot be told apart from other controllers that share the binary name
+	}
+
+	ro, err := c.Scheme().New(obj.GetObjectKind().GroupVersionKind())
+	if err != nil {
+		return err
+	}
+	existing, ok := ro.(client.Object)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", ro)
+	}
+
+	if err := FetchObject(c, obj.GetNamespace(), obj.GetName(), existing); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil // Nothing to upgrade, the object will be created by the apply
+		}
+		return err
+	}
+
+	legacy := -1
+	fields := existing.GetManagedFields()
+	for i, entry := range fields {
+		if entry.Manager == common.ArgoCDFieldManager && entry.Operation == metav1.ManagedFieldsOperationApply {
+			return nil // Already upgraded
+		}
+		if legacy < 0 && isLegacyEntry(entry, owner.UID) {
+			legacy = i
+		}
+	}
+
+	if legacy < 0 {
+		return nil // Not created by an earlier version of the operator
+	}
+
+	original := existing.DeepCopyObject().(client.Object)
+	fields[legacy].Manager = common.ArgoCDFieldManager
+	fields[legacy].Operation = metav1.ManagedFieldsOperationApply
+	existing.SetManagedFields(fields)
+	return c.Patch(context.TODO(), existing, client.MergeFrom(original))
+}
+
+// isLegacyEntry will return true if the given managed fields entry was recorded for a client-side update of an earlier
+// version of the operator, i.e. a legacy field manager update that set the controller reference with the given UID.
+func isLegacyEntry(entry metav1.ManagedFieldsEntry, uid types.UID) bool {
+	if entry.Manager != common.ArgoCDLegacyFieldManager || entry.Operation != metav1.ManagedFieldsOperationUpdate ||
+		entry.Subresource != "" || entry.FieldsV1 == nil {
+		return false
+	}
+
+	var fields struct {
+		Metadata struct {
+			OwnerReferences map[string]interface{} `json:"f:ownerReferences"`
+		} `json:"f:metadata"`
+	}
+	if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+		return false
+	}
+	_, ok := fields.Metadata.OwnerReferences[fmt.Sprintf(`k:{"uid":"%s"}`, uid)]
+	return ok
+}
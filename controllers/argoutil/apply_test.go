@@ -0,0 +1,232 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argoutil
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/argoproj-labs/argocd-operator/common"
+)
+
+func TestUpgradeManagedFields(t *testing.T) {
+	controller := true
+	owner := metav1.OwnerReference{
+		APIVersion: "argoproj.io/v1alpha1",
+		Kind:       "ArgoCD",
+		Name:       "argocd",
+		UID:        "9f3c4b5e-2a1d-4c7e-8b6f-1d2e3f4a5b6c",
+		Controller: &controller,
+	}
+	fieldsV1 := &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:kustomize.versionv4.1.0":{}},"f:metadata":{"f:ownerReferences":{".":{},"k:{\"uid\":\"9f3c4b5e-2a1d-4c7e-8b6f-1d2e3f4a5b6c\"}":{}}}}`)}
+	otherFieldsV1 := &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:example.setting":{}}}`)}
+
+	tests := []struct {
+		name        string
+		fields      []metav1.ManagedFieldsEntry
+		owners      []metav1.OwnerReference
+		wantManager string
+		wantOp      metav1.ManagedFieldsOperationType
+	}{
+		{
+			name: "legacy update",
+			fields: []metav1.ManagedFieldsEntry{
+				{Manager: common.ArgoCDLegacyFieldManager, Operation: metav1.ManagedFieldsOperationUpdate, FieldsV1: fieldsV1},
+			},
+			owners:      []metav1.OwnerReference{owner},
+			wantManager: common.ArgoCDFieldManager,
+			wantOp:      metav1.ManagedFieldsOperationApply,
+		},
+		{
+			name: "legacy status update",
+			fields: []metav1.ManagedFieldsEntry{
+				{Manager: common.ArgoCDLegacyFieldManager, Operation: metav1.ManagedFieldsOperationUpdate, Subresource: "status", FieldsV1: fieldsV1},
+			},
+			owners:      []metav1.OwnerReference{owner},
+			wantManager: common.ArgoCDLegacyFieldManager,
+			wantOp:      metav1.ManagedFieldsOperationUpdate,
+		},
+		{
+			name: "other controller with the legacy name",
+			fields: []metav1.ManagedFieldsEntry{
+				{Manager: common.ArgoCDLegacyFieldManager, Operation: metav1.ManagedFieldsOperationUpdate, FieldsV1: otherFieldsV1},
+			},
+			owners:      []metav1.OwnerReference{owner},
+			wantManager: common.ArgoCDLegacyFieldManager,
+			wantOp:      metav1.ManagedFieldsOperationUpdate,
+		},
+		{
+			name: "no controller reference",
+			fields: []metav1.ManagedFieldsEntry{
+				{Manager: common.ArgoCDLegacyFieldManager, Operation: metav1.ManagedFieldsOperationUpdate, FieldsV1: fieldsV1},
+			},
+			wantManager: common.ArgoCDLegacyFieldManager,
+			wantOp:      metav1.ManagedFieldsOperationUpdate,
+		},
+		{
+			name: "other manager",
+			fields: []metav1.ManagedFieldsEntry{
+				{Manager: "kubectl-edit", Operation: metav1.ManagedFieldsOperationUpdate, FieldsV1: fieldsV1},
+			},
+			owners:      []metav1.OwnerReference{owner},
+			wantManager: "kubectl-edit",
+			wantOp:      metav1.ManagedFieldsOperationUpdate,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            common.ArgoCDConfigMapName,
+					Namespace:       "argocd",
+					OwnerReferences: test.owners,
+					ManagedFields:   test.fields,
+				},
+			}
+			cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(cm).Build()
+
+			desired := &corev1.ConfigMap{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            common.ArgoCDConfigMapName,
+					Namespace:       "argocd",
+					OwnerReferences: test.owners,
+				},
+			}
+			assert.NoError(t, upgradeManagedFields(cl, desired))
+
+			upgraded := &corev1.ConfigMap{}
+			assert.NoError(t, FetchObject(cl, "argocd", common.ArgoCDConfigMapName, upgraded))
+			assert.Len(t, upgraded.ManagedFields, 1)
+			assert.Equal(t, test.wantManager, upgraded.ManagedFields[0].Manager)
+			assert.Equal(t, test.wantOp, upgraded.ManagedFields[0].Operation)
+		})
+	}
+}
+
+func TestUpgradeManagedFields_notFound(t *testing.T) {
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+
+	desired := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.ArgoCDConfigMapName,
+			Namespace: "argocd",
+		},
+	}
+	assert.NoError(t, upgradeManagedFields(cl, desired))
+}
+
+// conflictClient is a client that rejects applies that are not forced with the given field manager conflicts, as the
+// fake client does not support server-side apply. Forced applies create the object.
+type conflictClient struct {
+	client.Client
+	causes []metav1.StatusCause
+	forced bool
+}
+
+func (c *conflictClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	po := &client.PatchOptions{}
+	po.ApplyOptions(opts)
+	if po.Force == nil || !*po.Force {
+		if len(c.causes) > 0 {
+			return &apierrors.StatusError{ErrStatus: metav1.Status{
+				Status:  metav1.StatusFailure,
+				Code:    http.StatusConflict,
+				Reason:  metav1.StatusReasonConflict,
+				Details: &metav1.StatusDetails{Causes: c.causes},
+			}}
+		}
+	} else {
+		c.forced = true
+	}
+	return c.Create(ctx, obj)
+}
+
+func TestApplyObject(t *testing.T) {
+	tests := []struct {
+		name          string
+		causes        []metav1.StatusCause
+		wantConflicts []FieldConflict
+		wantForced    bool
+	}{
+		{
+			name: "no conflicts",
+		},
+		{
+			name: "conflicts",
+			causes: []metav1.StatusCause{
+				{Type: metav1.CauseTypeFieldManagerConflict, Message: `conflict with "kubectl-edit" using v1`, Field: ".data.admin.enabled"},
+			},
+			wantConflicts: []FieldConflict{{Field: ".data.admin.enabled", Manager: "kubectl-edit"}},
+			wantForced:    true,
+		},
+		{
+			name: "legacy conflicts",
+			causes: []metav1.StatusCause{
+				{Type: metav1.CauseTypeFieldManagerConflict, Message: `conflict with "manager" using v1`, Field: ".data.scopes"},
+			},
+			wantForced: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cl := &conflictClient{
+				Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
+				causes: test.causes,
+			}
+
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      common.ArgoCDConfigMapName,
+					Namespace: "argocd",
+				},
+				Data: map[string]string{"admin.enabled": "true"},
+			}
+			conflicts, err := ApplyObject(cl, cm)
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantConflicts, conflicts)
+			assert.Equal(t, test.wantForced, cl.forced)
+		})
+	}
+}
+
+func TestApplyObject_error(t *testing.T) {
+	cl := &conflictClient{
+		Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
+		causes: []metav1.StatusCause{{Type: metav1.CauseTypeFieldValueInvalid, Field: ".data"}},
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.ArgoCDConfigMapName,
+			Namespace: "argocd",
+		},
+	}
+	_, err := ApplyObject(cl, cm)
+	assert.True(t, apierrors.IsConflict(err))
+	assert.False(t, cl.forced)
+}
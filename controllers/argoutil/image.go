@@ -0,0 +1,117 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argoutil
+
+import (
+	"os"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/common"
+)
+
+// mirroredRegistries are the public registries whose images are pulled from the image registry of an ArgoCD instead.
+var mirroredRegistries = []string{
+	"docker.io",
+	"ghcr.io",
+	"index.docker.io",
+	"quay.io",
+	"registry-1.docker.io",
+}
+
+// ContainerImage returns the image reference for a component from the given image and version of its spec. When
+// neither is set, the image from the first of the given environment variables of the operator that is set is used,
+// e.g. ARGOCD_DEX_IMAGE or RELATED_IMAGE_DEX. Otherwise the given default image and version are used for the ones
+// that are not set.
+func ContainerImage(image string, version string, defaultImage string, defaultVersion string, envNames ...string) string {
+	if image == "" && version == "" {
+		for _, name := range envNames {
+			if e := os.Getenv(name); e != "" {
+				return e
+			}
+		}
+	}
+
+	if image == "" {
+		image = defaultImage
+	}
+	if version == "" {
+		version = defaultVersion
+	}
+	return CombineImageTag(image, version)
+}
+
+// ImageRegistry returns the registry that mirrors the public registries for the given ArgoCD, either from the spec or
+// from the environment of the operator. An empty string means that the images are pulled from their own registries.
+func ImageRegistry(cr *argoprojv1a1.ArgoCD) string {
+	if cr != nil && cr.Spec.ImageRegistry != "" {
+		return cr.Spec.ImageRegistry
+	}
+	return os.Getenv(common.ArgoCDImageRegistryEnvName)
+}
+
+// RewriteImageRegistry will replace the registry of the given image reference with the given registry, if the image
+// is hosted on one of the mirrored public registries. The repository and the tag or digest of the image are retained,
+// e.g. redis:6.2.4 becomes mirror.example.com/library/redis:6.2.4 for the mirror.example.com registry.
+func RewriteImageRegistry(image string, registry string) string {
+	registry = strings.TrimSuffix(registry, "/")
+	if registry == "" || image == "" {
+		return image
+	}
+
+	domain, repository := splitImageDomain(image)
+	for _, mirrored := range mirroredRegistries {
+		if domain == mirrored {
+			return registry + "/" + repository
+		}
+	}
+	return image
+}
+
+// ImagePullPolicy returns the first of the given pull policies that is set. When none is set, images referenced by
+// digest are pulled only if they are not present, as a digest always refers to the same image, and any other image is
+// always pulled.
+func ImagePullPolicy(image string, policies ...corev1.PullPolicy) corev1.PullPolicy {
+	for _, policy := range policies {
+		if policy != "" {
+			return policy
+		}
+	}
+	if strings.Contains(image, "@") {
+		return corev1.PullIfNotPresent
+	}
+	return corev1.PullAlways
+}
+
+// splitImageDomain will split the given image reference into the domain of its registry and the remainder, using the
+// same rules as the container runtimes. Images without a domain are hosted on Docker Hub, and the official images on
+// Docker Hub live in the library namespace.
+func splitImageDomain(image string) (string, string) {
+	i := strings.Index(image, "/")
+	if i < 0 {
+		return "docker.io", "library/" + image
+	}
+
+	domain := image[:i]
+	if !strings.ContainsAny(domain, ".:") && domain != "localhost" {
+		return "docker.io", image
+	}
+	if (domain == "docker.io" || domain == "index.docker.io" || domain == "registry-1.docker.io") && !strings.Contains(image[i+1:], "/") {
+		return domain, "library/" + image[i+1:]
+	}
+	return domain, image[i+1:]
+}
@@ -0,0 +1,103 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argoutil
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+	"github.com/argoproj-labs/argocd-operator/common"
+)
+
+func TestRewriteImageRegistry(t *testing.T) {
+	tests := []struct {
+		image    string
+		registry string
+		want     string
+	}{
+		{"quay.io/argoproj/argocd:v2.2.2", "mirror.example.com", "mirror.example.com/argoproj/argocd:v2.2.2"},
+		{"quay.io/argoproj/argocd@sha256:abcdef", "mirror.example.com/", "mirror.example.com/argoproj/argocd@sha256:abcdef"},
+		{"ghcr.io/dexidp/dex:v2.30.0", "mirror.example.com/ghcr", "mirror.example.com/ghcr/dexidp/dex:v2.30.0"},
+		{"redis:6.2.4-alpine", "mirror.example.com", "mirror.example.com/library/redis:6.2.4-alpine"},
+		{"grafana/grafana:7.1.1", "mirror.example.com", "mirror.example.com/grafana/grafana:7.1.1"},
+		{"docker.io/haproxy@sha256:abcdef", "mirror.example.com", "mirror.example.com/library/haproxy@sha256:abcdef"},
+		{"registry.redhat.io/rh-sso-7/sso75-openshift-rhel8:7.5", "mirror.example.com", "registry.redhat.io/rh-sso-7/sso75-openshift-rhel8:7.5"},
+		{"localhost:5000/argocd", "mirror.example.com", "localhost:5000/argocd"},
+		{"quay.io/argoproj/argocd:v2.2.2", "", "quay.io/argoproj/argocd:v2.2.2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			assert.Equal(t, tt.want, RewriteImageRegistry(tt.image, tt.registry))
+		})
+	}
+}
+
+func TestImageRegistry(t *testing.T) {
+	os.Setenv(common.ArgoCDImageRegistryEnvName, "operator.example.com")
+	defer os.Unsetenv(common.ArgoCDImageRegistryEnvName)
+
+	cr := &argoprojv1a1.ArgoCD{}
+	assert.Equal(t, "operator.example.com", ImageRegistry(cr))
+
+	// The registry of the instance takes precedence over the one of the operator
+	cr.Spec.ImageRegistry = "instance.example.com"
+	assert.Equal(t, "instance.example.com", ImageRegistry(cr))
+}
+
+func TestImagePullPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		image    string
+		policies []corev1.PullPolicy
+		want     corev1.PullPolicy
+	}{
+		{"digest", "quay.io/argoproj/argocd@sha256:abcdef", nil, corev1.PullIfNotPresent},
+		{"tag", "quay.io/argoproj/argocd:v2.3.3", nil, corev1.PullAlways},
+		{"unset policies", "quay.io/argoproj/argocd:v2.3.3", []corev1.PullPolicy{"", ""}, corev1.PullAlways},
+		{"first policy", "quay.io/argoproj/argocd@sha256:abcdef", []corev1.PullPolicy{corev1.PullNever, corev1.PullAlways}, corev1.PullNever},
+		{"second policy", "quay.io/argoproj/argocd@sha256:abcdef", []corev1.PullPolicy{"", corev1.PullAlways}, corev1.PullAlways},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ImagePullPolicy(tt.image, tt.policies...))
+		})
+	}
+}
+
+func TestContainerImage(t *testing.T) {
+	os.Setenv("RELATED_IMAGE_TEST", "related.example.com/test@sha256:abcdef")
+	defer os.Unsetenv("RELATED_IMAGE_TEST")
+
+	// The image from the environment is only used when neither the image nor the version is set
+	assert.Equal(t, "related.example.com/test@sha256:abcdef",
+		ContainerImage("", "", "quay.io/test", "v1.0.0", "TEST_IMAGE", "RELATED_IMAGE_TEST"))
+	assert.Equal(t, "quay.io/test:v2.0.0",
+		ContainerImage("", "v2.0.0", "quay.io/test", "v1.0.0", "TEST_IMAGE", "RELATED_IMAGE_TEST"))
+	assert.Equal(t, "example.com/test:v1.0.0",
+		ContainerImage("example.com/test", "", "quay.io/test", "v1.0.0", "TEST_IMAGE", "RELATED_IMAGE_TEST"))
+
+	// The first environment variable that is set takes precedence
+	os.Setenv("TEST_IMAGE", "env.example.com/test:v3.0.0")
+	defer os.Unsetenv("TEST_IMAGE")
+	assert.Equal(t, "env.example.com/test:v3.0.0",
+		ContainerImage("", "", "quay.io/test", "v1.0.0", "TEST_IMAGE", "RELATED_IMAGE_TEST"))
+
+	// The defaults are used when no environment variable is set
+	assert.Equal(t, "quay.io/test@sha256:123456", ContainerImage("", "", "quay.io/test", "sha256:123456"))
+}
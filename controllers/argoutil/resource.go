@@ -51,6 +51,18 @@ func CombineImageTag(img string, tag string) string {
 	return img // No tag, use default
 }
 
+// SplitImageTag will split the given image reference into the image and the tag or digest, it is the inverse of
+// CombineImageTag.
+func SplitImageTag(ref string) (string, string) {
+	if i := strings.Index(ref, "@"); i >= 0 {
+		return ref[:i], ref[i+1:] // Digest
+	}
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		return ref[:i], ref[i+1:] // Tag
+	}
+	return ref, "" // No tag
+}
+
 // CreateEvent will create a new Kubernetes Event with the given action, message, reason and involved uid.
 func CreateEvent(client client.Client, action string, message string, reason string, meta metav1.ObjectMeta) error {
 	event := newEvent(meta)
@@ -57,3 +57,28 @@ func TestDefaultAnnotations(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitImageTag(t *testing.T) {
+	tests := []struct {
+		ref string
+		img string
+		tag string
+	}{
+		{"quay.io/argoproj/argocd:v2.2.2", "quay.io/argoproj/argocd", "v2.2.2"},
+		{"quay.io/argoproj/argocd@sha256:abcdef", "quay.io/argoproj/argocd", "sha256:abcdef"},
+		{"localhost:5000/argocd", "localhost:5000/argocd", ""},
+		{"localhost:5000/argocd:latest", "localhost:5000/argocd", "latest"},
+		{"redis", "redis", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			img, tag := SplitImageTag(tt.ref)
+			if img != tt.img || tag != tt.tag {
+				t.Errorf("SplitImageTag() = %v, %v, want %v, %v", img, tag, tt.img, tt.tag)
+			}
+			if got := CombineImageTag(img, tag); got != tt.ref {
+				t.Errorf("CombineImageTag() = %v, want %v", got, tt.ref)
+			}
+		})
+	}
+}
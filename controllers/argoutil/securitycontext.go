@@ -0,0 +1,150 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argoutil
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+)
+
+// RestrictedPodSecurityContext returns the default pod security context that satisfies the restricted Pod Security
+// Standard.
+func RestrictedPodSecurityContext() *corev1.PodSecurityContext {
+	runAsNonRoot := true
+	return &corev1.PodSecurityContext{
+		RunAsNonRoot: &runAsNonRoot,
+		SeccompProfile: &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		},
+	}
+}
+
+// RestrictedSecurityContext returns the default container security context that satisfies the restricted Pod Security
+// Standard. The root filesystem is read-only if readOnlyRootFilesystem is true.
+func RestrictedSecurityContext(readOnlyRootFilesystem bool) *corev1.SecurityContext {
+	allowPrivilegeEscalation := false
+	return &corev1.SecurityContext{
+		AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		},
+		ReadOnlyRootFilesystem: &readOnlyRootFilesystem,
+	}
+}
+
+// ApplySecurityContext will set the default security contexts that satisfy the restricted Pod Security Standard on
+// the given pod spec and merge the given security context of the component into them. The defaults only fill in the
+// fields of the pod and container security contexts that are not set yet, so that e.g. a runAsUser or a writable root
+// filesystem configured by the component is retained.
+func ApplySecurityContext(spec *corev1.PodSpec, readOnlyRootFilesystem bool, securityContext *argoprojv1a1.ArgoCDSecurityContextSpec) error {
+	if spec.SecurityContext == nil {
+		spec.SecurityContext = &corev1.PodSecurityContext{}
+	}
+	setPodSecurityContextDefaults(spec.SecurityContext)
+
+	var podOverride *corev1.PodSecurityContext
+	var containerOverride *corev1.SecurityContext
+	if securityContext != nil {
+		podOverride = securityContext.Pod
+		containerOverride = securityContext.Container
+	}
+
+	if podOverride != nil {
+		merged := &corev1.PodSecurityContext{}
+		if err := mergeSecurityContext(spec.SecurityContext, podOverride, merged); err != nil {
+			return err
+		}
+		spec.SecurityContext = merged
+	}
+
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for i := range containers {
+			if containers[i].SecurityContext == nil {
+				containers[i].SecurityContext = &corev1.SecurityContext{}
+			}
+			setSecurityContextDefaults(containers[i].SecurityContext, readOnlyRootFilesystem)
+			if containerOverride != nil {
+				merged := &corev1.SecurityContext{}
+				if err := mergeSecurityContext(containers[i].SecurityContext, containerOverride, merged); err != nil {
+					return err
+				}
+				containers[i].SecurityContext = merged
+			}
+		}
+	}
+	return nil
+}
+
+// setPodSecurityContextDefaults will set the fields of the restricted pod security context that are not set in the
+// given pod security context.
+func setPodSecurityContextDefaults(securityContext *corev1.PodSecurityContext) {
+	restricted := RestrictedPodSecurityContext()
+	if securityContext.RunAsNonRoot == nil {
+		securityContext.RunAsNonRoot = restricted.RunAsNonRoot
+	}
+	if securityContext.SeccompProfile == nil {
+		securityContext.SeccompProfile = restricted.SeccompProfile
+	}
+}
+
+// setSecurityContextDefaults will set the fields of the restricted container security context that are not set in
+// the given container security context.
+func setSecurityContextDefaults(securityContext *corev1.SecurityContext, readOnlyRootFilesystem bool) {
+	restricted := RestrictedSecurityContext(readOnlyRootFilesystem)
+	if securityContext.AllowPrivilegeEscalation == nil {
+		securityContext.AllowPrivilegeEscalation = restricted.AllowPrivilegeEscalation
+	}
+	if securityContext.Capabilities == nil {
+		securityContext.Capabilities = restricted.Capabilities
+	} else if securityContext.Capabilities.Drop == nil {
+		securityContext.Capabilities.Drop = restricted.Capabilities.Drop
+	}
+	if securityContext.ReadOnlyRootFilesystem == nil {
+		securityContext.ReadOnlyRootFilesystem = restricted.ReadOnlyRootFilesystem
+	}
+}
+
+// FixedID returns the given uid or gid for images that don't run as a numeric non-root user on their own. It returns
+// nil on OpenShift, where the restricted SCC assigns the uid and gid from the range of the namespace.
+func FixedID(id int64) *int64 {
+	if IsOpenShiftCluster() {
+		return nil
+	}
+	return &id
+}
+
+// mergeSecurityContext will merge the fields that are set in the given override into the given original security
+// context and store the result in merged.
+func mergeSecurityContext(original interface{}, override interface{}, merged interface{}) error {
+	originalJSON, err := json.Marshal(original)
+	if err != nil {
+		return err
+	}
+	overrideJSON, err := json.Marshal(override)
+	if err != nil {
+		return err
+	}
+
+	patched, err := strategicpatch.StrategicMergePatch(originalJSON, overrideJSON, merged)
+	if err != nil {
+		return fmt.Errorf("failed to merge the security context: %w", err)
+	}
+	return json.Unmarshal(patched, merged)
+}
@@ -0,0 +1,105 @@
+// Copyright 2021 ArgoCD Operator Developers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package argoutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	argoprojv1a1 "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+)
+
+func TestApplySecurityContext(t *testing.T) {
+	runAsUser := int64(1000)
+	runAsNonRoot := false
+	readOnlyRootFilesystem := false
+	spec := corev1.PodSpec{
+		SecurityContext: &corev1.PodSecurityContext{RunAsUser: &runAsUser, RunAsNonRoot: &runAsNonRoot},
+		InitContainers: []corev1.Container{{
+			Name:            "init",
+			SecurityContext: &corev1.SecurityContext{ReadOnlyRootFilesystem: &readOnlyRootFilesystem},
+		}},
+		Containers: []corev1.Container{{Name: "main"}},
+	}
+	assert.NoError(t, ApplySecurityContext(&spec, true, nil))
+
+	// The fields of the pod security context that are already set are retained
+	want := RestrictedPodSecurityContext()
+	want.RunAsUser = &runAsUser
+	want.RunAsNonRoot = &runAsNonRoot
+	assert.Equal(t, want, spec.SecurityContext)
+
+	// The defaults are merged into the containers that already have a security context
+	assert.Equal(t, RestrictedSecurityContext(false), spec.InitContainers[0].SecurityContext)
+	assert.Equal(t, RestrictedSecurityContext(true), spec.Containers[0].SecurityContext)
+}
+
+func TestApplySecurityContext_capabilities(t *testing.T) {
+	spec := corev1.PodSpec{
+		Containers: []corev1.Container{{
+			Name: "main",
+			SecurityContext: &corev1.SecurityContext{
+				Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"NET_BIND_SERVICE"}},
+			},
+		}},
+	}
+	assert.NoError(t, ApplySecurityContext(&spec, true, nil))
+
+	want := RestrictedSecurityContext(true)
+	want.Capabilities.Add = []corev1.Capability{"NET_BIND_SERVICE"}
+	assert.Equal(t, want, spec.Containers[0].SecurityContext)
+}
+
+func TestApplySecurityContext_override(t *testing.T) {
+	fsGroup := int64(2000)
+	readOnlyRootFilesystem := false
+	spec := corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "main"}},
+	}
+	assert.NoError(t, ApplySecurityContext(&spec, true, &argoprojv1a1.ArgoCDSecurityContextSpec{
+		Container: &corev1.SecurityContext{
+			Capabilities: &corev1.Capabilities{
+				Add: []corev1.Capability{"NET_BIND_SERVICE"},
+			},
+			ReadOnlyRootFilesystem: &readOnlyRootFilesystem,
+		},
+		Pod: &corev1.PodSecurityContext{FSGroup: &fsGroup},
+	}))
+
+	// The fields that are set are merged into the defaults
+	wantPod := RestrictedPodSecurityContext()
+	wantPod.FSGroup = &fsGroup
+	assert.Equal(t, wantPod, spec.SecurityContext)
+
+	wantContainer := RestrictedSecurityContext(false)
+	wantContainer.Capabilities.Add = []corev1.Capability{"NET_BIND_SERVICE"}
+	assert.Equal(t, wantContainer, spec.Containers[0].SecurityContext)
+}
+
+func TestFixedID(t *testing.T) {
+	defer func(found bool) { openShiftAPIFound = found }(openShiftAPIFound)
+
+	openShiftAPIFound = false
+	id := FixedID(999)
+	if assert.NotNil(t, id) {
+		assert.Equal(t, int64(999), *id)
+	}
+
+	// The restricted SCC assigns the ids on OpenShift
+	openShiftAPIFound = true
+	assert.Nil(t, FixedID(999))
+}
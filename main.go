@@ -182,6 +182,19 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "ArgoCDExport")
 		os.Exit(1)
 	}
+
+	// Webhooks require a serving certificate, so they are only registered when explicitly enabled.
+	if strings.EqualFold(os.Getenv("ENABLE_WEBHOOKS"), "true") {
+		if err = (&argoprojiov1alpha1.ArgoCD{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ArgoCD")
+			os.Exit(1)
+		}
+		if err = (&argoprojiov1alpha1.ArgoCDExport{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ArgoCDExport")
+			os.Exit(1)
+		}
+		argocd.SetupDefaultingWebhookWithManager(mgr)
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {